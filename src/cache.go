@@ -0,0 +1,119 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"graphql-ts-generator/generator"
+)
+
+// Bump when the cache layout or the generated output format changes
+const cacheVersion = 3
+
+// Cache of a previous generation run, persisted between runs
+type Cache struct {
+	Version int               `json:"version"`
+	Files   map[string]string `json:"files"`   // schema file path -> content hash
+	Options string            `json:"options"` // hash of the generation options, target and generator version
+	Output  map[string]string `json:"output"`  // output file path -> content hash, one entry per split file
+}
+
+// Load the cache from disk. A missing or unreadable cache yields an empty one.
+func loadCache(path string) *Cache {
+	cache := &Cache{Version: cacheVersion, Files: make(map[string]string)}
+	if path == "" {
+		return cache
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+
+	var loaded Cache
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.Version != cacheVersion {
+		return cache
+	}
+	return &loaded
+}
+
+// Save the cache to disk. Does nothing if caching is disabled.
+func (c *Cache) save(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("could not write cache file %s: %v", path, err)
+	}
+	return nil
+}
+
+// Check whether the inputs and options match the cached run and the output files were left untouched
+func (c *Cache) upToDate(hashes map[string]string, options string) bool {
+	if len(c.Output) == 0 || c.Options != options || len(c.Files) != len(hashes) {
+		return false
+	}
+	for path, hash := range hashes {
+		if c.Files[path] != hash {
+			return false
+		}
+	}
+
+	for path, hash := range c.Output {
+		existing, err := os.ReadFile(path)
+		if err != nil || hashContent(existing) != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash the effective value of every flag, after the config file and the output overrides are applied,
+// with the generator version, so that a run with other options or another version is not skipped
+func optionsHash(flags *flag.FlagSet) string {
+	var options []byte
+	flags.VisitAll(func(f *flag.Flag) {
+		options = append(options, f.Name+"="+f.Value.String()+"\n"...)
+	})
+	return hashContent(append(options, "version="+generator.Version...))
+}
+
+// Hash the content of every output file as written on disk, e.g. once a post-generation hook formatted it
+func hashOutputs(paths []string) (map[string]string, error) {
+	hashes := make(map[string]string, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read output file %s: %v", path, err)
+		}
+		hashes[path] = hashContent(data)
+	}
+	return hashes, nil
+}
+
+// Hash the content of every schema file in the list
+func (f *schemaFlags) hashFiles(paths []string) (map[string]string, error) {
+	hashes := make(map[string]string, len(paths))
+	for _, path := range paths {
+		data, err := f.readSource(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read file %s: %v", path, err)
+		}
+		hashes[path] = hashContent(data)
+	}
+	return hashes, nil
+}
+
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
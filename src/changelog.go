@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// A type declared by a generated TypeScript file, with its members in declaration order:
+// the properties of interfaces, object types and classes, the values of enums, and the members of union types
+type tsDeclaration struct {
+	kind    string
+	members []tsMember
+	// Whether the declaration has type parameters, like the Nullable<T> helper
+	generic bool
+	// Types of the extends clause of interfaces and classes
+	extends []string
+	// Text of the JSDoc comment before the declaration
+	description string
+	// Order of the declaration in the files read
+	position int
+}
+
+type tsMember struct {
+	name string
+	// Property type with its optional marker, e.g. "?: Nullable<string>", empty for enum values and union members
+	signature string
+	// Initializer of an enum value, e.g. 'ADMIN'
+	value       string
+	description string
+}
+
+var (
+	// Start of an exported declaration, e.g. `export interface User extends Node {` or `export type Role = 'A' | 'B';`
+	tsDeclarationPattern = regexp.MustCompile(`^export (interface|type|enum|class) ([A-Za-z_$][\w$]*)(<[^=]*>)?(?: extends ([^{]*))?\s*(=\s*(.*)|\{)$`)
+	// Property of an interface, object type or class without its indentation, e.g. `email?: Nullable<string>;`
+	tsPropertyPattern = regexp.MustCompile(`^(?:readonly )?([A-Za-z_$][\w$]*|'[^']*')(\??)!?: (.+?)[;,]?$`)
+	// Value of an enum without its indentation, e.g. `ADMIN = 'ADMIN',`
+	tsEnumValuePattern = regexp.MustCompile(`^([A-Za-z_$][\w$]*|'[^']*')(?: = (.*?))?,?$`)
+)
+
+// Read the declarations of the generated TypeScript files. Missing files have no declarations,
+// so that the first generation lists every type as added.
+func readTypescriptModel(paths []string) (map[string]*tsDeclaration, error) {
+	model := make(map[string]*tsDeclaration)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		offset := len(model)
+		for name, decl := range parseTypescriptModel(string(decodeOutput(data))) {
+			decl.position += offset
+			model[name] = decl
+		}
+	}
+	return model, nil
+}
+
+// Return the TypeScript files of a previous split output: the files of the directory with the output extension
+func splitOutputFiles(dir string, extension string) []string {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*"+extension))
+	return paths
+}
+
+// Parse the type declarations of a file written by the generator, or written by hand in the same layout:
+// one member per line, all members indented alike, and declarations closed at the start of a line.
+// JSDoc comments before declarations and members are kept as their description.
+func parseTypescriptModel(content string) map[string]*tsDeclaration {
+	model := make(map[string]*tsDeclaration)
+	var current *tsDeclaration
+	var indent string
+	var comment []string
+	inComment := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "/**") {
+			comment, inComment = nil, true
+			trimmed = strings.TrimPrefix(trimmed, "/**")
+		}
+		if inComment {
+			text, closed := strings.CutSuffix(trimmed, "*/")
+			text = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text), "*"))
+			if text != "" && !strings.HasPrefix(text, "@") {
+				comment = append(comment, text)
+			}
+			inComment = !closed
+			continue
+		}
+		description := strings.Join(comment, "\n")
+		if trimmed != "" {
+			comment = nil
+		}
+
+		if current != nil {
+			if strings.HasPrefix(line, "}") {
+				current = nil
+				continue
+			}
+			if indent == "" && trimmed != "" {
+				indent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			}
+			member, found := strings.CutPrefix(line, indent)
+			if !found || indent == "" || strings.TrimLeft(member, " \t") != member {
+				continue
+			}
+			if current.kind == "enum" {
+				if match := tsEnumValuePattern.FindStringSubmatch(member); match != nil {
+					current.members = append(current.members, tsMember{name: match[1], value: match[2], description: description})
+				}
+			} else if match := tsPropertyPattern.FindStringSubmatch(member); match != nil {
+				current.members = append(current.members, tsMember{name: match[1], signature: match[2] + ": " + match[3], description: description})
+			}
+			continue
+		}
+
+		match := tsDeclarationPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		decl := &tsDeclaration{kind: match[1], generic: match[3] != "", description: description, position: len(model)}
+		for _, name := range strings.Split(match[4], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				decl.extends = append(decl.extends, name)
+			}
+		}
+		model[match[2]] = decl
+		alias := strings.TrimSpace(match[6])
+		switch {
+		case match[5] == "{" || alias == "{":
+			current, indent = decl, ""
+		case strings.HasSuffix(alias, ";"):
+			// Single-line alias: a union enum of string literals, or a union of types
+			decl.kind = "union"
+			members := strings.Split(strings.TrimSuffix(alias, ";"), " | ")
+			if strings.HasPrefix(members[0], "'") {
+				decl.kind = "enum"
+			}
+			for _, member := range members {
+				decl.members = append(decl.members, tsMember{name: strings.Trim(member, "'")})
+			}
+		}
+	}
+	return model
+}
+
+// Sections of a changelog, in the order they are written
+var changelogSections = []string{
+	"Added types",
+	"Removed types",
+	"Added fields",
+	"Removed fields",
+	"Changed fields",
+	"New enum values",
+	"Removed enum values",
+	"New union members",
+	"Removed union members",
+}
+
+// Describe the differences between two TypeScript models as Markdown, with a list per kind of change,
+// e.g. "- `User.email`: `string` → `Nullable<string>`", or "No changes." if the declarations are the same
+func typescriptChangelog(previous, current map[string]*tsDeclaration) string {
+	entries := make(map[string][]string)
+	add := func(section, format string, args ...any) {
+		entries[section] = append(entries[section], "- "+fmt.Sprintf(format, args...))
+	}
+
+	for _, name := range sortedNames(current) {
+		decl := current[name]
+		before, found := previous[name]
+		if !found {
+			add("Added types", "`%s` (%s)", name, decl.kind)
+			continue
+		}
+		members := make(map[string]string)
+		for _, member := range before.members {
+			members[member.name] = member.signature
+		}
+		for _, member := range decl.members {
+			signature, found := members[member.name]
+			delete(members, member.name)
+			switch {
+			case !found && decl.kind == "enum":
+				add("New enum values", "`%s.%s`", name, member.name)
+			case !found && decl.kind == "union":
+				add("New union members", "`%s` now includes `%s`", name, member.name)
+			case !found:
+				add("Added fields", "`%s.%s%s`", name, member.name, member.signature)
+			case signature != member.signature:
+				add("Changed fields", "`%s.%s`: `%s%s` → `%s%s`", name, member.name, member.name, signature, member.name, member.signature)
+			}
+		}
+		for _, member := range before.members {
+			if _, removed := members[member.name]; !removed {
+				continue
+			}
+			switch decl.kind {
+			case "enum":
+				add("Removed enum values", "`%s.%s`", name, member.name)
+			case "union":
+				add("Removed union members", "`%s` no longer includes `%s`", name, member.name)
+			default:
+				add("Removed fields", "`%s.%s%s`", name, member.name, member.signature)
+			}
+		}
+	}
+	for _, name := range sortedNames(previous) {
+		if _, found := current[name]; !found {
+			add("Removed types", "`%s` (%s)", name, previous[name].kind)
+		}
+	}
+
+	var b strings.Builder
+	for _, section := range changelogSections {
+		if len(entries[section]) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("### " + section + "\n" + strings.Join(entries[section], "\n") + "\n")
+	}
+	if b.Len() == 0 {
+		return "No changes.\n"
+	}
+	return b.String()
+}
+
+func sortedNames(model map[string]*tsDeclaration) []string {
+	names := make([]string, 0, len(model))
+	for name := range model {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write the changelog to a file, or to the standard output for "-"
+func writeChangelog(path string, changelog string) error {
+	if path == "-" {
+		_, err := os.Stdout.WriteString(changelog)
+		return err
+	}
+	if err := os.WriteFile(path, []byte(changelog), 0644); err != nil {
+		return fmt.Errorf("could not write changelog %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// Name of the installed command, as in the npm package bin
+const commandName = "generate-types"
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// A command and the registration of its flags, used to complete the command line
+type completionCommand struct {
+	name        string
+	description string
+	register    func(flags *flag.FlagSet)
+}
+
+func generateCommandFlags(flags *flag.FlagSet) {
+	registerSchemaFlags(flags)
+	registerGenerateFlags(flags)
+}
+
+var completionCommands = []completionCommand{
+	{"generate", "Generate the TypeScript file (default)", generateCommandFlags},
+	{"sdl", "Write all schema files merged into one normalized SDL file", generateCommandFlags},
+	{"docs", "Write a Markdown or HTML reference of the schema", generateCommandFlags},
+	{"graph", "Write a Graphviz or Mermaid graph of the references between types", generateCommandFlags},
+	{"changelog", "Generate the TypeScript file and print the changes since the previous output", generateCommandFlags},
+	{"reverse", "Write the GraphQL SDL of TypeScript interfaces, types and enums (experimental)", func(flags *flag.FlagSet) { registerReverseFlags(flags) }},
+	{"validate", "Check the schemas and run the lint rules without generating output", func(flags *flag.FlagSet) { registerSchemaFlags(flags) }},
+	{"validate-operations", "Check client operation documents against the merged schema", func(flags *flag.FlagSet) { registerValidateOperationsFlags(flags) }},
+	{"coverage", "Report the operations selecting each field of the schema", func(flags *flag.FlagSet) { registerCoverageFlags(flags) }},
+	{"deprecations", "Report the uses of deprecated fields and arguments by client operations", func(flags *flag.FlagSet) { registerDeprecationsFlags(flags) }},
+	{"test", "Compare the output of every fixture case with its golden file", func(flags *flag.FlagSet) { registerSnapshotFlags(flags) }},
+	{"complexity", "Report the fan-out of types and the nesting depth of root fields", func(flags *flag.FlagSet) { registerComplexityFlags(flags) }},
+	{"operations", "Write a .graphql operation document for each Query and Mutation field", func(flags *flag.FlagSet) { registerOperationsFlags(flags) }},
+	{"serve", "Serve the generator over HTTP for editors and playgrounds", func(flags *flag.FlagSet) { registerServeFlags(flags) }},
+	{"install-hook", "Write a git pre-commit hook checking the generated output", func(flags *flag.FlagSet) { registerInstallHookFlags(flags) }},
+	{"completion", "Print a bash, zsh or fish completion script", func(flags *flag.FlagSet) {}},
+}
+
+// Print the shell completion script of the commands and their flags
+func runCompletion(args []string) {
+	if len(args) != 1 {
+		log.Fatalf("Usage: %s completion %s", commandName, strings.Join(completionShells, "|"))
+	}
+	if err := writeCompletion(os.Stdout, args[0]); err != nil {
+		log.Fatalf("Error writing completion: %v", err)
+	}
+}
+
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return writeBashCompletion(w)
+	case "zsh":
+		return writeZshCompletion(w)
+	case "fish":
+		return writeFishCompletion(w)
+	}
+	return fmt.Errorf("unknown shell %q, expected %s", shell, strings.Join(completionShells, ", "))
+}
+
+// Return the flags of a command, in alphabetical order
+func commandFlags(command completionCommand) []*flag.Flag {
+	flags := flag.NewFlagSet(command.name, flag.ContinueOnError)
+	command.register(flags)
+	var result []*flag.Flag
+	flags.VisitAll(func(f *flag.Flag) { result = append(result, f) })
+	return result
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	value, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && value.IsBoolFlag()
+}
+
+func commandNames() []string {
+	var names []string
+	for _, command := range completionCommands {
+		names = append(names, command.name)
+	}
+	return names
+}
+
+// Quote a string for sh, zsh and fish single-quoted strings
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func writeBashCompletion(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", commandName)
+	b.WriteString("_generate_types() {\n")
+	b.WriteString("  local cur=${COMP_WORDS[COMP_CWORD]} command=generate words\n")
+	b.WriteString("  if [[ $COMP_CWORD -gt 1 && ${COMP_WORDS[1]} != -* ]]; then\n    command=${COMP_WORDS[1]}\n  fi\n")
+	fmt.Fprintf(&b, "  if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then\n    COMPREPLY=($(compgen -W %s -- \"$cur\"))\n    return\n  fi\n",
+		shellQuote(strings.Join(commandNames(), " ")))
+	b.WriteString("  case $command in\n")
+	for _, command := range completionCommands {
+		var names []string
+		for _, f := range commandFlags(command) {
+			names = append(names, "-"+f.Name)
+		}
+		if command.name == "completion" {
+			names = completionShells
+		}
+		fmt.Fprintf(&b, "    %s) words=%s ;;\n", command.name, shellQuote(strings.Join(names, " ")))
+	}
+	b.WriteString("  esac\n")
+	b.WriteString("  if [[ $cur == -* || $command == completion ]]; then\n    COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	b.WriteString("  else\n    COMPREPLY=($(compgen -f -- \"$cur\"))\n  fi\n}\n")
+	fmt.Fprintf(&b, "complete -o filenames -F _generate_types %s\n", commandName)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeZshCompletion(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n\n", commandName)
+	b.WriteString("_generate_types() {\n  local -a commands flags\n  local command=generate\n  commands=(\n")
+	for _, command := range completionCommands {
+		fmt.Fprintf(&b, "    %s\n", shellQuote(command.name+":"+command.description))
+	}
+	b.WriteString("  )\n")
+	b.WriteString("  if (( CURRENT == 2 )) && [[ $words[CURRENT] != -* ]]; then\n    _describe command commands\n    return\n  fi\n")
+	b.WriteString("  [[ $words[2] != -* ]] && command=$words[2]\n")
+	b.WriteString("  case $command in\n")
+	for _, command := range completionCommands {
+		if command.name == "completion" {
+			fmt.Fprintf(&b, "    completion) _values shell %s; return ;;\n", strings.Join(completionShells, " "))
+			continue
+		}
+		fmt.Fprintf(&b, "    %s) flags=(", command.name)
+		for _, f := range commandFlags(command) {
+			fmt.Fprintf(&b, "\n      %s", shellQuote("-"+f.Name+":"+f.Usage))
+		}
+		b.WriteString("\n    ) ;;\n")
+	}
+	b.WriteString("  esac\n")
+	b.WriteString("  if [[ $words[CURRENT] == -* ]]; then\n    _describe flag flags\n  else\n    _files\n  fi\n}\n\n")
+	fmt.Fprintf(&b, "compdef _generate_types %s\n", commandName)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeFishCompletion(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", commandName)
+	names := strings.Join(commandNames(), " ")
+	for _, command := range completionCommands {
+		fmt.Fprintf(&b, "complete -c %s -n '__fish_use_subcommand' -f -a %s -d %s\n",
+			commandName, command.name, shellQuote(command.description))
+	}
+	for _, command := range completionCommands {
+		condition := "__fish_seen_subcommand_from " + command.name
+		if command.name == "generate" {
+			// Generate is also the command run without a subcommand
+			condition = "not __fish_seen_subcommand_from " + strings.TrimPrefix(names, "generate ")
+		}
+		if command.name == "completion" {
+			fmt.Fprintf(&b, "complete -c %s -n %s -f -a %s\n",
+				commandName, shellQuote(condition), shellQuote(strings.Join(completionShells, " ")))
+			continue
+		}
+		for _, f := range commandFlags(command) {
+			required := " -r"
+			if isBoolFlag(f) {
+				required = ""
+			}
+			fmt.Fprintf(&b, "complete -c %s -n %s -o %s%s -d %s\n",
+				commandName, shellQuote(condition), f.Name, required, shellQuote(f.Usage))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
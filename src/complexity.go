@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+)
+
+func registerComplexityFlags(flags *flag.FlagSet) (*schemaFlags, *int) {
+	schemaOpts := registerSchemaFlags(flags)
+	return schemaOpts, flags.Int("top", 20, "Number of types with the highest fan-out to show, 0 shows all")
+}
+
+// Report the fan-out of the types and the nesting depth and reachable types of the root fields
+func runComplexity(args []string) {
+	flags := flag.NewFlagSet("complexity", flag.ExitOnError)
+	schemaOpts, top := registerComplexityFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := schemaOpts.context()
+	defer cancel()
+
+	gen, err := schemaOpts.loadSchema(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	report := gen.Complexity()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\nROOT FIELD\tDEPTH\tREACHABLE TYPES")
+	for _, field := range report.RootFields {
+		depth := fmt.Sprint(field.Depth)
+		if field.Cyclic {
+			depth += "+ (cyclic)"
+		}
+		fmt.Fprintf(w, "%s.%s\t%s\t%d\n", field.Root, field.Field, depth, field.ReachableTypes)
+	}
+
+	types := report.Types
+	if *top > 0 && len(types) > *top {
+		types = types[:*top]
+	}
+	fmt.Fprintln(w, "\nTYPE\tFAN-OUT")
+	for _, typ := range types {
+		fmt.Fprintf(w, "%s\t%d\n", typ.Name, typ.FanOut)
+	}
+	w.Flush()
+
+	fmt.Printf("\nReachable from root fields: %d of %d types\n", report.ReachableTypes, report.TotalTypes)
+}
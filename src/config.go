@@ -0,0 +1,291 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Find the -config argument, if any, without parsing the other flags
+func configPath(args []string) string {
+	return argValue(args, "config")
+}
+
+// Find the value of a flag in the arguments, without parsing the other flags
+func argValue(args []string, flagName string) string {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != flagName {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// Check whether a boolean flag is set in the arguments, without parsing the other flags
+func argBool(args []string, flagName string) bool {
+	for _, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != flagName {
+			continue
+		}
+		if !hasValue {
+			return true
+		}
+		set, _ := strconv.ParseBool(value)
+		return set
+	}
+	return false
+}
+
+// Config key listing the outputs of a multi-output config
+const outputsKey = "outputs"
+
+// Config keys holding the named profiles and the profile used when -profile is not given
+const (
+	profilesKey = "profiles"
+	profileKey  = "profile"
+)
+
+// Config key holding the named projects, the flag selecting one of them, and the flag generating all of them
+const (
+	projectsKey     = "projects"
+	projectKey      = "project"
+	allProjectsFlag = "all"
+)
+
+// Load the JSON config file named by -config, or return nil if there is none.
+// The options of the selected project, then of the selected profile, replace the base options of the same name.
+func loadConfig(args []string) (map[string]any, error) {
+	path := configPath(args)
+	config, err := readConfig(path)
+	if err != nil || config == nil {
+		return nil, err
+	}
+	if err := applyProject(config, argValue(args, projectKey)); err != nil {
+		return nil, fmt.Errorf("config file %s: %v", path, err)
+	}
+	if err := applyProfile(config, argValue(args, profileKey)); err != nil {
+		return nil, fmt.Errorf("config file %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// Read a JSON config file, or return nil if the path is empty
+func readConfig(path string) (map[string]any, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file %s: %v", path, err)
+	}
+	var config map[string]any
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("could not parse config file %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// Merge the options of the named project into the base config. A project holds its own sources, filters
+// and outputs, on top of the options shared by all projects.
+func applyProject(config map[string]any, name string) error {
+	projects, ok := config[projectsKey].(map[string]any)
+	if config[projectsKey] != nil && !ok {
+		return fmt.Errorf("%q must be an object of projects", projectsKey)
+	}
+	delete(config, projectsKey)
+	if name == "" {
+		return nil
+	}
+
+	project, ok := projects[name].(map[string]any)
+	if !ok {
+		return fmt.Errorf("unknown project %q", name)
+	}
+	for key, value := range project {
+		config[key] = value
+	}
+	return nil
+}
+
+// Return the sorted names of the projects of the config file named by -config
+func configProjects(args []string) ([]string, error) {
+	config, err := readConfig(configPath(args))
+	if err != nil {
+		return nil, err
+	}
+	projects, ok := config[projectsKey].(map[string]any)
+	if config[projectsKey] != nil && !ok {
+		return nil, fmt.Errorf("config file %s: %q must be an object of projects", configPath(args), projectsKey)
+	}
+	return sortedConfigKeys(projects), nil
+}
+
+// Merge the named profile into the base config, defaulting to the "profile" key of the config
+func applyProfile(config map[string]any, name string) error {
+	profiles, ok := config[profilesKey].(map[string]any)
+	if config[profilesKey] != nil && !ok {
+		return fmt.Errorf("%q must be an object of profiles", profilesKey)
+	}
+	delete(config, profilesKey)
+	if name == "" {
+		name, _ = config[profileKey].(string)
+	}
+	if name == "" {
+		return nil
+	}
+
+	profile, ok := profiles[name].(map[string]any)
+	if !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	for key, value := range profile {
+		config[key] = value
+	}
+	config[profileKey] = name
+	return nil
+}
+
+// Load the JSON config file named by -config and apply its values as flag defaults.
+// Config keys are flag names, so every flag can be set from the config file.
+// Flags given on the command line take precedence over the config file.
+func applyConfigFile(flags *flag.FlagSet, args []string) error {
+	config, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+	delete(config, outputsKey)
+	if err := applyConfigValues(flags, config); err != nil {
+		return fmt.Errorf("config file %s: %v", configPath(args), err)
+	}
+	return nil
+}
+
+// Apply config values as flag values
+func applyConfigValues(flags *flag.FlagSet, config map[string]any) error {
+	for _, key := range sortedConfigKeys(config) {
+		if flags.Lookup(key) == nil {
+			return fmt.Errorf("unknown option %q", key)
+		}
+		if err := setConfigValue(flags, key, config[key]); err != nil {
+			return fmt.Errorf("option %q: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// Return the entries of the "outputs" list of the config file. Each entry holds
+// the options of one output, applied on top of the options shared at the top level.
+func configOutputs(args []string) ([]map[string]any, error) {
+	config, err := loadConfig(args)
+	if err != nil || config[outputsKey] == nil {
+		return nil, err
+	}
+
+	entries, ok := config[outputsKey].([]any)
+	if !ok {
+		return nil, fmt.Errorf("config file %s: %q must be a list of objects", configPath(args), outputsKey)
+	}
+	outputs := make([]map[string]any, len(entries))
+	for i, entry := range entries {
+		output, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("config file %s: %s[%d] must be an object", configPath(args), outputsKey, i)
+		}
+		outputs[i] = output
+	}
+	return outputs, nil
+}
+
+// Set a flag from a JSON value. Objects set Name=Value pairs, arrays are joined with commas.
+func setConfigValue(flags *flag.FlagSet, key string, value any) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range sortedConfigKeys(v) {
+			item, err := configString(v[name])
+			if err != nil {
+				return err
+			}
+			if err := flags.Set(key, name+"="+item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			str, err := configString(item)
+			if err != nil {
+				return err
+			}
+			items[i] = str
+		}
+		return flags.Set(key, strings.Join(items, ","))
+	default:
+		str, err := configString(v)
+		if err != nil {
+			return err
+		}
+		return flags.Set(key, str)
+	}
+}
+
+// Convert a scalar JSON value to its flag representation
+func configString(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return expandEnv(v)
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", value)
+	}
+}
+
+// Reference to an environment variable in a config value, as ${NAME} or ${NAME:-default}
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// Replace the environment variable references of a config value, so the same config
+// works across environments. A variable without default must be set.
+func expandEnv(value string) (string, error) {
+	var missing []string
+	expanded := envReference.ReplaceAllStringFunc(value, func(ref string) string {
+		match := envReference.FindStringSubmatch(ref)
+		if env, ok := os.LookupEnv(match[1]); ok && (env != "" || match[2] == "") {
+			return env
+		}
+		if match[2] != "" {
+			return match[3]
+		}
+		missing = append(missing, match[1])
+		return ref
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+func sortedConfigKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
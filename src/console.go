@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"graphql-ts-generator/generator"
+)
+
+// ANSI colors of the console messages
+const (
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorGreen  = "\x1b[32m"
+	colorReset  = "\x1b[0m"
+)
+
+// Console of a command: the messages, progress and debug output, and the log file they are copied to
+type console struct {
+	// Disable colors even when writing to a terminal
+	noColor bool
+	// Whether the progress messages go to stderr, when stdout is the machine-readable output of the command
+	progressOnStderr bool
+	// File receiving the full debug output with -log-file. It is opened once per run,
+	// so that every output of a multi-output config is logged to it.
+	logFile *os.File
+}
+
+// Whether messages written to the file are colored: the file must be a terminal,
+// and colors must not be disabled with -no-color or a non-empty NO_COLOR variable
+func (c *console) useColor(file *os.File) bool {
+	if c.noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (c *console) colorize(file *os.File, color, text string) string {
+	if !c.useColor(file) {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// Print a success message in green
+func (c *console) printSuccess(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	out := os.Stdout
+	if c.progressOnStderr {
+		out = os.Stderr
+	}
+	fmt.Fprintln(out, c.colorize(out, colorGreen, message))
+	c.logMessage(message)
+}
+
+// Print a warning in yellow
+func (c *console) printWarning(message any) {
+	fmt.Fprintln(os.Stderr, c.colorize(os.Stderr, colorYellow, fmt.Sprint(message)))
+	c.logMessage(fmt.Sprint(message))
+}
+
+// Print an error in red
+func (c *console) printError(message any) {
+	fmt.Fprintln(os.Stderr, c.colorize(os.Stderr, colorRed, fmt.Sprint(message)))
+	c.logMessage(fmt.Sprint(message))
+}
+
+// Print a lint issue in the color of its severity
+func (c *console) printLintIssue(issue generator.LintIssue) {
+	if issue.Severity == generator.SeverityError {
+		c.printError(issue)
+	} else {
+		c.printWarning(issue)
+	}
+}
+
+// Output of the log package, which only reports fatal errors, colored red
+type errorWriter struct {
+	file    *os.File
+	console *console
+}
+
+func (w errorWriter) Write(p []byte) (int, error) {
+	if !w.console.useColor(w.file) {
+		return w.file.Write(p)
+	}
+	text := strings.TrimSuffix(string(p), "\n")
+	if _, err := fmt.Fprintf(w.file, "%s%s%s\n", colorRed, text, colorReset); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
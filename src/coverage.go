@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func registerCoverageFlags(flags *flag.FlagSet) (*schemaFlags, *string, *bool) {
+	schemaOpts := registerSchemaFlags(flags)
+	operations := flags.String("operations", "./operations", "Directory of the .graphql operation documents of the clients, searched recursively")
+	unused := flags.Bool("unused", false, "Only list the fields that no operation selects")
+	return schemaOpts, operations, unused
+}
+
+// Report the operations selecting each field of the schema, and the fields no operation selects
+func runCoverage(args []string) {
+	flags := flag.NewFlagSet("coverage", flag.ExitOnError)
+	schemaOpts, operations, unused := registerCoverageFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := schemaOpts.context()
+	defer cancel()
+
+	gen, err := schemaOpts.loadSchema(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sources, err := readOperations(*operations)
+	if err != nil {
+		log.Fatalf("Error reading operations: %v", err)
+	}
+	usages, err := gen.FieldUsages(ctx, sources)
+	if err != nil {
+		log.Fatalf("Error reporting field usage: %v", err)
+	}
+
+	selected := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\nFIELD\tOPERATIONS")
+	for _, usage := range usages {
+		if len(usage.Operations) > 0 {
+			selected++
+			if *unused {
+				continue
+			}
+		}
+		operations := strings.Join(usage.Operations, ", ")
+		if operations == "" {
+			operations = "-"
+		}
+		fmt.Fprintf(w, "%s.%s\t%s\n", usage.Type, usage.Field, operations)
+	}
+	w.Flush()
+
+	percent := 100.0
+	if len(usages) > 0 {
+		percent = float64(selected) * 100 / float64(len(usages))
+	}
+	fmt.Printf("\nFields selected by %d document(s): %d of %d (%.1f%%)\n", len(sources), selected, len(usages), percent)
+}
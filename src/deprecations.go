@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+type deprecationReport struct {
+	Documents int                `json:"documents"`
+	Usages    []deprecationUsage `json:"usages"`
+}
+
+type deprecationUsage struct {
+	Field      string `json:"field"`
+	Argument   string `json:"argument,omitempty"`
+	Reason     string `json:"reason"`
+	Definition string `json:"definition"`
+	Position   string `json:"position"`
+}
+
+func registerDeprecationsFlags(flags *flag.FlagSet) (*schemaFlags, *string, *string, *bool) {
+	schemaOpts := registerSchemaFlags(flags)
+	operations := flags.String("operations", "./operations", "Directory of the .graphql operation documents of the clients, searched recursively")
+	format := flags.String("format", "text", "Report format: text or json")
+	fail := flags.Bool("fail", false, "Exit with an error when an operation uses a deprecated field or argument, e.g. in CI")
+	return schemaOpts, operations, format, fail
+}
+
+// Report every use of a deprecated field or argument by the client operations
+func runDeprecations(args []string) {
+	flags := flag.NewFlagSet("deprecations", flag.ExitOnError)
+	schemaOpts, operations, format, fail := registerDeprecationsFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		log.Fatal(err)
+	}
+	if *format != "text" && *format != "json" {
+		log.Fatalf("Invalid -format value %q: expected text or json", *format)
+	}
+	schemaOpts.console.progressOnStderr = *format == "json"
+
+	ctx, cancel := schemaOpts.context()
+	defer cancel()
+
+	gen, err := schemaOpts.loadSchema(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sources, err := readOperations(*operations)
+	if err != nil {
+		log.Fatalf("Error reading operations: %v", err)
+	}
+	usages, err := gen.DeprecatedUsages(ctx, sources)
+	if err != nil {
+		log.Fatalf("Error reporting deprecated usage: %v", err)
+	}
+
+	report := deprecationReport{Documents: len(sources), Usages: []deprecationUsage{}}
+	for _, usage := range usages {
+		report.Usages = append(report.Usages, deprecationUsage{
+			Field:      usage.Type + "." + usage.Field,
+			Argument:   usage.Argument,
+			Reason:     usage.Reason,
+			Definition: usage.Definition,
+			Position:   fmt.Sprintf("%s:%d:%d", usage.File, usage.Line, usage.Column),
+		})
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Error writing report: %v", err)
+		}
+	} else {
+		for _, usage := range report.Usages {
+			printDeprecationUsage(schemaOpts.console, usage, *fail)
+		}
+	}
+
+	if len(usages) > 0 && *fail {
+		log.Fatalf("Deprecation check failed: %d use(s) of deprecated fields or arguments found", len(usages))
+	}
+	if *format == "text" {
+		schemaOpts.console.printSuccess("Deprecation check completed: %d use(s) of deprecated fields or arguments in %d document(s).", len(usages), len(sources))
+	}
+}
+
+// Print a deprecated usage, as an error when it fails the command
+func printDeprecationUsage(out *console, usage deprecationUsage, fail bool) {
+	field := usage.Field
+	if usage.Argument != "" {
+		field += "(" + usage.Argument + ":)"
+	}
+	message := fmt.Sprintf("%s: %s uses deprecated %s: %s", usage.Position, usage.Definition, field, usage.Reason)
+	if fail {
+		out.printError(message)
+	} else {
+		out.printWarning(message)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Prefix of the first line of generated files, followed by the hash of the rest of the generated content
+const contentHashPrefix = "// @generated sha256="
+
+// Return the content-hash line of generated content
+func contentHashLine(content []byte) string {
+	return contentHashPrefix + hashContent(content) + "\n"
+}
+
+// Check whether generated content, without its custom regions, no longer matches its embedded content hash.
+// Files without a content hash are never reported.
+func manuallyEdited(generated []byte) bool {
+	firstLine, rest, _ := bytes.Cut(generated, []byte("\n"))
+	expected, found := strings.CutPrefix(string(firstLine), contentHashPrefix)
+	if !found {
+		return false
+	}
+	return hashContent(rest) != expected
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Line endings of the written output files
+const (
+	lineEndingsLF   = "lf"
+	lineEndingsCRLF = "crlf"
+)
+
+// Byte order mark starting UTF-8 files for editors that expect one
+var utf8BOM = []byte("\uFEFF")
+
+// Check that the configured line endings are known
+func checkLineEndings(lineEndings string) error {
+	switch lineEndings {
+	case "", lineEndingsLF, lineEndingsCRLF:
+		return nil
+	}
+	return fmt.Errorf("unknown line endings %q, expected lf or crlf", lineEndings)
+}
+
+// Convert output content, generated with LF line endings, to the configured line endings and byte order mark
+func encodeOutput(content []byte, opts outputOptions) []byte {
+	if opts.lineEndings == lineEndingsCRLF {
+		content = bytes.ReplaceAll(content, []byte("\n"), []byte("\r\n"))
+	}
+	if opts.bom {
+		content = append(append([]byte{}, utf8BOM...), content...)
+	}
+	return content
+}
+
+// Return a writer converting the output content written to it, generated with LF line endings,
+// to the configured line endings
+func newLineEndingsWriter(w io.Writer, lineEndings string) io.Writer {
+	if lineEndings == lineEndingsCRLF {
+		return crlfWriter{w}
+	}
+	return w
+}
+
+// Writer replacing LF line endings with CRLF
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		line, rest, found := bytes.Cut(p, []byte("\n"))
+		if !found {
+			n, err := c.w.Write(p)
+			return written + n, err
+		}
+		if _, err := c.w.Write(line); err != nil {
+			return written, err
+		}
+		if _, err := io.WriteString(c.w, "\r\n"); err != nil {
+			return written, err
+		}
+		written += len(line) + 1
+		p = rest
+	}
+	return written, nil
+}
+
+// Remove the byte order mark and the CRLF line endings of an existing output file, so that its content hash
+// and custom regions compare with generated content whatever the configured encoding, or a checkout converting it
+func decodeOutput(data []byte) []byte {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+}
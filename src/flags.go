@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Flag collecting repeated Name=Value pairs
+type mapFlag map[string]string
+
+// Pairs are sorted, so that the value is stable across runs, e.g. in the options hash of the cache
+func (m mapFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for key, value := range m {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m mapFlag) Set(value string) error {
+	key, val, found := strings.Cut(value, "=")
+	if !found || key == "" {
+		return fmt.Errorf("expected Name=Value, got %q", value)
+	}
+	m[key] = val
+	return nil
+}
+
+// Flag enabled alone like a boolean, or with a comma-separated list of values restricting it,
+// e.g. -fail-on-warn or -fail-on-warn=unmapped-scalar,orphan-types
+type optionalListFlag struct {
+	enabled bool
+	// Values the flag is restricted to, all values if empty
+	values []string
+}
+
+func (f *optionalListFlag) String() string {
+	if f.enabled && len(f.values) == 0 {
+		return "true"
+	}
+	return strings.Join(f.values, ",")
+}
+
+func (f *optionalListFlag) Set(value string) error {
+	switch value {
+	case "true":
+		f.enabled, f.values = true, nil
+	case "false":
+		f.enabled, f.values = false, nil
+	default:
+		f.values = splitList(value)
+		f.enabled = len(f.values) > 0
+	}
+	return nil
+}
+
+func (f *optionalListFlag) IsBoolFlag() bool {
+	return true
+}
+
+// Check whether the flag is enabled for a value
+func (f *optionalListFlag) includes(value string) bool {
+	if !f.enabled || len(f.values) == 0 {
+		return f.enabled
+	}
+	for _, v := range f.values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Split a comma-separated flag value, ignoring empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Generate an input type as a class. Fields with a default value are initialized to it, required fields
+// without default are definitely assigned by the caller, and the constructor copies the given fields,
+// so that omitted fields keep their defaults.
+// A field named constructor, even quoted, is a syntax error in a class, so it must be renamed.
+func (g *Generator) writeInputClass(file *bufio.Writer, input *ast.Definition) error {
+	name := g.tsName(input.Name)
+	for _, field := range input.Fields {
+		if property := g.propertyName(field); property == "constructor" || property == "'constructor'" {
+			return fmt.Errorf("%s.%s cannot be a field of the %s class, as classes reserve constructor; rename it with @tsName or -reserved-fields rename, or disable input classes",
+				input.Name, field.Name, name)
+		}
+	}
+
+	file.WriteString(fmt.Sprintf("export class %s {\n", name))
+	for _, field := range input.Fields {
+		writeDocComment(file, "  ", g.fieldDocLines(field))
+		property := g.propertyName(field)
+		fieldType := g.fieldType(input.Name, field)
+		if !field.Type.NonNull {
+			property += "?"
+			fieldType = g.inputNullable(fieldType)
+		} else if field.DefaultValue == nil {
+			property += "!"
+		}
+		if field.DefaultValue != nil {
+			file.WriteString(fmt.Sprintf("  %s: %s = %s;\n", property, fieldType, g.tsValue(field.DefaultValue, field.Type)))
+		} else {
+			file.WriteString(fmt.Sprintf("  %s: %s;\n", property, fieldType))
+		}
+	}
+	file.WriteString(fmt.Sprintf("\n  constructor(init?: Partial<%s>) {\n    Object.assign(this, init);\n  }\n}\n\n", name))
+	return nil
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Helper types declared by the typescript plugin of graphql-codegen, which consuming code may reference
+const codegenHelpers = `export type Maybe<T> = T | null;
+export type InputMaybe<T> = Maybe<T>;
+export type Exact<T extends { [key: string]: unknown }> = { [K in keyof T]: T[K] };
+export type MakeOptional<T, K extends keyof T> = Omit<T, K> & { [SubKey in K]?: Maybe<T[SubKey]> };
+export type MakeMaybe<T, K extends keyof T> = Omit<T, K> & { [SubKey in K]: Maybe<T[SubKey]> };
+export type MakeEmpty<T extends { [key: string]: unknown }, K extends keyof T> = { [_ in K]?: never };
+export type Incremental<T> = T | { [P in keyof T]?: P extends ' $fragmentName' | '__typename' ? T[P] : never };
+
+`
+
+// Names of the codegen helper types
+var codegenHelperNames = []string{"Maybe", "InputMaybe", "Exact", "MakeOptional", "MakeMaybe", "MakeEmpty", "Incremental"}
+
+// Check whether the output follows the conventions of graphql-codegen
+func (g *Generator) codegen() bool {
+	return g.opts.Preset == PresetCodegen
+}
+
+// Generate the codegen helper types. With the StrictTS option, Maybe also accepts an explicit undefined.
+func (g *Generator) writeCodegenHelpers(file *bufio.Writer) {
+	if g.opts.StrictTS {
+		file.WriteString(strings.Replace(codegenHelpers, "Maybe<T> = T | null;", "Maybe<T> = T | null | undefined;", 1))
+		return
+	}
+	file.WriteString(codegenHelpers)
+}
+
+// Generate the Scalars record of codegen, with the input and output type of every scalar,
+// e.g. Scalars['ID']['input']
+func (g *Generator) writeCodegenScalars(file *bufio.Writer) {
+	file.WriteString("export type Scalars = {\n")
+	for _, name := range builtInScalars {
+		tsType, _ := g.scalarType(name)
+		file.WriteString(fmt.Sprintf("  %s: { input: %s; output: %s; }\n", name, tsType, tsType))
+	}
+	for _, name := range orderedKeys(g, g.schema.Scalars, "") {
+		if tsType, found := g.scalarType(name); found {
+			file.WriteString(fmt.Sprintf("  %s: { input: %s; output: %s; }\n", name, tsType, tsType))
+		}
+	}
+	file.WriteString("};\n\n")
+}
+
+// Return the opening of the declaration of an object, input or arguments type: an interface, or a type alias
+// with the codegen preset, whose implicit index signature satisfies constraints such as Exact<T>
+func (g *Generator) openDeclaration(name string) string {
+	if g.codegen() {
+		return fmt.Sprintf("export type %s = {\n", name)
+	}
+	return fmt.Sprintf("export interface %s {\n", name)
+}
+
+// Return the end of a declaration started by openDeclaration
+func (g *Generator) closeDeclaration() string {
+	if g.codegen() {
+		return "};\n\n"
+	}
+	return "}\n\n"
+}
+
+// Generate the optional __typename of an object type, declared by codegen so that object literals can set it
+func (g *Generator) writeTypename(file *bufio.Writer, name string) {
+	if g.codegen() {
+		file.WriteString(fmt.Sprintf("  __typename?: %s;\n", quoteString(name)))
+	}
+}
+
+// Generate a union type of the selected members of each union, e.g. export type SearchResult = Post | User
+func (g *Generator) writeUnions(file *bufio.Writer, selected map[string]bool) {
+	for _, name := range orderedKeys(g, g.schema.Unions, "") {
+		if selected != nil && !selected[name] {
+			continue
+		}
+		var members []string
+		for _, member := range g.schema.Unions[name].Types {
+			if !g.isExcluded(member) && (selected == nil || selected[member]) {
+				members = append(members, g.tsName(member))
+			}
+		}
+		if len(members) == 0 {
+			members = []string{"never"}
+		}
+		file.WriteString(fmt.Sprintf("export type %s = %s;\n\n", g.tsName(name), strings.Join(members, " | ")))
+	}
+}
+
+// Return the name of an enum member: the GraphQL value, or its PascalCase form with the codegen preset
+func (g *Generator) enumMemberName(value string) string {
+	if g.codegen() {
+		return toPascalCase(value)
+	}
+	return value
+}
+
+// Convert a name to PascalCase like the default naming convention of codegen, lowercasing the words,
+// e.g. USER_ROLE to UserRole and HTTPStatus to HttpStatus
+func toPascalCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	wordStart := true
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			wordStart = true
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			wordStart = wordStart || unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower)
+		}
+		if wordStart {
+			b.WriteRune(unicode.ToUpper(r))
+		} else {
+			b.WriteRune(unicode.ToLower(r))
+		}
+		wordStart = false
+	}
+	if converted := b.String(); converted != "" && !unicode.IsDigit([]rune(converted)[0]) {
+		return converted
+	}
+	return "_" + b.String()
+}
+
+// Check that the PascalCase members of each enum are distinct with the codegen preset
+func (g *Generator) checkCodegenEnums() error {
+	for _, name := range sortedKeys(g.schema.Enums) {
+		enum := g.schema.Enums[name]
+		if g.isUnionEnum(enum) {
+			continue
+		}
+		members := make(map[string]string)
+		for _, value := range enum.EnumValues {
+			member := toPascalCase(value.Name)
+			if other, found := members[member]; found {
+				return fmt.Errorf("enum %s values %s and %s both become the member %s with the codegen preset", name, other, value.Name, member)
+			}
+			members[member] = value.Name
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Error thrown by the coercion functions
+const coercionError = `export class InputCoercionError extends Error {
+  readonly path: string;
+  readonly expected: string;
+  readonly value: unknown;
+
+  constructor(path: string, expected: string, value: unknown) {
+    super(` + "`${path}: expected ${expected}, got ${JSON.stringify(value)}`" + `);
+    this.name = 'InputCoercionError';
+    this.path = path;
+    this.expected = expected;
+    this.value = value;
+  }
+}
+
+`
+
+// Coercions of objects, lists, enums and scalars shared by the coercion functions, written when used so that
+// the output passes noUnusedLocals. Strings are converted to numbers and booleans, as form data and URL parameters
+// only hold strings.
+var coercionHelpers = []struct{ name, source string }{
+	{"coerceObjectValue", `function coerceObjectValue(value: unknown, path: string, name: string): Record<string, unknown> {
+  if (typeof value === 'object' && value !== null && !Array.isArray(value)) {
+    return value as Record<string, unknown>;
+  }
+  throw new InputCoercionError(path, name, value);
+}
+
+`},
+	{"coerceListOf", `function coerceListOf<T>(value: unknown, path: string, item: (value: unknown, path: string) => T): Array<T> {
+  const values = Array.isArray(value) ? value : [value];
+  return values.map((value, index) => item(value, ` + "`${path}[${index}]`" + `));
+}
+
+`},
+	{"coerceEnumValue", `function coerceEnumValue<T>(value: unknown, path: string, name: string, values: Record<string, T>): T {
+  if (typeof value === 'string' && Object.prototype.hasOwnProperty.call(values, value)) {
+    return values[value];
+  }
+  for (const key of Object.keys(values)) {
+    if (values[key] === value) {
+      return values[key];
+    }
+  }
+  throw new InputCoercionError(path, name, value);
+}
+
+`},
+	{"coerceString", `function coerceString(value: unknown, path: string): string {
+  if (typeof value === 'string') {
+    return value;
+  }
+  if (typeof value === 'number' || typeof value === 'boolean') {
+    return String(value);
+  }
+  throw new InputCoercionError(path, 'String', value);
+}
+
+`},
+	{"coerceInt", `function coerceInt(value: unknown, path: string): number {
+  const number = typeof value === 'string' && value.trim() !== '' ? Number(value) : value;
+  if (typeof number === 'number' && Number.isInteger(number)) {
+    return number;
+  }
+  throw new InputCoercionError(path, 'Int', value);
+}
+
+`},
+	{"coerceFloat", `function coerceFloat(value: unknown, path: string): number {
+  const number = typeof value === 'string' && value.trim() !== '' ? Number(value) : value;
+  if (typeof number === 'number' && Number.isFinite(number)) {
+    return number;
+  }
+  throw new InputCoercionError(path, 'Float', value);
+}
+
+`},
+	{"coerceBoolean", `function coerceBoolean(value: unknown, path: string): boolean {
+  if (typeof value === 'boolean') {
+    return value;
+  }
+  if (value === 'true' || value === 'false') {
+    return value === 'true';
+  }
+  throw new InputCoercionError(path, 'Boolean', value);
+}
+
+`},
+	{"coerceBigInt", `function coerceBigInt(value: unknown, path: string): bigint {
+  if (typeof value === 'bigint') {
+    return value;
+  }
+  if ((typeof value === 'string' && /^-?\d+$/.test(value.trim())) || (typeof value === 'number' && Number.isInteger(value))) {
+    return BigInt(typeof value === 'string' ? value.trim() : value);
+  }
+  throw new InputCoercionError(path, 'BigInt', value);
+}
+
+`},
+	{"coerceDate", `function coerceDate(value: unknown, path: string): Date {
+  const date = typeof value === 'string' || typeof value === 'number' ? new Date(value) : value;
+  if (date instanceof Date && !Number.isNaN(date.getTime())) {
+    return date;
+  }
+  throw new InputCoercionError(path, 'Date', value);
+}
+
+`},
+}
+
+// Coercion functions of the TypeScript types of scalars, other types are passed through unchecked
+var scalarCoercions = map[string]string{
+	"string":  "coerceString",
+	"boolean": "coerceBoolean",
+	"bigint":  "coerceBigInt",
+	"Date":    "coerceDate",
+}
+
+// Return the name of the coercion function of an input type
+func (g *Generator) coercionName(name string) string {
+	return "coerce" + g.tsName(name)
+}
+
+// Generate a function converting untyped data, such as form data, into each input type, e.g.
+// `coerceCreateUserInput(raw: unknown): CreateUserInput`. Unknown keys are dropped, scalars are converted
+// from strings, fields missing from the data get their default value, nullable ones included, and an InputCoercionError
+// naming the path of the value is thrown when a value cannot be converted.
+func (g *Generator) writeCoercions(file *bufio.Writer, selected map[string]bool) {
+	var functions strings.Builder
+	for _, name := range orderedKeys(g, g.schema.Inputs, "") {
+		if selected != nil && !selected[name] {
+			continue
+		}
+		input := g.schema.Inputs[name]
+		tsName := g.tsName(name)
+		functions.WriteString(fmt.Sprintf("export function %s(raw: unknown, path = %s): %s {\n", g.coercionName(name), quoteString(name), tsName))
+		functions.WriteString(fmt.Sprintf("  const value = coerceObjectValue(raw, path, %s);\n", quoteString(name)))
+
+		var optional []*ast.FieldDefinition
+		functions.WriteString(fmt.Sprintf("  const result: %s = {\n", tsName))
+		for _, field := range input.Fields {
+			if !field.Type.NonNull && field.DefaultValue == nil {
+				optional = append(optional, field)
+				continue
+			}
+			key, fieldPath := "value["+quoteString(field.Name)+"]", "`${path}."+field.Name+"`"
+			coercion := g.coercion(name, field, field.Type, key, fieldPath)
+			if !field.Type.NonNull {
+				coercion = fmt.Sprintf("%s === null ? null : %s", key, coercion)
+			}
+			if field.DefaultValue != nil {
+				coercion = fmt.Sprintf("%s === undefined ? %s : %s", key, g.tsValue(field.DefaultValue, field.Type), coercion)
+			}
+			functions.WriteString(fmt.Sprintf("    %s: %s,\n", g.propertyName(field), coercion))
+		}
+		functions.WriteString("  };\n")
+		for _, field := range optional {
+			key, fieldPath := "value["+quoteString(field.Name)+"]", "`${path}."+field.Name+"`"
+			functions.WriteString(fmt.Sprintf("  if (%s !== undefined) {\n", key))
+			functions.WriteString(fmt.Sprintf("    result%s = %s === null ? null : %s;\n  }\n", g.propertyAccess(field), key, g.coercion(name, field, field.Type, key, fieldPath)))
+		}
+		functions.WriteString("  return result;\n}\n\n")
+	}
+	if functions.Len() == 0 {
+		return
+	}
+
+	file.WriteString(coercionError)
+	for _, helper := range coercionHelpers {
+		if strings.Contains(functions.String(), helper.name+"(") {
+			file.WriteString(helper.source)
+		}
+	}
+	file.WriteString(functions.String())
+}
+
+// Return the access of the property of a field, e.g. .name, or ['delete'] for quoted property names
+func (g *Generator) propertyAccess(field *ast.FieldDefinition) string {
+	if property := g.propertyName(field); !strings.HasPrefix(property, "'") {
+		return "." + property
+	}
+	return "[" + g.propertyName(field) + "]"
+}
+
+// Return the expression coercing a value of a non-null type at the given path. List items are nullable
+// with the codegen preset only, like their TypeScript type.
+func (g *Generator) coercion(owner string, field *ast.FieldDefinition, typ *ast.Type, value string, path string) string {
+	if override, found := g.fieldTypeOverride(owner, field.Name); found {
+		return value + " as " + override.tsType
+	}
+	if typ.Elem != nil {
+		item := g.coercion(owner, field, typ.Elem, "item", "itemPath")
+		if g.codegen() && !typ.Elem.NonNull {
+			item = "item === null ? null : " + item
+		}
+		return fmt.Sprintf("coerceListOf(%s, %s, (item, itemPath) => %s)", value, path, item)
+	}
+
+	name := typ.Name()
+	if enum, found := g.schema.Enums[name]; found {
+		values := make([]string, len(enum.EnumValues))
+		for i, enumValue := range enum.EnumValues {
+			values[i] = g.propertyKey(enumValue.Name) + ": " + g.enumMember(name, enumValue.Name)
+		}
+		return fmt.Sprintf("coerceEnumValue(%s, %s, %s, { %s })", value, path, quoteString(name), strings.Join(values, ", "))
+	}
+	if _, found := g.schema.Inputs[name]; found && g.isResolved(name) {
+		return fmt.Sprintf("%s(%s, %s)", g.coercionName(name), value, path)
+	}
+
+	tsType, found := g.scalarType(name)
+	if !found {
+		return value + " as unknown"
+	}
+	if tsType == "number" {
+		if name == "Int" {
+			return fmt.Sprintf("coerceInt(%s, %s)", value, path)
+		}
+		return fmt.Sprintf("coerceFloat(%s, %s)", value, path)
+	}
+	if coerce, found := scalarCoercions[tsType]; found {
+		return fmt.Sprintf("%s(%s, %s)", coerce, value, path)
+	}
+	return value + " as " + tsType
+}
@@ -0,0 +1,351 @@
+package generator
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// TypeComplexity is the number of distinct definitions referenced by the fields and arguments of a type
+type TypeComplexity struct {
+	Name   string
+	FanOut int
+}
+
+// RootFieldComplexity describes the type graph a root field can select from
+type RootFieldComplexity struct {
+	Root  string
+	Field string
+	// Deepest nesting of selection sets, not counting the cycles
+	Depth int
+	// Whether a cycle is reachable, so that selections can be nested without limit
+	Cyclic bool
+	// Number of types, inputs, enums and unions reachable from the field and its arguments
+	ReachableTypes int
+}
+
+// ComplexityReport summarizes the shape of the merged schema
+type ComplexityReport struct {
+	// Object types, interfaces and inputs, by decreasing fan-out
+	Types []TypeComplexity
+	// Root fields in Query, Mutation, Subscription order
+	RootFields []RootFieldComplexity
+	// Number of types, inputs, enums and unions reachable from any root field, and defined in total
+	ReachableTypes int
+	TotalTypes     int
+}
+
+// Complexity reports the fan-out of each type and the nesting depth and reachable types of each root field
+func (g *Generator) Complexity() ComplexityReport {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var report ComplexityReport
+	for _, name := range sortedKeys(g.schema.Types) {
+		report.Types = append(report.Types, TypeComplexity{name, g.fanOut(g.schema.Types[name].Definition)})
+	}
+	for _, name := range sortedKeys(g.schema.Inputs) {
+		report.Types = append(report.Types, TypeComplexity{name, g.fanOut(g.schema.Inputs[name])})
+	}
+	sort.SliceStable(report.Types, func(i, j int) bool {
+		return report.Types[i].FanOut > report.Types[j].FanOut
+	})
+
+	graph := newNestingGraph(g.schema)
+	reach := newReachability(g.schema, g.isExcluded)
+	all := reach.newSet()
+	for i, roots := range g.schema.roots() {
+		for _, name := range sortedKeys(roots) {
+			field := roots[name]
+			reachable := reach.newSet()
+			reach.addField(reachable, field)
+			all.union(reachable)
+
+			depth, cyclic := graph.nesting(field.Type.Name())
+			report.RootFields = append(report.RootFields, RootFieldComplexity{
+				Root:           rootNames[i],
+				Field:          name,
+				Depth:          depth,
+				Cyclic:         cyclic,
+				ReachableTypes: reachable.count(),
+			})
+		}
+	}
+	report.ReachableTypes = all.count()
+	report.TotalTypes = len(g.schema.Types) + len(g.schema.Inputs) + len(g.schema.Enums) + len(g.schema.Unions)
+	return report
+}
+
+// Count the distinct definitions of the schema referenced by the fields and arguments of a type
+func (g *Generator) fanOut(def *ast.Definition) int {
+	referenced := make(map[string]bool)
+	for _, field := range def.Fields {
+		referenced[field.Type.Name()] = true
+		for _, arg := range field.Arguments {
+			referenced[arg.Type.Name()] = true
+		}
+	}
+	for _, name := range def.Interfaces {
+		referenced[name] = true
+	}
+	count := 0
+	for name := range referenced {
+		if name != def.Name && g.schema.definition(name) != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Graph of the output types a selection set can nest into. Field edges add a level,
+// while union members and interface implementers are selected at the same level.
+type nestingGraph struct {
+	schema *Schema
+	components
+	depths map[int]nestingDepth
+}
+
+type nestingDepth struct {
+	depth  int
+	cyclic bool
+}
+
+type nestingEdge struct {
+	to    string
+	level int
+}
+
+func newNestingGraph(schema *Schema) *nestingGraph {
+	graph := &nestingGraph{schema: schema, depths: make(map[int]nestingDepth)}
+	names := append(sortedKeys(schema.Types), sortedKeys(schema.Unions)...)
+	graph.components = findComponents(names, func(name string) []string {
+		var targets []string
+		for _, edge := range graph.edges(name) {
+			targets = append(targets, edge.to)
+		}
+		return targets
+	})
+	return graph
+}
+
+// Return the edges leaving a type: its composite field types, its union members and its implementers
+func (n *nestingGraph) edges(name string) []nestingEdge {
+	var edges []nestingEdge
+	if union, found := n.schema.Unions[name]; found {
+		for _, member := range union.Types {
+			edges = append(edges, nestingEdge{member, 0})
+		}
+		return edges
+	}
+	typeInfo, found := n.schema.Types[name]
+	if !found {
+		return nil
+	}
+	for _, field := range typeInfo.Definition.Fields {
+		if target := field.Type.Name(); n.isComposite(target) {
+			edges = append(edges, nestingEdge{target, 1})
+		}
+	}
+	if typeInfo.Definition.Kind == ast.Interface {
+		for _, implementer := range n.schema.implementers(name) {
+			edges = append(edges, nestingEdge{implementer, 0})
+		}
+	}
+	return edges
+}
+
+func (n *nestingGraph) isComposite(name string) bool {
+	_, isType := n.schema.Types[name]
+	_, isUnion := n.schema.Unions[name]
+	return isType || isUnion
+}
+
+// Return the deepest nesting of selection sets below a field of the named type,
+// and whether a cycle is reachable from it
+func (n *nestingGraph) nesting(name string) (int, bool) {
+	id, found := n.of[name]
+	if !found {
+		return 0, false
+	}
+	result := n.componentNesting(id)
+	return result.depth, result.cyclic
+}
+
+func (n *nestingGraph) componentNesting(id int) nestingDepth {
+	if result, found := n.depths[id]; found {
+		return result
+	}
+
+	// Components only reference components found before them, so the recursion ends
+	result := nestingDepth{cyclic: n.cyclic[id]}
+	for _, member := range n.members[id] {
+		if _, isType := n.schema.Types[member]; isType {
+			result.depth = max(result.depth, 1)
+		}
+		for _, edge := range n.edges(member) {
+			target, found := n.of[edge.to]
+			if !found || target == id {
+				continue
+			}
+			nested := n.componentNesting(target)
+			result.depth = max(result.depth, nested.depth+edge.level)
+			result.cyclic = result.cyclic || nested.cyclic
+		}
+	}
+	n.depths[id] = result
+	return result
+}
+
+// Strongly connected components of a graph, in reverse topological order:
+// the edges of a component only lead to itself or to components found before it
+type components struct {
+	of      map[string]int
+	members [][]string
+	// Whether the component contains a cycle, including a node referencing itself
+	cyclic []bool
+}
+
+// Find the strongly connected components with Tarjan's algorithm
+func findComponents(names []string, edges func(name string) []string) components {
+	c := components{of: make(map[string]int)}
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+
+	var connect func(name string)
+	connect = func(name string) {
+		index[name] = len(index)
+		lowLink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+		selfLoop := false
+		for _, target := range edges(name) {
+			if target == name {
+				selfLoop = true
+			}
+			if _, visited := index[target]; !visited {
+				connect(target)
+				lowLink[name] = min(lowLink[name], lowLink[target])
+			} else if onStack[target] {
+				lowLink[name] = min(lowLink[name], index[target])
+			}
+		}
+		if lowLink[name] != index[name] {
+			return
+		}
+
+		id := len(c.members)
+		var members []string
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[member] = false
+			c.of[member] = id
+			members = append(members, member)
+			if member == name {
+				break
+			}
+		}
+		c.members = append(c.members, members)
+		c.cyclic = append(c.cyclic, len(members) > 1 || selfLoop)
+	}
+
+	for _, name := range names {
+		if _, visited := index[name]; !visited {
+			connect(name)
+		}
+	}
+	return c
+}
+
+// Definitions reachable from each definition, as walked by typeWalker. They are computed once
+// per strongly connected component, so that the reachable types of every root field are
+// found in linear time rather than with one walk per field.
+type reachability struct {
+	schema   *Schema
+	excluded func(name string) bool
+	// Bit of each walked definition
+	bit map[string]int
+	components
+	// Definitions reachable from each component
+	reachable []bitSet
+}
+
+func newReachability(schema *Schema, excluded func(name string) bool) *reachability {
+	r := &reachability{schema: schema, excluded: excluded, bit: make(map[string]int)}
+	var names []string
+	for _, defs := range []map[string]*ast.Definition{schema.Enums, schema.Unions, schema.Inputs} {
+		names = append(names, sortedKeys(defs)...)
+	}
+	names = append(names, sortedKeys(schema.Types)...)
+	for _, name := range names {
+		if !excluded(name) {
+			r.bit[name] = len(r.bit)
+		}
+	}
+
+	r.components = findComponents(sortedKeys(r.bit), r.edges)
+	for id, members := range r.members {
+		reachable := r.newSet()
+		for _, member := range members {
+			reachable.add(r.bit[member])
+			for _, target := range r.edges(member) {
+				if other := r.of[target]; other != id {
+					reachable.union(r.reachable[other])
+				}
+			}
+		}
+		r.reachable = append(r.reachable, reachable)
+	}
+	return r
+}
+
+// Return the walked definitions referenced by a definition
+func (r *reachability) edges(name string) []string {
+	references, _ := r.schema.references(name)
+	var targets []string
+	for _, reference := range references {
+		if _, walked := r.bit[reference]; walked {
+			targets = append(targets, reference)
+		}
+	}
+	return targets
+}
+
+func (r *reachability) newSet() bitSet {
+	return make(bitSet, (len(r.bit)+63)/64)
+}
+
+// Add the definitions reachable from the type and the arguments of a field
+func (r *reachability) addField(set bitSet, field *ast.FieldDefinition) {
+	names := []string{field.Type.Name()}
+	for _, arg := range field.Arguments {
+		names = append(names, arg.Type.Name())
+	}
+	for _, name := range names {
+		if id, found := r.of[name]; found {
+			set.union(r.reachable[id])
+		}
+	}
+}
+
+type bitSet []uint64
+
+func (b bitSet) add(i int) {
+	b[i/64] |= 1 << (i % 64)
+}
+
+func (b bitSet) union(other bitSet) {
+	for i := range b {
+		b[i] |= other[i]
+	}
+}
+
+func (b bitSet) count() int {
+	count := 0
+	for _, word := range b {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"sync"
+)
+
+// A part of the output that depends only on the schema and the options, so that it can be
+// written concurrently with the other parts
+type emitSection func(g *Generator, file *bufio.Writer) error
+
+// The content of a written section and the types it references
+type sectionOutput struct {
+	content    []byte
+	references *references
+}
+
+// Write the sections in parallel goroutines and return their content in the order of the sections.
+// The schema is not modified during emission: each section is written by its own view of the generator,
+// which collects the types the section references, and the lazily built indexes are built beforehand.
+// The returned error is the one of the first failed section.
+func (g *Generator) emitSections(sections []emitSection) ([]sectionOutput, error) {
+	g.schema.indexImplementers()
+
+	outputs := make([]sectionOutput, len(sections))
+	errs := make([]error, len(sections))
+	var wg sync.WaitGroup
+	for i, section := range sections {
+		wg.Add(1)
+		go func(i int, section emitSection) {
+			defer wg.Done()
+			view := g.sectionView()
+			var content bytes.Buffer
+			file := bufio.NewWriter(&content)
+			if errs[i] = section(view, file); errs[i] == nil {
+				errs[i] = file.Flush()
+			}
+			outputs[i] = sectionOutput{content.Bytes(), view.references}
+		}(i, section)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
+
+// Return a generator sharing the options and the schema, collecting the references of one section
+func (g *Generator) sectionView() *Generator {
+	return &Generator{
+		opts:         g.opts,
+		schema:       g.schema,
+		parsed:       g.parsed,
+		sourceHashes: g.sourceHashes,
+		comments:     g.comments,
+		references:   newReferences(),
+	}
+}
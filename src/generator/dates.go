@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Conversion functions between DateTime strings and Date objects, walking the DateFields map
+const dateHelpers = `export function parseDateTime(value: string): Date {
+  return new Date(value);
+}
+
+export function serializeDateTime(value: Date): string {
+  return value.toISOString();
+}
+
+function convertDates(typeName: string, value: unknown, convert: (value: any) => unknown): unknown {
+  if (value === null || value === undefined) {
+    return value;
+  }
+  if (Array.isArray(value)) {
+    return value.map((item) => convertDates(typeName, item, convert));
+  }
+  if (typeName === 'DateTime') {
+    return convert(value);
+  }
+  if (typeof value !== 'object') {
+    return value;
+  }
+  const record = value as Record<string, unknown>;
+  const concrete = typeof record.__typename === 'string' && record.__typename in DateFields ? record.__typename : typeName;
+  const fields: Record<string, string> | undefined = DateFields[concrete];
+  if (!fields) {
+    return value;
+  }
+  const result: Record<string, unknown> = { ...record };
+  for (const [field, fieldType] of Object.entries(fields)) {
+    if (field in result) {
+      result[field] = convertDates(fieldType, result[field], convert);
+    }
+  }
+  return result;
+}
+
+// Replace the DateTime strings of a response value with Date objects
+export function parseDates<T>(typeName: string, value: unknown): T {
+  return convertDates(typeName, value, parseDateTime) as T;
+}
+
+// Replace the Date objects of an input value with DateTime strings
+export function serializeDates<T>(typeName: string, value: T): unknown {
+  return convertDates(typeName, value, serializeDateTime);
+}
+
+`
+
+// Generate the DateFields map of the types holding DateTime values, followed by the conversion helpers
+func (g *Generator) writeDateHelpers(file *bufio.Writer, selected map[string]bool) {
+	definitions := make(map[string]*ast.Definition)
+	for name, typeInfo := range g.schema.Types {
+		def := *typeInfo.Definition
+		def.Fields = g.objectFields(typeInfo.Definition)
+		definitions[name] = &def
+	}
+	for name, input := range g.schema.Inputs {
+		definitions[name] = input
+	}
+
+	// A type holds dates if one of its fields is a DateTime or a type holding dates
+	withDates := map[string]bool{"DateTime": true}
+	for changed := true; changed; {
+		changed = false
+		for name, def := range definitions {
+			if withDates[name] {
+				continue
+			}
+			for _, field := range def.Fields {
+				if withDates[field.Type.Name()] {
+					withDates[name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	file.WriteString("export const DateFields: Record<string, Record<string, string>> = {\n")
+	for _, name := range sortedKeys(definitions) {
+		if !withDates[name] || (selected != nil && !selected[name]) {
+			continue
+		}
+		var fields []string
+		for _, field := range definitions[name].Fields {
+			if withDates[field.Type.Name()] {
+				fields = append(fields, fmt.Sprintf("%s: '%s'", g.propertyKey(field.Name), field.Type.Name()))
+			}
+		}
+		file.WriteString(fmt.Sprintf("  %s: { %s },\n", name, strings.Join(fields, ", ")))
+	}
+	file.WriteString("};\n\n")
+	file.WriteString(dateHelpers)
+}
@@ -0,0 +1,251 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Zero values of the TypeScript types of scalars, for required fields without default value
+var zeroValues = map[string]string{
+	"string":                  "''",
+	"number":                  "0",
+	"boolean":                 "false",
+	"bigint":                  "BigInt(0)",
+	"Date":                    "new Date(0)",
+	"unknown":                 "null",
+	"any":                     "null",
+	"Record<string, unknown>": "{}",
+}
+
+// Return the name of the default value factory of an input type
+func (g *Generator) inputDefaultsName(name string) string {
+	return "default" + g.tsName(name)
+}
+
+// Generate a factory returning a valid value of each input type: the fields with a default value
+// in the schema are set to it, and the other required fields to the zero value of their type
+func (g *Generator) writeInputDefaults(file *bufio.Writer, selected map[string]bool) {
+	for _, name := range orderedKeys(g, g.schema.Inputs, "") {
+		if selected != nil && !selected[name] {
+			continue
+		}
+		input := g.schema.Inputs[name]
+		var values []string
+		for _, field := range input.Fields {
+			var value string
+			if field.DefaultValue != nil {
+				value = g.tsValue(field.DefaultValue, field.Type)
+			} else if field.Type.NonNull {
+				value = g.zeroValue(input.Name, field)
+			} else {
+				continue
+			}
+			values = append(values, fmt.Sprintf("    %s: %s,\n", g.propertyName(field), value))
+		}
+
+		file.WriteString(fmt.Sprintf("export function %s(): %s {\n", g.inputDefaultsName(name), g.tsName(name)))
+		if len(values) == 0 {
+			file.WriteString("  return {};\n}\n\n")
+			continue
+		}
+		file.WriteString("  return {\n" + strings.Join(values, "") + "  };\n}\n\n")
+	}
+}
+
+// Return the zero value of a required input field: an empty list, the first value of an enum,
+// the default value of an input, or the zero value of the TypeScript type of a scalar.
+// Types without a known zero value, such as imported scalar types, get null!, to be replaced by the caller.
+func (g *Generator) zeroValue(owner string, field *ast.FieldDefinition) string {
+	if override, found := g.fieldTypeOverride(owner, field.Name); found {
+		return zeroValueOf(override.tsType)
+	}
+	if field.Type.Elem != nil {
+		return "[]"
+	}
+	name := field.Type.Name()
+	if enum, found := g.schema.Enums[name]; found && len(enum.EnumValues) > 0 {
+		return g.enumMember(name, enum.EnumValues[0].Name)
+	}
+	if _, found := g.schema.Inputs[name]; found {
+		return g.inputDefaultsName(name) + "()"
+	}
+	tsType, _ := g.scalarType(name)
+	return zeroValueOf(tsType)
+}
+
+func zeroValueOf(tsType string) string {
+	if value, found := zeroValues[tsType]; found {
+		return value
+	}
+	return "null!"
+}
+
+// Return a reference to an enum member, recording the enum as used as a value.
+// The members of enums emitted as unions are their string literals.
+func (g *Generator) enumMember(enum string, value string) string {
+	if def, found := g.schema.Enums[enum]; found && g.isUnionEnum(def) {
+		return quoteString(value)
+	}
+	g.useValue(enum)
+	return g.tsName(enum) + "." + g.enumMemberName(value)
+}
+
+// Convert a GraphQL default value into a TypeScript expression of the given type.
+// A single value of a list type is wrapped into a list, following the input coercion rules,
+// and input objects start from the default value of their type with the InputDefaults option, or are constructed
+// with the InputClasses option, so that omitted fields get their defaults.
+func (g *Generator) tsValue(value *ast.Value, typ *ast.Type) string {
+	if value.Kind == ast.NullValue {
+		return "null"
+	}
+	if typ.Elem != nil {
+		if value.Kind != ast.ListValue {
+			return "[" + g.tsValue(value, typ.Elem) + "]"
+		}
+		items := make([]string, len(value.Children))
+		for i, child := range value.Children {
+			items[i] = g.tsValue(child.Value, typ.Elem)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+
+	name := typ.Name()
+	switch value.Kind {
+	case ast.EnumValue:
+		return g.enumMember(name, value.Raw)
+	case ast.ObjectValue:
+		input, found := g.schema.Inputs[name]
+		if !found {
+			// Object values of scalars such as JSON, written as is
+			return value.String()
+		}
+		var fields []string
+		if g.opts.InputClasses {
+			for _, child := range value.Children {
+				if field := input.Fields.ForName(child.Name); field != nil {
+					fields = append(fields, g.propertyName(field)+": "+g.tsValue(child.Value, field.Type))
+				}
+			}
+			g.useValue(name)
+			return "new " + g.tsName(name) + "({ " + strings.Join(fields, ", ") + " })"
+		}
+		if g.opts.InputDefaults {
+			fields = append(fields, "..."+g.inputDefaultsName(name)+"()")
+		}
+		for _, child := range value.Children {
+			if field := input.Fields.ForName(child.Name); field != nil {
+				fields = append(fields, g.propertyName(field)+": "+g.tsValue(child.Value, field.Type))
+			}
+		}
+		return "{ " + strings.Join(fields, ", ") + " }"
+	}
+
+	literal := value.Raw
+	if value.Kind == ast.StringValue || value.Kind == ast.BlockValue {
+		literal = quoteString(value.Raw)
+	}
+	switch tsType, _ := g.scalarType(name); tsType {
+	case "string":
+		return quoteString(value.Raw)
+	case "bigint":
+		return "BigInt(" + literal + ")"
+	case "Date":
+		return "new Date(" + literal + ")"
+	}
+	return literal
+}
+
+// A field with default values for some of its arguments
+type argumentDefaults struct {
+	name string
+	// Root type of the field, empty for the fields of object types
+	root  string
+	field *ast.FieldDefinition
+}
+
+// Return the fields of the selected root and object types with defaulted arguments, with the names
+// of their constants: the field name for root fields, unless another root type has the field,
+// and the type and field names otherwise, e.g. GET_PROJECTS_DEFAULTS and USER_POSTS_DEFAULTS
+func (g *Generator) argumentDefaults(selected map[string]bool) []argumentDefaults {
+	hasDefaults := func(field *ast.FieldDefinition) bool {
+		for _, arg := range field.Arguments {
+			if arg.DefaultValue != nil {
+				return true
+			}
+		}
+		return false
+	}
+
+	var defaults []argumentDefaults
+	used := make(map[string]bool)
+	for i, fields := range g.schema.roots() {
+		for _, name := range orderedKeys(g, fields, rootNames[i]) {
+			if field := fields[name]; g.rootFieldSelected(rootNames[i], name) && hasDefaults(field) {
+				constant := constantName(name) + "_DEFAULTS"
+				if used[constant] {
+					constant = constantName(rootNames[i]) + "_" + constant
+				}
+				used[constant] = true
+				defaults = append(defaults, argumentDefaults{constant, rootNames[i], field})
+			}
+		}
+	}
+	for _, name := range orderedKeys(g, g.schema.Types, "") {
+		if selected != nil && !selected[name] {
+			continue
+		}
+		for _, field := range g.objectFields(g.schema.Types[name].Definition) {
+			if hasDefaults(field) {
+				defaults = append(defaults, argumentDefaults{constantName(g.tsName(name)) + "_" + constantName(field.Name) + "_DEFAULTS", "", field})
+			}
+		}
+	}
+	return defaults
+}
+
+// Return the fields with defaulted arguments of a root type, or of the object types for an empty root
+func (g *Generator) rootArgumentDefaults(selected map[string]bool, root string) []argumentDefaults {
+	var filtered []argumentDefaults
+	for _, defaults := range g.argumentDefaults(selected) {
+		if defaults.root == root {
+			filtered = append(filtered, defaults)
+		}
+	}
+	return filtered
+}
+
+// Generate a constant of the default values of the arguments of each field,
+// e.g. `export const GET_PROJECTS_DEFAULTS = { limit: 20 } as const`
+func (g *Generator) writeArgumentDefaults(file *bufio.Writer, fields []argumentDefaults) {
+	for _, defaults := range fields {
+		var values []string
+		for _, arg := range defaults.field.Arguments {
+			if arg.DefaultValue != nil {
+				values = append(values, fmt.Sprintf("  %s: %s,\n", g.propertyKey(arg.Name), g.tsValue(arg.DefaultValue, arg.Type)))
+			}
+		}
+		file.WriteString(fmt.Sprintf("export const %s = {\n%s} as const;\n\n", defaults.name, strings.Join(values, "")))
+	}
+}
+
+// Convert a camelCase or PascalCase name to SCREAMING_SNAKE_CASE, keeping acronyms together,
+// e.g. getHTTPStatus to GET_HTTP_STATUS
+func constantName(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
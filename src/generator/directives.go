@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Directives understood by the generator. They are declared automatically
+// unless a schema file declares them itself.
+var generatorDirectives = map[string]string{
+	"tsNumeric": "directive @tsNumeric on ENUM",
+	"tsUnion":   "directive @tsUnion on ENUM",
+	"tsValue":   "directive @tsValue(value: Int!) on ENUM_VALUE",
+	"tsName":    "directive @tsName(name: String!) on OBJECT | INTERFACE | ENUM | INPUT_OBJECT | UNION | SCALAR | FIELD_DEFINITION | INPUT_FIELD_DEFINITION",
+
+	// Apollo Federation
+	"key":          "directive @key(fields: String!, resolvable: Boolean = true) repeatable on OBJECT | INTERFACE",
+	"external":     "directive @external on OBJECT | FIELD_DEFINITION",
+	"requires":     "directive @requires(fields: String!) on FIELD_DEFINITION",
+	"provides":     "directive @provides(fields: String!) on FIELD_DEFINITION",
+	"extends":      "directive @extends on OBJECT | INTERFACE",
+	"shareable":    "directive @shareable repeatable on OBJECT | FIELD_DEFINITION",
+	"inaccessible": "directive @inaccessible on FIELD_DEFINITION | OBJECT | INTERFACE | UNION | ARGUMENT_DEFINITION | SCALAR | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION",
+	"override":     "directive @override(from: String!) on FIELD_DEFINITION",
+	"tag":          "directive @tag(name: String!) repeatable on FIELD_DEFINITION | OBJECT | INTERFACE | UNION | ARGUMENT_DEFINITION | SCALAR | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION",
+	"link":         "scalar link__Import\nenum link__Purpose { SECURITY EXECUTION }\ndirective @link(url: String!, as: String, import: [link__Import], for: link__Purpose) repeatable on SCHEMA",
+}
+
+// Locations of the directive hiding elements from the output
+const internalDirectiveLocations = "OBJECT | INTERFACE | FIELD_DEFINITION | ARGUMENT_DEFINITION | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION | UNION | SCALAR"
+
+// Build a built-in source declaring the generator directives missing from the schema content
+func (g *Generator) directivesSource(content string) *ast.Source {
+	directives := make(map[string]string, len(generatorDirectives)+1)
+	for name, declaration := range generatorDirectives {
+		directives[name] = declaration
+	}
+	internal := g.internalDirective()
+	if _, found := directives[internal]; !found {
+		directives[internal] = fmt.Sprintf("directive @%s on %s", internal, internalDirectiveLocations)
+	}
+
+	var declarations []string
+	for _, name := range sortedKeys(directives) {
+		declared := regexp.MustCompile(`directive\s+@` + regexp.QuoteMeta(name) + `\b`)
+		if !declared.MatchString(content) {
+			declarations = append(declarations, directives[name])
+		}
+	}
+	return &ast.Source{
+		Name:    "generator-directives.graphql",
+		Input:   strings.Join(declarations, "\n"),
+		BuiltIn: true,
+	}
+}
@@ -0,0 +1,228 @@
+package generator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Generate a Markdown reference of the schema: root fields, types, fields, arguments, enums and deprecations
+func (g *Generator) emitDocs(ctx context.Context, w io.Writer) error {
+	schema := g.schema
+	selected := g.selectedTypes()
+	file := bufio.NewWriter(w)
+
+	file.WriteString("# Schema Reference\n\n")
+
+	// Root operation fields
+	for i, fields := range schema.roots() {
+		var fieldNames []string
+		for _, name := range sortedKeys(fields) {
+			if g.rootFieldSelected(rootNames[i], name) {
+				fieldNames = append(fieldNames, name)
+			}
+		}
+		if len(fieldNames) == 0 {
+			continue
+		}
+
+		file.WriteString(fmt.Sprintf("## %s\n\n", rootNames[i]))
+		for _, name := range fieldNames {
+			field := fields[name]
+			file.WriteString(fmt.Sprintf("### %s.%s\n\n", rootNames[i], field.Name))
+			g.writeDocsDescription(file, field.Description, field.Directives)
+			file.WriteString(fmt.Sprintf("**Returns:** %s\n\n", g.docsTypeLink(field.Type)))
+			g.writeDocsArguments(file, field.Arguments)
+		}
+	}
+
+	for _, section := range g.docsSections(selected) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		file.WriteString(fmt.Sprintf("## %s\n\n", section.title))
+		for _, def := range section.defs {
+			g.writeDocsDefinition(file, def)
+		}
+	}
+
+	return file.Flush()
+}
+
+// A group of definitions of the same kind in the documentation
+type docsSection struct {
+	title string
+	defs  []*ast.Definition
+}
+
+// Return the non-empty documentation sections, with definitions sorted by name
+func (g *Generator) docsSections(selected map[string]bool) []docsSection {
+	schema := g.schema
+	sections := []struct {
+		title string
+		defs  map[string]*ast.Definition
+	}{
+		{"Objects", g.definitionsOfKind(ast.Object)},
+		{"Interfaces", g.definitionsOfKind(ast.Interface)},
+		{"Inputs", schema.Inputs},
+		{"Enums", schema.Enums},
+		{"Unions", schema.Unions},
+		{"Scalars", schema.Scalars},
+	}
+
+	var result []docsSection
+	for _, section := range sections {
+		var defs []*ast.Definition
+		for _, name := range sortedKeys(section.defs) {
+			// Type filters only select object types, interfaces and enums
+			def := section.defs[name]
+			filtered := def.Kind == ast.Object || def.Kind == ast.Interface || def.Kind == ast.Enum
+			if selected == nil || !filtered || selected[name] {
+				defs = append(defs, def)
+			}
+		}
+		if len(defs) > 0 {
+			result = append(result, docsSection{title: section.title, defs: defs})
+		}
+	}
+	return result
+}
+
+// Return the object types or interfaces of the schema
+func (g *Generator) definitionsOfKind(kind ast.DefinitionKind) map[string]*ast.Definition {
+	defs := make(map[string]*ast.Definition)
+	for name, typeInfo := range g.schema.Types {
+		if typeInfo.Definition.Kind == kind {
+			defs[name] = typeInfo.Definition
+		}
+	}
+	return defs
+}
+
+// Generate the section of a single type, input, enum, union or scalar
+func (g *Generator) writeDocsDefinition(file *bufio.Writer, def *ast.Definition) {
+	file.WriteString(fmt.Sprintf("### %s\n\n", def.Name))
+	g.writeDocsDescription(file, def.Description, def.Directives)
+
+	if len(def.Interfaces) > 0 {
+		links := make([]string, len(def.Interfaces))
+		for i, iface := range def.Interfaces {
+			links[i] = docsLink(iface)
+		}
+		file.WriteString(fmt.Sprintf("**Implements:** %s\n\n", strings.Join(links, ", ")))
+	}
+	if len(def.Types) > 0 {
+		links := make([]string, len(def.Types))
+		for i, member := range def.Types {
+			links[i] = docsLink(member)
+		}
+		file.WriteString(fmt.Sprintf("**Possible types:** %s\n\n", strings.Join(links, ", ")))
+	}
+
+	if len(def.Fields) > 0 {
+		file.WriteString("| Field | Type | Description |\n| --- | --- | --- |\n")
+		for _, field := range def.Fields {
+			name := field.Name
+			if len(field.Arguments) > 0 {
+				args := make([]string, len(field.Arguments))
+				for i, arg := range field.Arguments {
+					args[i] = fmt.Sprintf("%s: %s", arg.Name, arg.Type.String())
+				}
+				name += "(" + strings.Join(args, ", ") + ")"
+			}
+			file.WriteString(fmt.Sprintf("| `%s` | %s | %s |\n", escapeDocsCell(name), g.docsTypeLink(field.Type), docsCellDescription(field.Description, field.Directives)))
+		}
+		file.WriteString("\n")
+	}
+
+	if len(def.EnumValues) > 0 {
+		file.WriteString("| Value | Description |\n| --- | --- |\n")
+		for _, value := range def.EnumValues {
+			file.WriteString(fmt.Sprintf("| `%s` | %s |\n", value.Name, docsCellDescription(value.Description, value.Directives)))
+		}
+		file.WriteString("\n")
+	}
+}
+
+// Generate the argument table of a root field
+func (g *Generator) writeDocsArguments(file *bufio.Writer, args ast.ArgumentDefinitionList) {
+	if len(args) == 0 {
+		return
+	}
+
+	file.WriteString("| Argument | Type | Default | Description |\n| --- | --- | --- | --- |\n")
+	for _, arg := range args {
+		defaultValue := ""
+		if arg.DefaultValue != nil {
+			defaultValue = "`" + escapeDocsCell(arg.DefaultValue.String()) + "`"
+		}
+		file.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", arg.Name, g.docsTypeLink(arg.Type), defaultValue, docsCellDescription(arg.Description, arg.Directives)))
+	}
+	file.WriteString("\n")
+}
+
+// Generate a description paragraph and deprecation notice
+func (g *Generator) writeDocsDescription(file *bufio.Writer, description string, directives ast.DirectiveList) {
+	if reason, deprecated := deprecationReason(directives); deprecated {
+		file.WriteString(fmt.Sprintf("> **Deprecated:** %s\n\n", reason))
+	}
+	if description = strings.TrimSpace(description); description != "" {
+		file.WriteString(description + "\n\n")
+	}
+}
+
+// Render a type reference, linking the named type if it is documented
+func (g *Generator) docsTypeLink(typ *ast.Type) string {
+	var rendered string
+	if typ.Elem != nil {
+		rendered = "\\[" + g.docsTypeLink(typ.Elem) + "\\]"
+	} else if _, builtIn := defaultScalars[typ.NamedType]; builtIn && g.schema.Scalars[typ.NamedType] == nil {
+		rendered = typ.NamedType
+	} else {
+		rendered = docsLink(typ.NamedType)
+	}
+	if typ.NonNull {
+		rendered += "!"
+	}
+	return rendered
+}
+
+// Render a link to the section of a type
+func docsLink(name string) string {
+	return fmt.Sprintf("[%s](#%s)", name, strings.ToLower(name))
+}
+
+// Render a description and deprecation notice for a table cell
+func docsCellDescription(description string, directives ast.DirectiveList) string {
+	cell := escapeDocsCell(strings.TrimSpace(description))
+	if reason, deprecated := deprecationReason(directives); deprecated {
+		if cell != "" {
+			cell += "<br>"
+		}
+		cell += "**Deprecated:** " + escapeDocsCell(reason)
+	}
+	return cell
+}
+
+var docsCellEscaper = strings.NewReplacer("|", "\\|", "\n", "<br>")
+
+func escapeDocsCell(text string) string {
+	return docsCellEscaper.Replace(text)
+}
+
+// Return the reason of a @deprecated directive
+func deprecationReason(directives ast.DirectiveList) (string, bool) {
+	directive := directives.ForName("deprecated")
+	if directive == nil {
+		return "", false
+	}
+	if reason := directive.Arguments.ForName("reason"); reason != nil && reason.Value != nil {
+		return reason.Value.Raw, true
+	}
+	return "No longer supported", true
+}
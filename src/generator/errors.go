@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// PositionError is an error pointing at a location in a schema file.
+// It is formatted as file:line:column so editors can jump to it.
+type PositionError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// Create a PositionError for a definition position. Missing positions only report the message.
+func positionErrorf(pos *ast.Position, format string, a ...any) error {
+	message := fmt.Sprintf(format, a...)
+	if pos == nil || pos.Src == nil {
+		return errors.New(message)
+	}
+	return &PositionError{
+		File:    pos.Src.Name,
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Message: message,
+	}
+}
+
+// Format a position as file:line:column
+func formatPosition(pos *ast.Position) string {
+	if pos == nil || pos.Src == nil {
+		return "unknown position"
+	}
+	return fmt.Sprintf("%s:%d:%d", pos.Src.Name, pos.Line, pos.Column)
+}
+
+// Convert a gqlparser error to PositionErrors, one per reported location
+func parseError(file string, err error) error {
+	var gqlErr *gqlerror.Error
+	if !errors.As(err, &gqlErr) || len(gqlErr.Locations) == 0 {
+		return fmt.Errorf("%s: error parsing schema: %v", file, err)
+	}
+
+	errs := make([]error, 0, len(gqlErr.Locations))
+	for _, location := range gqlErr.Locations {
+		errs = append(errs, &PositionError{
+			File:    file,
+			Line:    location.Line,
+			Column:  location.Column,
+			Message: gqlErr.Message,
+		})
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Function failing the compilation of a switch that misses a case, and throwing if an unknown value comes at runtime
+const assertNeverHelper = "export function assertNever(value: never): never {\n" +
+	"  throw new Error(`Unexpected value: ${JSON.stringify(value)}`);\n" +
+	"}\n\n"
+
+// Return the name of the match function of an enum or union
+func (g *Generator) matchName(name string) string {
+	return "match" + g.tsName(name)
+}
+
+// Generate the match function of an enum, calling the case of the value. The cases are a record
+// of every enum value, so that a value added to the schema fails the compilation of every match.
+func (g *Generator) writeEnumMatch(file *bufio.Writer, enum *ast.Definition) {
+	name := g.tsName(enum.Name)
+	file.WriteString(fmt.Sprintf("export function %s<R>(value: %s, cases: Record<%s, () => R>): R {\n", g.matchName(enum.Name), name, name))
+	file.WriteString("  return cases[value]();\n}\n\n")
+}
+
+// Generate assertNever and the match function of each union, calling the case of the member type
+// named by the __typename of the value, with a case required for every member
+func (g *Generator) writeUnionMatches(file *bufio.Writer, selected map[string]bool) {
+	file.WriteString(assertNeverHelper)
+	for _, name := range orderedKeys(g, g.schema.Unions, "") {
+		if selected != nil && !selected[name] {
+			continue
+		}
+		var members []string
+		for _, member := range g.schema.Unions[name].Types {
+			if !g.isExcluded(member) && (selected == nil || selected[member]) {
+				members = append(members, member)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+
+		values := make([]string, len(members))
+		for i, member := range members {
+			values[i] = fmt.Sprintf("(%s & { __typename: %s })", g.convertGraphqlTypeToTs(member), quoteString(member))
+		}
+		file.WriteString(fmt.Sprintf("export function %s<R>(\n  value: %s,\n  cases: {\n", g.matchName(name), strings.Join(values, " | ")))
+		for _, member := range members {
+			file.WriteString(fmt.Sprintf("    %s: (value: %s) => R;\n", member, g.tsName(member)))
+		}
+		file.WriteString("  },\n): R {\n  switch (value.__typename) {\n")
+		for _, member := range members {
+			file.WriteString(fmt.Sprintf("    case %s:\n      return cases.%s(value);\n", quoteString(member), member))
+		}
+		file.WriteString("    default:\n      return assertNever(value);\n  }\n}\n\n")
+	}
+}
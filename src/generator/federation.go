@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Generate the Apollo Federation entity types: a key fields type per entity,
+// the _Entity union and the _Any representation
+func (g *Generator) writeFederationTypes(file *bufio.Writer, selected map[string]bool) error {
+	var entities []string
+	for _, name := range g.objectTypeNames(selected) {
+		def := g.schema.Types[name].Definition
+		keys := def.Directives.ForNames("key")
+		if len(keys) == 0 {
+			continue
+		}
+		entities = append(entities, g.tsName(name))
+
+		var representations []string
+		for _, key := range keys {
+			representation, err := g.keyFieldsType(def, key)
+			if err != nil {
+				return err
+			}
+			representations = append(representations, representation)
+		}
+		file.WriteString(fmt.Sprintf("export type %sKeyFields = %s;\n\n", g.tsName(name), strings.Join(representations, " | ")))
+	}
+
+	file.WriteString("export type _Any = { __typename: string; [key: string]: unknown };\n\n")
+	if len(entities) == 0 {
+		file.WriteString("export type _Entity = never;\n\n")
+	} else {
+		file.WriteString(fmt.Sprintf("export type _Entity = %s;\n\n", strings.Join(entities, " | ")))
+	}
+	return nil
+}
+
+// Return the representation type of one @key of an entity, such as { __typename: 'User'; id: string }
+func (g *Generator) keyFieldsType(def *ast.Definition, key *ast.Directive) (string, error) {
+	arg := key.Arguments.ForName("fields")
+	if arg == nil || arg.Value == nil {
+		return "", positionErrorf(key.Position, "@key on %s has no fields", def.Name)
+	}
+	document, err := parser.ParseQuery(&ast.Source{Name: def.Name, Input: "{ " + arg.Value.Raw + " }"})
+	if err != nil {
+		return "", positionErrorf(key.Position, "@key on %s has invalid fields %q: %s", def.Name, arg.Value.Raw, err.Error())
+	}
+	fields, err := g.selectionType(def, document.Operations[0].SelectionSet)
+	if err != nil {
+		return "", positionErrorf(key.Position, "@key on %s: %s", def.Name, err.Error())
+	}
+	return fmt.Sprintf("{ __typename: '%s'; %s }", def.Name, fields), nil
+}
+
+// Return the properties of a key field selection on a type, nested selections become object types
+func (g *Generator) selectionType(def *ast.Definition, selections ast.SelectionSet) (string, error) {
+	var properties []string
+	for _, selection := range selections {
+		selected, ok := selection.(*ast.Field)
+		if !ok {
+			return "", fmt.Errorf("only fields are allowed in key fields")
+		}
+		field := def.Fields.ForName(selected.Name)
+		if field == nil {
+			return "", fmt.Errorf("unknown field %s.%s", def.Name, selected.Name)
+		}
+
+		fieldType := g.fieldType(def.Name, field)
+		if len(selected.SelectionSet) > 0 {
+			typeInfo, found := g.schema.Types[field.Type.Name()]
+			if !found {
+				return "", fmt.Errorf("field %s.%s has no fields to select", def.Name, field.Name)
+			}
+			nested, err := g.selectionType(typeInfo.Definition, selected.SelectionSet)
+			if err != nil {
+				return "", err
+			}
+			fieldType = "{ " + nested + " }"
+		}
+		if field.Type.NonNull {
+			properties = append(properties, fmt.Sprintf("%s: %s;", g.propertyKey(field.Name), fieldType))
+		} else {
+			properties = append(properties, fmt.Sprintf("%s?: %s;", g.propertyKey(field.Name), g.nullable(fieldType)))
+		}
+	}
+	return strings.Join(properties, " "), nil
+}
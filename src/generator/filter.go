@@ -0,0 +1,225 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Names of the root operation types, in output order
+var rootNames = []string{"Query", "Mutation", "Subscription"}
+
+// Return the names of the types, inputs and enums to emit, or nil to emit all of them
+func (g *Generator) selectedTypes() map[string]bool {
+	prune := g.opts.Prune || len(g.opts.Tags) > 0
+	if !prune && len(g.opts.Only) == 0 && len(g.opts.Exclude) == 0 {
+		return nil
+	}
+
+	walker := &typeWalker{schema: g.schema, reachable: make(map[string]bool), excluded: g.isExcluded}
+	if len(g.opts.Only) > 0 || !prune {
+		// Seed with the allowed types, their dependencies are pulled in by the walker
+		for _, name := range sortedKeys(g.schema.Enums) {
+			if len(g.opts.Only) == 0 || matchesTypePattern(g.opts.Only, name) {
+				walker.visit(name)
+			}
+		}
+		for _, name := range sortedKeys(g.schema.Types) {
+			if len(g.opts.Only) == 0 || matchesTypePattern(g.opts.Only, name) {
+				walker.visit(name)
+			}
+		}
+		for _, name := range sortedKeys(g.schema.Inputs) {
+			if len(g.opts.Only) == 0 || matchesTypePattern(g.opts.Only, name) {
+				walker.visit(name)
+			}
+		}
+	} else if len(g.opts.Tags) > 0 {
+		// Untagged types and interfaces are hidden, so the remaining ones are tagged and kept
+		// even when only untagged root fields reach them
+		for _, name := range sortedKeys(g.schema.Types) {
+			walker.visit(name)
+		}
+	}
+	for i, roots := range g.schema.roots() {
+		for _, name := range sortedKeys(roots) {
+			if g.rootFieldSelected(rootNames[i], name) {
+				walker.visitField(roots[name])
+			}
+		}
+	}
+	return walker.reachable
+}
+
+// Check whether a root field is emitted. With an allow-list only matching root fields are kept.
+func (g *Generator) rootFieldSelected(root string, field string) bool {
+	if matchesFieldPattern(g.opts.Exclude, root, field) {
+		return false
+	}
+	return len(g.opts.Only) == 0 || matchesFieldPattern(g.opts.Only, root, field)
+}
+
+// Check whether a type or enum is on the deny-list or hidden by the internal directive or tag filtering
+func (g *Generator) isExcluded(name string) bool {
+	return matchesTypePattern(g.opts.Exclude, name) || g.schema.hidden[name]
+}
+
+// Check whether a named type is generated: a root type, or a type, input, enum, union or scalar of the schema
+// that is not excluded
+func (g *Generator) isResolved(name string) bool {
+	return slices.Contains(rootNames, name) || (!g.isExcluded(name) && g.schema.definition(name) != nil)
+}
+
+// Return the type of a reference to a type that is not generated, such as an excluded type when the checks are
+// skipped: unknown, mixed for Flow, with a comment naming the type instead of an undeclared identifier
+func (g *Generator) unresolvedType(name string) string {
+	fallback := "unknown"
+	if g.opts.Target == TargetFlow {
+		fallback = "mixed"
+	}
+	return fmt.Sprintf("%s /* %s is not generated */", fallback, name)
+}
+
+// Check emitted fields for references to excluded types, which would be left undeclared
+func (g *Generator) checkExcludedReferences() error {
+	if (len(g.opts.Exclude) == 0 && len(g.schema.hidden) == 0) || g.opts.SkipChecks {
+		return nil
+	}
+
+	var errs []error
+	g.forEachField(func(owner string, field *ast.FieldDefinition) {
+		if name := field.Type.Name(); g.isExcluded(name) {
+			errs = append(errs, positionErrorf(field.Position, "%s.%s references excluded type %s", owner, field.Name, name))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Check whether a type name matches a pattern without a field part
+func matchesTypePattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, ".") && matchPattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check whether a root field matches a Root.field pattern. A bare root name matches all of its fields.
+func matchesFieldPattern(patterns []string, root string, field string) bool {
+	for _, pattern := range patterns {
+		patternRoot, patternField, found := strings.Cut(pattern, ".")
+		if !found {
+			patternField = "*"
+		}
+		if matchPattern(patternRoot, root) && matchPattern(patternField, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match a name against a glob pattern such as User* or *Input
+func matchPattern(pattern string, name string) bool {
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+// Collects the types, inputs, enums and unions reachable from a set of types and fields,
+// following field types, argument types, union members, interface implementations and implementers
+type typeWalker struct {
+	schema    *Schema
+	reachable map[string]bool
+	// Types that are never visited
+	excluded func(name string) bool
+}
+
+func (w *typeWalker) visit(name string) {
+	if w.reachable[name] || w.excluded(name) {
+		return
+	}
+	references, found := w.schema.references(name)
+	if !found {
+		return
+	}
+	w.reachable[name] = true
+	for _, reference := range references {
+		w.visit(reference)
+	}
+}
+
+// Return the definitions a type, input, enum or union pulls in: union members, input field types,
+// field and argument types, implemented interfaces, and the implementers of an interface.
+// Found is false for scalars and unknown names, which are not walked.
+func (s *Schema) references(name string) (references []string, found bool) {
+	if _, found := s.Enums[name]; found {
+		return nil, true
+	}
+	if union, found := s.Unions[name]; found {
+		return union.Types, true
+	}
+	if input, found := s.Inputs[name]; found {
+		for _, field := range input.Fields {
+			references = append(references, field.Type.Name())
+		}
+		return references, true
+	}
+	typeInfo, found := s.Types[name]
+	if !found {
+		return nil, false
+	}
+
+	def := typeInfo.Definition
+	for _, field := range def.Fields {
+		references = append(references, field.Type.Name())
+		for _, arg := range field.Arguments {
+			references = append(references, arg.Type.Name())
+		}
+	}
+	for _, iface := range def.Interfaces {
+		references = append(references, iface)
+	}
+	if def.Kind == ast.Interface {
+		references = append(references, s.implementers(name)...)
+	}
+	return references, true
+}
+
+// Visit the named type of a field and of each of its arguments
+func (w *typeWalker) visitField(field *ast.FieldDefinition) {
+	w.visit(field.Type.Name())
+	for _, arg := range field.Arguments {
+		w.visit(arg.Type.Name())
+	}
+}
+
+// Return the root operation fields, in Query, Mutation, Subscription order
+func (s *Schema) roots() []map[string]*ast.FieldDefinition {
+	return []map[string]*ast.FieldDefinition{s.Queries, s.Mutations, s.Subscriptions}
+}
+
+// Return the names of the types implementing an interface, sorted
+func (s *Schema) implementers(iface string) []string {
+	s.indexImplementers()
+	return s.implementersIndex[iface]
+}
+
+// Build the index of the implementers of each interface, if the schema changed since it was built
+func (s *Schema) indexImplementers() {
+	if s.implementersIndex != nil {
+		return
+	}
+	s.implementersIndex = make(map[string][]string)
+	for _, name := range sortedKeys(s.Types) {
+		for _, implemented := range s.Types[name].Definition.Interfaces {
+			index := s.implementersIndex[implemented]
+			if len(index) == 0 || index[len(index)-1] != name {
+				s.implementersIndex[implemented] = append(index, name)
+			}
+		}
+	}
+}
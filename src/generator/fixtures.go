@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Example value of the DateTime scalar and of scalars mapped to Date
+const fixtureDateTime = "2024-01-01T00:00:00Z"
+
+// A JSON object whose members keep the order of the schema fields
+type fixtureObject []fixtureMember
+
+type fixtureMember struct {
+	name  string
+	value any
+}
+
+func (o fixtureObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(member.name)
+		value, err := json.Marshal(member.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// Generate example JSON payloads conforming to the schema, for contract tests, documentation examples and
+// mock servers: a value of each object type under "types", and the data of a response selecting each root field
+// under "fields", e.g. "Query.user": { "user": { ... } }. Objects are nested up to DefaultOperationDepth levels,
+// lists hold one item, and enums and abstract types take their first value or member.
+func (g *Generator) emitFixtures(ctx context.Context, w io.Writer) error {
+	selected := g.selectedTypes()
+	types := fixtureObject{}
+	for _, name := range g.objectTypeNames(selected) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		types = append(types, fixtureMember{name, g.fixtureObjectValue(name, DefaultOperationDepth)})
+	}
+
+	fields := fixtureObject{}
+	for i, roots := range g.schema.roots() {
+		for _, name := range orderedKeys(g, roots, rootNames[i]) {
+			field := roots[name]
+			if !g.rootFieldSelected(rootNames[i], name) || g.isExcluded(field.Type.Name()) {
+				continue
+			}
+			var data fixtureObject
+			if value, ok := g.fixtureValue(rootNames[i], field, field.Type, DefaultOperationDepth+1); ok {
+				data = fixtureObject{{name, value}}
+			}
+			fields = append(fields, fixtureMember{rootNames[i] + "." + name, data})
+		}
+	}
+
+	data, err := json.MarshalIndent(fixtureObject{{"types", types}, {"fields", fields}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Return an example object of an object type or interface, starting with its __typename,
+// with the object fields nested up to the given depth. Interfaces take the shape of their first implementer.
+func (g *Generator) fixtureObjectValue(name string, depth int) fixtureObject {
+	if typeInfo, found := g.schema.Types[name]; found && typeInfo.Definition.Kind == ast.Interface {
+		for _, implementer := range g.schema.implementers(name) {
+			if !g.isExcluded(implementer) {
+				return g.fixtureObjectValue(implementer, depth)
+			}
+		}
+	}
+
+	object := fixtureObject{{"__typename", name}}
+	typeInfo, found := g.schema.Types[name]
+	if !found {
+		return object
+	}
+	for _, field := range g.objectFields(typeInfo.Definition) {
+		if g.isExcluded(field.Type.Name()) || g.isUploadOutput(name, field) {
+			continue
+		}
+		if value, ok := g.fixtureValue(name, field, field.Type, depth); ok {
+			object = append(object, fixtureMember{field.Name, value})
+		}
+	}
+	return object
+}
+
+// Return an example value of a field type, or false for an object deeper than the remaining depth,
+// which is left out of the payload like an unselected field
+func (g *Generator) fixtureValue(owner string, field *ast.FieldDefinition, typ *ast.Type, depth int) (any, bool) {
+	if typ.Elem != nil {
+		item, ok := g.fixtureValue(owner, field, typ.Elem, depth)
+		if !ok {
+			return nil, false
+		}
+		return []any{item}, true
+	}
+
+	name := typ.Name()
+	if enum, found := g.schema.Enums[name]; found {
+		if len(enum.EnumValues) == 0 {
+			return nil, true
+		}
+		return enum.EnumValues[0].Name, true
+	}
+	if union, found := g.schema.Unions[name]; found {
+		if depth == 0 {
+			return nil, false
+		}
+		for _, member := range union.Types {
+			if !g.isExcluded(member) {
+				return g.fixtureObjectValue(member, depth-1), true
+			}
+		}
+		return fixtureObject{{"__typename", name}}, true
+	}
+	if _, found := g.schema.Types[name]; found {
+		if depth == 0 {
+			return nil, false
+		}
+		return g.fixtureObjectValue(name, depth-1), true
+	}
+	return g.fixtureScalar(name, field), true
+}
+
+// Return an example value of a scalar: the field name for strings, so that payloads read naturally,
+// and null for scalars mapped to types without a JSON example
+func (g *Generator) fixtureScalar(name string, field *ast.FieldDefinition) any {
+	switch name {
+	case "ID":
+		return "1"
+	case "Float":
+		return 1.5
+	case "DateTime":
+		return fixtureDateTime
+	}
+	switch tsType, _ := g.scalarType(name); tsType {
+	case "string":
+		return field.Name
+	case "number", "bigint":
+		return 1
+	case "boolean":
+		return true
+	case "Date":
+		return fixtureDateTime
+	case "unknown", "any", "Record<string, unknown>":
+		return fixtureObject{}
+	}
+	return nil
+}
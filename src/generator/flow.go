@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Scalar types differing between Flow and TypeScript, which has no unknown or Record
+var flowScalars = map[string]string{
+	"unknown":                 "mixed",
+	"Record<string, unknown>": "{ [key: string]: mixed }",
+}
+
+// Generate Flow type declarations from the same model as the TypeScript output
+func (g *Generator) emitFlow(ctx context.Context, w io.Writer) error {
+	schema := g.schema
+	selected := g.selectedTypes()
+	file := bufio.NewWriter(w)
+
+	// Header
+	file.WriteString(`/**
+ * -------------------------------------------------------
+ * THIS FILE WAS AUTOMATICALLY GENERATED (DO NOT MODIFY)
+ * -------------------------------------------------------
+`)
+	for _, line := range g.headerMetadata() {
+		file.WriteString(" * " + line + "\n")
+	}
+	file.WriteString(" *\n * @flow\n */\n\n")
+	g.writeLintSuppressions(file, []string{SuppressEslint})
+	g.writeImports(file)
+
+	// Generate enums as string literal unions
+	for _, name := range orderedKeys(g, schema.Enums, "") {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if selected != nil && !selected[name] {
+			continue
+		}
+		enum := schema.Enums[name]
+		values := make([]string, len(enum.EnumValues))
+		for i, value := range enum.EnumValues {
+			values[i] = fmt.Sprintf("'%s'", value.Name)
+		}
+		file.WriteString(fmt.Sprintf("export type %s = %s;\n\n", g.tsName(enum.Name), strings.Join(values, " | ")))
+	}
+
+	// Generate object types
+	for _, name := range orderedKeys(g, schema.Types, "") {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if selected != nil && !selected[name] {
+			continue
+		}
+		typeInfo := schema.Types[name]
+		file.WriteString(fmt.Sprintf("export type %s = {\n", g.tsName(typeInfo.Name)))
+		for _, field := range g.objectFields(typeInfo.Definition) {
+			g.writeFlowField(file, typeInfo.Name, field)
+		}
+		file.WriteString("};\n\n")
+	}
+	g.writeUnions(file, selected)
+
+	// Generate input types
+	for _, name := range orderedKeys(g, schema.Inputs, "") {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if selected != nil && !selected[name] {
+			continue
+		}
+		input := schema.Inputs[name]
+		file.WriteString(fmt.Sprintf("export type %s = {\n", g.tsName(input.Name)))
+		for _, field := range input.Fields {
+			g.writeFlowField(file, input.Name, field)
+		}
+		file.WriteString("};\n\n")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Generate root types
+	for i, fields := range schema.roots() {
+		var fieldNames []string
+		for _, fieldName := range orderedKeys(g, fields, rootNames[i]) {
+			if g.rootFieldSelected(rootNames[i], fieldName) {
+				fieldNames = append(fieldNames, fieldName)
+			}
+		}
+		if len(fieldNames) == 0 {
+			continue
+		}
+
+		file.WriteString(fmt.Sprintf("export type %s = {\n", g.tsName(rootNames[i])))
+		for _, fieldName := range fieldNames {
+			g.writeFlowField(file, rootNames[i], fields[fieldName])
+		}
+		file.WriteString("};\n\n")
+	}
+
+	return file.Flush()
+}
+
+// Generate a single object type property. Nullable fields are optional maybe types.
+func (g *Generator) writeFlowField(file *bufio.Writer, owner string, field *ast.FieldDefinition) {
+	if g.isUploadOutput(owner, field) {
+		return
+	}
+	fieldType := g.convertGraphqlTypeToTs(field.Type.String())
+	if override, found := g.fieldTypeOverride(owner, field.Name); found {
+		fieldType = override.tsType
+	}
+	if g.renamesReserved(field) {
+		file.WriteString(fmt.Sprintf("  /** GraphQL field: %s */\n", field.Name))
+	}
+	if field.Type.NonNull {
+		file.WriteString(fmt.Sprintf("  %s: %s,\n", g.propertyName(field), fieldType))
+	} else {
+		file.WriteString(fmt.Sprintf("  %s?: ?%s,\n", g.propertyName(field), fieldType))
+	}
+}
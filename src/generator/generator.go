@@ -0,0 +1,173 @@
+// Package generator converts GraphQL schema files into TypeScript declarations.
+// It is the engine behind the generate-types CLI and can be embedded by other Go tools.
+package generator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// Generator merges schema files into a Schema and emits it as TypeScript.
+// All its state is held by the instance, so generators can be created per run, and the methods
+// of one generator can be called from several goroutines: they run one at a time.
+type Generator struct {
+	// Serializes the exported methods, as emission keeps state on the generator
+	mu     sync.Mutex
+	opts   Options
+	schema *Schema
+	// Parsed schemas keyed by content hash, reused across rebuilds of the same generator
+	parsed *ParseCache
+	// Types referenced by the declarations written so far, collected while emitting split files
+	references *references
+	// Content hashes of the added sources
+	sourceHashes []string
+	// Leading comments of the added sources by source name, collected with the JSDoc option
+	comments map[string]lineComments
+}
+
+// NewGenerator creates a generator with an empty schema, configured by the given options
+func NewGenerator(opts ...Option) *Generator {
+	g := &Generator{schema: NewSchema()}
+	for _, opt := range opts {
+		opt(&g.opts)
+	}
+	g.parsed = g.opts.ParseCache
+	if g.parsed == nil {
+		g.parsed = NewParseCache()
+	}
+	return g
+}
+
+// ParseCache holds parsed schema files by content hash. It can be shared by several generators,
+// such as the generators of each output of a run, and is safe for concurrent use.
+type ParseCache struct {
+	mu      sync.Mutex
+	schemas map[string]*parsedSchema
+}
+
+// A parsed schema file
+type parsedSchema struct {
+	*ast.Schema
+	// Names of the types the file extends without defining them, merged into the definitions of other files
+	extensions map[string]bool
+}
+
+// NewParseCache creates an empty parse cache
+func NewParseCache() *ParseCache {
+	return &ParseCache{schemas: make(map[string]*parsedSchema)}
+}
+
+func (c *ParseCache) get(key string) (*parsedSchema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	schema, found := c.schemas[key]
+	return schema, found
+}
+
+func (c *ParseCache) put(key string, schema *parsedSchema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schemas[key] = schema
+}
+
+// Schema returns the definitions merged so far. Unlike the other methods,
+// it must not be used while another goroutine adds sources.
+func (g *Generator) Schema() *Schema {
+	return g.schema
+}
+
+// AddFile reads, parses and merges a single GraphQL schema file
+func (g *Generator) AddFile(ctx context.Context, path string) error {
+	fileContent, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read file %s: %v", path, err)
+	}
+	return g.AddSource(ctx, path, string(fileContent), "")
+}
+
+// AddSource parses and merges schema content. The name is used in messages.
+// If hash is not empty, the parsed schema is cached under it and reused when the same hash is added again.
+func (g *Generator) AddSource(ctx context.Context, name string, content string, hash string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	schema, err := g.parse(name, content, hash)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := g.schema.merge(schema, name, g); err != nil {
+		return err
+	}
+	if g.opts.JSDoc {
+		comments, err := scanComments(name, content)
+		if err != nil {
+			return parseError(name, err)
+		}
+		if g.comments == nil {
+			g.comments = make(map[string]lineComments)
+		}
+		g.comments[name] = comments
+	}
+	g.sourceHashes = append(g.sourceHashes, contentHash(content))
+	return nil
+}
+
+// Return the hex-encoded sha256 hash of content
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Parse schema content, reusing the parsed schema if the content is unchanged.
+// The declared directives depend on the internal directive, so it is part of the cache key.
+func (g *Generator) parse(name string, content string, hash string) (*parsedSchema, error) {
+	key := g.internalDirective() + "@" + hash
+	if schema, found := g.parsed.get(key); found && hash != "" {
+		g.debugf("Reusing parsed file: %s\n", name)
+		return schema, nil
+	}
+
+	g.debugf("Parsing file: %s\n", name)
+
+	// Parse the schema, like gqlparser.LoadSchema, keeping the document to find the extended types
+	source := &ast.Source{Name: name, Input: content}
+	document, err := parser.ParseSchemas(validator.Prelude, g.directivesSource(content), source)
+	if err != nil {
+		return nil, parseError(name, err)
+	}
+	loaded, err := validator.ValidateSchemaDocument(document)
+	if err != nil {
+		return nil, parseError(name, err)
+	}
+	schema := &parsedSchema{Schema: loaded, extensions: make(map[string]bool)}
+	for _, ext := range document.Extensions {
+		if ext.Position != nil && ext.Position.Src == source && document.Definitions.ForName(ext.Name) == nil {
+			schema.extensions[ext.Name] = true
+		}
+	}
+
+	if hash != "" {
+		g.parsed.put(key, schema)
+	}
+	return schema, nil
+}
+
+func (g *Generator) debugf(format string, a ...any) {
+	if g.opts.DebugLog != nil {
+		fmt.Fprintf(g.opts.DebugLog, format, a...)
+	}
+}
@@ -0,0 +1,2486 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Helper function to build a generator from inline schema sources
+func newTestGenerator(t *testing.T, sources ...string) *Generator {
+	t.Helper()
+	gen := NewGenerator()
+	for i, source := range sources {
+		if err := gen.AddSource(context.Background(), fmt.Sprintf("schema%d.graphql", i+1), source, ""); err != nil {
+			t.Fatalf("Failed to add source: %v", err)
+		}
+	}
+	return gen
+}
+
+// Helper function to emit the generator output as a string
+func emit(t *testing.T, gen *Generator) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := gen.Emit(context.Background(), &buf); err != nil {
+		t.Fatalf("Failed to emit: %v", err)
+	}
+	return buf.String()
+}
+
+func expectContains(t *testing.T, output string, contents ...string) {
+	t.Helper()
+	for _, content := range contents {
+		if !strings.Contains(output, content) {
+			t.Errorf("Expected content not found in output: %s\n%s", content, output)
+		}
+	}
+}
+
+func expectNotContains(t *testing.T, output string, contents ...string) {
+	t.Helper()
+	for _, content := range contents {
+		if strings.Contains(output, content) {
+			t.Errorf("Unexpected content found in output: %s\n%s", content, output)
+		}
+	}
+}
+
+func TestEmit(t *testing.T) {
+	gen := newTestGenerator(t, `
+		enum Role { ADMIN MEMBER }
+		type User { id: ID! role: Role tags: [String!]! }
+		type Query { me: User }
+	`)
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export enum Role {\n  ADMIN = 'ADMIN',\n  MEMBER = 'MEMBER',\n}",
+		"export interface User {\n  id: string;\n  role?: Nullable<Role>;\n  tags: Array<string>;\n}",
+		"export interface Query {\n  me?: Nullable<User>;\n}",
+	)
+	expectNotContains(t, output, "__Schema", "__TypeKind")
+}
+
+func TestConflictingDefinitions(t *testing.T) {
+	gen := NewGenerator()
+	if err := gen.AddSource(context.Background(), "a.graphql", "type User { id: ID! }", ""); err != nil {
+		t.Fatalf("Failed to add source: %v", err)
+	}
+	if err := gen.AddSource(context.Background(), "b.graphql", "type User { id: String! }", ""); err == nil {
+		t.Errorf("Expected conflicting definitions error")
+	}
+
+	gen.AddSource(context.Background(), "c.graphql", "enum Role { ADMIN MEMBER }", "")
+	err := gen.AddSource(context.Background(), "d.graphql", "enum Role { ADMIN GUEST }", "")
+	if err == nil || !strings.HasSuffix(err.Error(), "definitions (previously defined at c.graphql:1:6): value GUEST only in d.graphql; value MEMBER only in c.graphql") {
+		t.Errorf("Expected the differing enum values in the error, got: %v", err)
+	}
+
+	// Order, descriptions and directives may differ
+	gen = NewGenerator()
+	if err := gen.AddSource(context.Background(), "a.graphql", `type User { id: ID! "The name" name: String } enum Role { ADMIN MEMBER }`, ""); err != nil {
+		t.Fatalf("Failed to add source: %v", err)
+	}
+	if err := gen.AddSource(context.Background(), "b.graphql", `"A user" type User { name: String @deprecated id: ID! } enum Role { MEMBER ADMIN }`, ""); err != nil {
+		t.Errorf("Expected reordered definitions to be accepted: %v", err)
+	}
+
+	gen = NewGenerator(WithSkipChecks(true))
+	gen.AddSource(context.Background(), "a.graphql", "type User { id: ID! }", "")
+	if err := gen.AddSource(context.Background(), "b.graphql", "type User { id: String! }", ""); err != nil {
+		t.Errorf("Expected conflict to be ignored with SkipChecks: %v", err)
+	}
+
+	gen = NewGenerator(WithSkipChecksTypes("Money"))
+	gen.AddSource(context.Background(), "a.graphql", "type Money { amount: Int! } type User { id: ID! }", "")
+	if err := gen.AddSource(context.Background(), "b.graphql", "type Money { amount: String! }", ""); err != nil {
+		t.Errorf("Expected conflict to be ignored for a listed type: %v", err)
+	}
+	expectContains(t, emit(t, gen), "  amount: number;\n")
+	if err := gen.AddSource(context.Background(), "c.graphql", "type User { id: String! }", ""); err == nil {
+		t.Error("Expected conflicts of other types to be reported")
+	}
+}
+
+func TestConflictingRootFields(t *testing.T) {
+	gen := NewGenerator()
+	gen.AddSource(context.Background(), "a.graphql", "type User { id: ID! } type Query { getUser(id: ID!): User }", "")
+	if err := gen.AddSource(context.Background(), "b.graphql", "type User { id: ID! } type Query { getUser(id: ID!): User }", ""); err != nil {
+		t.Errorf("Expected identical root fields to be accepted: %v", err)
+	}
+	err := gen.AddSource(context.Background(), "c.graphql", "type User { id: ID! } type Query { getUser(email: String): User! }", "")
+	expected := "c.graphql:1:36: Query.getUser has conflicting definitions (previously defined at a.graphql:1:36): " +
+		"type is User in a.graphql but User! in c.graphql; argument email only in c.graphql; argument id only in a.graphql"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Unexpected root field conflict error: %v", err)
+	}
+
+	gen = NewGenerator(WithSkipChecks(true))
+	gen.AddSource(context.Background(), "a.graphql", "type Mutation { save(id: ID!): Boolean }", "")
+	if err := gen.AddSource(context.Background(), "b.graphql", "type Mutation { save: Int }", ""); err != nil {
+		t.Errorf("Expected conflict to be ignored with SkipChecks: %v", err)
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	gen := NewGenerator(WithDeclarationOrder(true))
+	sources := []string{
+		"extend enum Role { GUEST } extend input UserFilter { role: Role }",
+		`interface Node { id: ID! } type User implements Node { id: ID! } enum Role { ADMIN } input UserFilter { name: String }
+			type Query { users(filter: UserFilter): [User!]! }`,
+		"extend interface Node { createdAt: String } extend type User { name: String }",
+	}
+	for i, source := range sources {
+		if err := gen.AddSource(context.Background(), fmt.Sprintf("%d.graphql", i), source, ""); err != nil {
+			t.Fatalf("Failed to add source %d: %v", i, err)
+		}
+	}
+
+	expectContains(t, emit(t, gen),
+		"export enum Role {\n  ADMIN = 'ADMIN',\n  GUEST = 'GUEST',\n}\n",
+		"export interface Node {\n  id: string;\n  createdAt?: Nullable<string>;\n}\n",
+		"export interface User {\n  id: string;\n  name?: Nullable<string>;\n}\n",
+		"export interface UserFilter {\n  name?: Nullable<string>;\n  role?: Nullable<Role>;\n}\n",
+	)
+
+	err := gen.AddSource(context.Background(), "4.graphql", "extend type User { name: Int }", "")
+	if err == nil || !strings.Contains(err.Error(), "User has conflicting extensions (defined at 1.graphql:1:33): field name is String in 2.graphql but Int in 4.graphql") {
+		t.Errorf("Expected the conflicting field of the extension to be reported, got: %v", err)
+	}
+	if err := gen.AddSource(context.Background(), "5.graphql", "extend input Role { id: ID }", ""); err == nil {
+		t.Error("Expected an extension of another kind to be reported")
+	}
+}
+
+func TestAllConflictsReported(t *testing.T) {
+	gen := NewGenerator()
+	gen.AddSource(context.Background(), "a.graphql", "type User { id: ID! } enum Role { ADMIN } type Query { user: User }", "")
+	err := gen.AddSource(context.Background(), "b.graphql", "type User { id: String! } enum Role { GUEST } type Query { user: Int } type Post { id: ID! }", "")
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 3 {
+		t.Fatalf("Expected the three conflicts of the file, got: %v", err)
+	}
+	for _, expected := range []string{"Query.user has", "enum Role has", "type or interface User has"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q in the conflicts, got: %v", expected, err)
+		}
+	}
+	if _, found := gen.Schema().Types["Post"]; !found {
+		t.Error("Expected the definitions after a conflict to be merged")
+	}
+	if gen.Schema().Types["User"].Definition.Fields.ForName("id").Type.Name() != "ID" {
+		t.Error("Expected the first definition of a conflicting type to be kept")
+	}
+}
+
+func TestConflictStrategies(t *testing.T) {
+	addSources := func(gen *Generator) error {
+		gen.AddSource(context.Background(), "a.graphql", "type User { id: ID! name: String } enum Role { ADMIN } type Query { me: User }", "")
+		return gen.AddSource(context.Background(), "b.graphql", "type User { id: ID! email: String } enum Role { GUEST } type Query { me: User! }", "")
+	}
+
+	gen := NewGenerator(WithConflicts(ConflictLastWins))
+	if err := addSources(gen); err != nil {
+		t.Fatalf("Expected conflicts to be resolved: %v", err)
+	}
+	output := emit(t, gen)
+	expectContains(t, output, "export interface User {\n  id: string;\n  email?: Nullable<string>;\n}", "  GUEST = 'GUEST',\n", "  me: User;\n")
+
+	gen = NewGenerator(WithConflicts(ConflictMergeFields), WithTypeConflict("Query", ConflictFirstWins))
+	if err := addSources(gen); err != nil {
+		t.Fatalf("Expected conflicts to be merged: %v", err)
+	}
+	output = emit(t, gen)
+	expectContains(t, output,
+		"export interface User {\n  id: string;\n  name?: Nullable<string>;\n  email?: Nullable<string>;\n}",
+		"export enum Role {\n  ADMIN = 'ADMIN',\n  GUEST = 'GUEST',\n}",
+		"  me?: Nullable<User>;\n",
+	)
+
+	gen = NewGenerator(WithConflicts(ConflictMergeFields))
+	gen.AddSource(context.Background(), "a.graphql", "type User { id: ID! }", "")
+	err := gen.AddSource(context.Background(), "b.graphql", "type User { id: String! name: String }", "")
+	if err == nil || !strings.HasSuffix(err.Error(), "field id is ID! in a.graphql but String! in b.graphql") {
+		t.Errorf("Expected merged fields with different types to conflict, got %v", err)
+	}
+
+	gen = NewGenerator(WithSkipChecks(true), WithTypeConflict("Role", ConflictError))
+	if err := addSources(gen); err == nil || !strings.Contains(err.Error(), "enum Role") {
+		t.Errorf("Expected the type strategy to take precedence over SkipChecks, got %v", err)
+	}
+
+	gen = NewGenerator(WithConflicts("newest"))
+	if err := gen.AddSource(context.Background(), "a.graphql", "type User { id: ID! }", ""); err == nil {
+		t.Error("Expected an error for an unknown conflict strategy")
+	}
+}
+
+func TestScalarOption(t *testing.T) {
+	gen := NewGenerator(WithScalar("DateTime", "Date"), WithScalar("Money", "string"))
+	if err := gen.AddSource(context.Background(), "a.graphql", "scalar DateTime scalar Money type Order { createdAt: DateTime! total: Money! }", ""); err != nil {
+		t.Fatalf("Failed to add source: %v", err)
+	}
+
+	expectContains(t, emit(t, gen), "  createdAt: Date;\n", "  total: string;\n")
+}
+
+func TestEmitCancelled(t *testing.T) {
+	gen := newTestGenerator(t, "type User { id: ID! }")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	if err := gen.Emit(ctx, &buf); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if err := gen.AddSource(ctx, "b.graphql", "type Project { id: ID! }", ""); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestErrorPositions(t *testing.T) {
+	gen := NewGenerator()
+	gen.AddSource(context.Background(), "a.graphql", "type User {\n  id: ID!\n}", "")
+	err := gen.AddSource(context.Background(), "b.graphql", "\ntype User {\n  id: String!\n}", "")
+	if err == nil || err.Error() != "b.graphql:2:6: type or interface User has conflicting definitions (previously defined at a.graphql:1:6): field id is ID! in a.graphql but String! in b.graphql" {
+		t.Errorf("Unexpected conflict error: %v", err)
+	}
+
+	err = gen.AddSource(context.Background(), "c.graphql", "type Project {\n  owner: Missing\n}", "")
+	var posErr *PositionError
+	if !errors.As(err, &posErr) || posErr.File != "c.graphql" || posErr.Line != 2 || posErr.Column != 10 {
+		t.Errorf("Unexpected parse error: %#v", err)
+	}
+}
+
+func TestUnmappedScalarWarnings(t *testing.T) {
+	gen := newTestGenerator(t, `
+		scalar Money
+		scalar DateTime
+		type Order { total: Money! createdAt: DateTime! }
+		type Query { balance: Money }
+	`)
+
+	warnings := gen.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %v", warnings)
+	}
+	if warnings[0].Code != WarnUnmappedScalar || !strings.Contains(warnings[0].Message, "used by Order.total (schema1.graphql:4:16), Query.balance (schema1.graphql:5:16)") {
+		t.Errorf("Unexpected warning: %s", warnings[0])
+	}
+
+	gen = NewGenerator(WithScalar("Money", "string"))
+	gen.AddSource(context.Background(), "a.graphql", "scalar Money type Order { total: Money! }", "")
+	if warnings := gen.Warnings(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings for mapped scalar, got %v", warnings)
+	}
+}
+
+func TestStrictScalars(t *testing.T) {
+	gen := NewGenerator(WithStrictScalars(true))
+	gen.AddSource(context.Background(), "a.graphql", "scalar Money type Order { total: Money! }", "")
+
+	var buf bytes.Buffer
+	if err := gen.Emit(context.Background(), &buf); err == nil || !strings.Contains(err.Error(), "scalar Money has no TypeScript mapping") {
+		t.Errorf("Expected strict scalars error, got %v", err)
+	}
+
+	gen = NewGenerator(WithStrictScalars(true), WithScalar("Money", "string"))
+	gen.AddSource(context.Background(), "a.graphql", "scalar Money type Order { total: Money! }", "")
+	expectContains(t, emit(t, gen), "  total: string;\n")
+}
+
+func TestLint(t *testing.T) {
+	gen := NewGenerator(
+		WithLintRule(LintDescriptions, SeverityWarning),
+		WithLintRule(LintEnumValues, SeverityOff),
+		WithForbiddenPrefixes("Tmp"),
+	)
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum role { admin }
+		"A project"
+		type TmpProject {
+			"The id"
+			id: ID!
+			Owner_name: String
+		}
+	`, "")
+
+	var issues []string
+	for _, issue := range gen.Lint() {
+		issues = append(issues, issue.String())
+	}
+	expected := []string{
+		"a.graphql:2:8: warn [type-names]: role should be PascalCase",
+		"a.graphql:2:8: warn [descriptions]: role has no description",
+		"a.graphql:4:8: error [forbidden-prefixes]: TmpProject starts with forbidden prefix \"Tmp\"",
+		"a.graphql:7:4: warn [field-names]: TmpProject.Owner_name should be camelCase",
+		"a.graphql:7:4: warn [descriptions]: TmpProject.Owner_name has no description",
+	}
+	if strings.Join(issues, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected lint issues:\n%s", strings.Join(issues, "\n"))
+	}
+}
+
+func TestOrphanTypes(t *testing.T) {
+	gen := NewGenerator(WithLintRule(LintTypeNames, SeverityOff), WithLintRule(LintOrphanTypes, SeverityError))
+	gen.AddSource(context.Background(), "a.graphql", `
+		type User { id: ID! status: Status! }
+		enum Status { ACTIVE }
+		type LegacyUser { id: ID! }
+		input LegacyFilter { id: ID }
+		type Query { me: User }
+	`, "")
+
+	var issues []string
+	for _, issue := range gen.Lint() {
+		issues = append(issues, issue.String())
+	}
+	expected := []string{
+		"a.graphql:5:9: error [orphan-types]: LegacyFilter is not reachable from any Query, Mutation or Subscription field",
+		"a.graphql:4:8: error [orphan-types]: LegacyUser is not reachable from any Query, Mutation or Subscription field",
+	}
+	if strings.Join(issues, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected lint issues:\n%s", strings.Join(issues, "\n"))
+	}
+}
+
+func TestPrune(t *testing.T) {
+	schema := `
+		enum Status { OPEN CLOSED }
+		enum Unused { A }
+		interface Node { id: ID! }
+		type User implements Node { id: ID! }
+		type Project { id: ID! owner: User! }
+		type Orphan { id: ID! }
+		type Event { id: ID! }
+		type Query { node(id: ID!): Node projects(status: Status): [Project!]! }
+		type Subscription { events: Event! }
+	`
+
+	output := emit(t, newTestGenerator(t, schema))
+	expectContains(t, output, "export interface Orphan", "export enum Unused", "export interface Subscription {\n  events: Event;\n}")
+
+	gen := NewGenerator(WithPrune(true))
+	gen.AddSource(context.Background(), "a.graphql", schema, "")
+	output = emit(t, gen)
+	expectContains(t, output, "export enum Status", "export interface Node", "export interface User", "export interface Project", "export interface Event")
+	expectNotContains(t, output, "export interface Orphan", "export enum Unused")
+}
+
+func TestOnly(t *testing.T) {
+	gen := NewGenerator(WithOnly("Project", "Query.get*"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum Role { ADMIN }
+		type User { id: ID! role: Role! }
+		type Project { id: ID! owner: User! }
+		type Invoice { id: ID! }
+		type Query { getInvoice: Invoice listUsers: [User!]! }
+		type Mutation { createUser: User! }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output, "export interface Project", "export interface User", "export enum Role", "export interface Invoice", "export interface Query {\n  getInvoice?: Nullable<Invoice>;\n}")
+	expectNotContains(t, output, "listUsers", "export interface Mutation")
+}
+
+func TestExclude(t *testing.T) {
+	schema := `
+		type AdminStats { users: Int! }
+		type User { id: ID! }
+		type Query { me: User adminStats: AdminStats internalUser: User }
+	`
+	gen := NewGenerator(WithExclude("Admin*", "Query.internal*"))
+	gen.AddSource(context.Background(), "a.graphql", schema, "")
+
+	var buf bytes.Buffer
+	err := gen.Emit(context.Background(), &buf)
+	if err == nil || !strings.Contains(err.Error(), "Query.adminStats references excluded type AdminStats") {
+		t.Errorf("Expected excluded reference error, got %v", err)
+	}
+
+	gen = NewGenerator(WithExclude("Admin*", "Query.adminStats", "Query.internal*"))
+	gen.AddSource(context.Background(), "a.graphql", schema, "")
+	output := emit(t, gen)
+	expectContains(t, output, "export interface User", "export interface Query {\n  me?: Nullable<User>;\n}")
+	expectNotContains(t, output, "AdminStats", "internalUser")
+
+	// With the checks skipped, references to excluded types fall back to unknown
+	gen = NewGenerator(WithExclude("Admin*"), WithSkipChecks(true))
+	gen.AddSource(context.Background(), "a.graphql", schema, "")
+	expectContains(t, emit(t, gen), "  adminStats?: Nullable<unknown /* AdminStats is not generated */>;\n")
+	warnings := gen.Warnings()
+	if len(warnings) != 1 || warnings[0].Code != WarnUnresolvedType || !strings.Contains(warnings[0].Message, "used by Query.adminStats (a.graphql:") {
+		t.Errorf("Expected an unresolved type warning, got: %v", warnings)
+	}
+
+	// Unions are declared by every target, so references to them resolve
+	for _, target := range []string{TargetTypescript, TargetFlow} {
+		gen = NewGenerator(WithTarget(target), WithExclude("Post"), WithSkipChecks(true))
+		gen.AddSource(context.Background(), "a.graphql", "type User { id: ID! } type Post { id: ID! } type Comment { id: ID! } union SearchResult = User | Post | Comment type Query { search: [SearchResult!]! }", "")
+		output = emit(t, gen)
+		expectContains(t, output, "export type SearchResult = User | Comment;\n")
+		expectNotContains(t, output, "is not generated")
+		if warnings := gen.Warnings(); len(warnings) != 0 {
+			t.Errorf("Expected no warnings for %s, got: %v", target, warnings)
+		}
+	}
+}
+
+func TestRename(t *testing.T) {
+	gen := NewGenerator(WithRename("Event", "ApiEvent"), WithRename("Kind", "EventKind"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum Kind { A }
+		type Event { id: ID! kind: Kind! }
+		type Query { events: [Event!]! }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output, "export enum EventKind {", "export interface ApiEvent {\n  id: string;\n  kind: EventKind;\n}", "events: Array<ApiEvent>;")
+	expectNotContains(t, output, "interface Event ")
+}
+
+func TestFieldTypeOverride(t *testing.T) {
+	gen := NewGenerator(
+		WithFieldType("User.metadata", "./metadata#UserMetadata"),
+		WithFieldType("User.settings", "./metadata#Settings"),
+		WithFieldType("Query.raw", "unknown"),
+	)
+	gen.AddSource(context.Background(), "a.graphql", `
+		scalar JSON
+		type User { metadata: JSON! settings: JSON }
+		type Query { raw: JSON }
+	`, "")
+
+	expectContains(t, emit(t, gen),
+		"import type { Settings, UserMetadata } from './metadata';\n\ntype Nullable",
+		"  metadata: UserMetadata;\n  settings?: Nullable<Settings>;\n",
+		"  raw?: Nullable<unknown>;\n",
+	)
+}
+
+func TestFlowTarget(t *testing.T) {
+	gen := NewGenerator(WithTarget(TargetFlow))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum Role { ADMIN MEMBER }
+		scalar JSONObject
+		type User { id: ID! role: Role meta: JSONObject! }
+		type Query { users: [User!]! }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		" * @flow\n",
+		"export type Role = 'ADMIN' | 'MEMBER';\n",
+		"export type User = {\n  id: string,\n  role?: ?Role,\n  meta: { [key: string]: mixed },\n};\n",
+		"export type Query = {\n  users: Array<User>,\n};\n",
+	)
+	expectNotContains(t, output, "Nullable", "interface")
+
+	// Scalars mapped to unknown are mixed, and inputs are declared
+	gen = NewGenerator(WithTarget(TargetFlow), WithScalar("Cursor", "unknown"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		scalar JSON
+		scalar Cursor
+		input UserFilter { role: Role meta: JSON after: Cursor! }
+		enum Role { ADMIN MEMBER }
+		type User { id: ID! meta: JSON }
+		type Query { users(filter: UserFilter): [User!]! }
+	`, "")
+	output = emit(t, gen)
+	expectContains(t, output,
+		"export type User = {\n  id: string,\n  meta?: ?mixed,\n};\n",
+		"export type UserFilter = {\n  role?: ?Role,\n  meta?: ?mixed,\n  after: mixed,\n};\n",
+	)
+	expectNotContains(t, output, "unknown")
+}
+
+func TestGoTarget(t *testing.T) {
+	gen := NewGenerator(WithTarget(TargetGo), WithGoPackage("api"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum Role { ADMIN SUPER_USER }
+		type User { id: ID! role: Role tags: [String] manager: User! owner_name: String }
+		type Query { users: [User!]! }
+	`, "")
+
+	expectContains(t, emit(t, gen),
+		"package api\n",
+		"type Role string\n\nconst (\n\tRoleAdmin     Role = \"ADMIN\"\n\tRoleSuperUser Role = \"SUPER_USER\"\n)\n",
+		"type User struct {\n\tID        string    `json:\"id\"`\n\tRole      *Role     `json:\"role,omitempty\"`\n\tTags      []*string `json:\"tags,omitempty\"`\n\tManager   *User     `json:\"manager\"`\n\tOwnerName *string   `json:\"owner_name,omitempty\"`\n}\n",
+		"type Query struct {\n\tUsers []User `json:\"users\"`\n}\n",
+	)
+
+	// Inputs are structs and unions interfaces implemented by their members, and the output compiles
+	gen = NewGenerator(WithTarget(TargetGo))
+	gen.AddSource(context.Background(), "a.graphql", `
+		type User { id: ID! }
+		type Post { id: ID! author: User! }
+		union SearchResult = User | Post
+		input PostFilter { authorId: ID, tags: [String!] next: PostFilter }
+		type Query { search(filter: PostFilter!): [SearchResult!]! first: SearchResult }
+	`, "")
+	output := emit(t, gen)
+	expectContains(t, output,
+		"type SearchResult interface {\n\tisSearchResult()\n}\n",
+		"func (User) isSearchResult() {}\n",
+		"func (Post) isSearchResult() {}\n",
+		"type PostFilter struct {\n\tAuthorId *string     `json:\"authorId,omitempty\"`\n\tTags     []string    `json:\"tags,omitempty\"`\n\tNext     *PostFilter `json:\"next,omitempty\"`\n}\n",
+		"type Query struct {\n\tFirst  SearchResult   `json:\"first,omitempty\"`\n\tSearch []SearchResult `json:\"search\"`\n}\n",
+	)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "generated.go", output, 0)
+	if err != nil {
+		t.Fatalf("Expected valid Go, got %v:\n%s", err, output)
+	}
+	if _, err := new(types.Config).Check("generated", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("Expected the Go output to compile, got %v:\n%s", err, output)
+	}
+}
+
+func TestFixturesTarget(t *testing.T) {
+	gen := newTestGenerator(t, `
+		enum Role { ADMIN USER }
+		interface Node { id: ID! }
+		type User implements Node { id: ID! name: String! age: Int score: Float role: Role! friends: [User!]! }
+		type Query { user(id: ID!): User node(id: ID!): Node }
+	`)
+	gen.opts.Target = TargetFixtures
+	output := emit(t, gen)
+	var fixtures struct {
+		Types  map[string]map[string]any
+		Fields map[string]map[string]any
+	}
+	if err := json.Unmarshal([]byte(output), &fixtures); err != nil {
+		t.Fatalf("Expected JSON fixtures, got %v:\n%s", err, output)
+	}
+	expectContains(t, output,
+		"\"User\": {\n      \"__typename\": \"User\",\n      \"id\": \"1\",\n      \"name\": \"name\",\n      \"age\": 1,\n      \"score\": 1.5,\n      \"role\": \"ADMIN\",\n",
+	)
+	friends := fixtures.Types["User"]["friends"].([]any)[0].(map[string]any)
+	if nested := friends["friends"].([]any)[0].(map[string]any); nested["friends"] != nil || nested["name"] != "name" {
+		t.Errorf("Expected objects nested up to the operation depth, got %v", nested)
+	}
+	if node := fixtures.Fields["Query.node"]["node"].(map[string]any); node["__typename"] != "User" {
+		t.Errorf("Expected the interface to take the shape of its implementer, got %v", node)
+	}
+}
+
+func TestSDLTarget(t *testing.T) {
+	gen := NewGenerator(WithTarget(TargetSDL))
+	gen.AddSource(context.Background(), "a.graphql", `
+		directive @auth(role: String!) on FIELD_DEFINITION
+		type User { id: ID! }
+		input CreateUserInput { email: String! }
+		type Query { me: User @auth(role: "user") }
+	`, "")
+	gen.AddSource(context.Background(), "b.graphql", `
+		type User { id: ID! }
+		type Project { id: ID! }
+		union SearchResult = User | Project
+		type Query { search(term: String!): [SearchResult!]! }
+		type Mutation { createUser(input: CreateUserInput!): User! }
+		input CreateUserInput { email: String! }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"directive @auth(role: String!) on FIELD_DEFINITION\n",
+		"input CreateUserInput {\n  email: String!\n}\n",
+		"type Query {\n  me: User @auth(role: \"user\")\n  search(term: String!): [SearchResult!]!\n}\n",
+		"type Mutation {\n  createUser(input: CreateUserInput!): User!\n}\n",
+		"union SearchResult = User | Project\n",
+	)
+
+	// The merged SDL is a valid schema on its own
+	if err := NewGenerator().AddSource(context.Background(), "merged.graphql", output, ""); err != nil {
+		t.Errorf("Merged SDL does not parse: %v\n%s", err, output)
+	}
+}
+
+func TestDocsTarget(t *testing.T) {
+	gen := NewGenerator(WithTarget(TargetDocs))
+	gen.AddSource(context.Background(), "a.graphql", `
+		"A registered user"
+		type User {
+			id: ID!
+			"Login name"
+			login: String @deprecated(reason: "Use email")
+		}
+		enum Role { ADMIN "Regular member" MEMBER }
+		type Query {
+			"Find users"
+			users(role: Role, limit: Int = 20): [User!]!
+		}
+	`, "")
+
+	expectContains(t, emit(t, gen),
+		"## Query\n\n### Query.users\n\nFind users\n\n**Returns:** \\[[User](#user)!\\]!\n\n",
+		"| `role` | [Role](#role) |  |  |\n| `limit` | Int | `20` |  |\n",
+		"## Objects\n\n### User\n\nA registered user\n\n",
+		"| `login` | String | Login name<br>**Deprecated:** Use email |\n",
+		"## Enums\n\n### Role\n\n",
+		"| `MEMBER` | Regular member |\n",
+	)
+}
+
+func TestHTMLTarget(t *testing.T) {
+	gen := NewGenerator(WithTarget(TargetHTML))
+	gen.AddSource(context.Background(), "a.graphql", `
+		"A <registered> user"
+		type User { id: ID! projects(first: Int = 10): [Project!]! }
+		type Project { id: ID! }
+		type Query { me: User }
+	`, "")
+
+	expectContains(t, emit(t, gen),
+		"<!DOCTYPE html>",
+		`<div class="entry" id="Query.me"`,
+		`<strong>Returns:</strong> <code><a href="#User">User</a></code>`,
+		`<p>A &lt;registered&gt; user</p>`,
+		`<td class="name">projects.first</td>`,
+		`<input id="search"`,
+	)
+}
+
+func TestNumericEnums(t *testing.T) {
+	gen := NewGenerator(WithNumericEnums("Status"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum Priority @tsNumeric { LOW MEDIUM HIGH @tsValue(value: 10) }
+		enum Status { OPEN CLOSED }
+		enum Role { ADMIN }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export enum Priority {\n  LOW = 0,\n  MEDIUM = 1,\n  HIGH = 10,\n}",
+		"export enum Status {\n  OPEN = 0,\n  CLOSED = 1,\n}",
+		"export enum Role {\n  ADMIN = 'ADMIN',\n}",
+	)
+
+	// Schemas declaring the directive themselves are accepted as well
+	gen = NewGenerator()
+	if err := gen.AddSource(context.Background(), "b.graphql", "directive @tsNumeric on ENUM\nenum Level @tsNumeric { A }", ""); err != nil {
+		t.Errorf("Failed to add source declaring @tsNumeric: %v", err)
+	}
+}
+
+func TestUnionEnums(t *testing.T) {
+	gen := NewGenerator(WithUnionEnums("Locale"), WithInputDefaults(true))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum CountryCode @tsUnion { FR US }
+		enum Locale { EN_US FR_FR }
+		enum Role { ADMIN }
+		input AddressInput { country: CountryCode! locale: Locale = FR_FR role: Role! }
+		type Query { address(input: AddressInput): Boolean }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export type CountryCode = 'FR' | 'US';\n",
+		"export type Locale = 'EN_US' | 'FR_FR';\n",
+		"export enum Role {\n  ADMIN = 'ADMIN',\n}",
+		"    country: 'FR',\n    locale: 'FR_FR',\n    role: Role.ADMIN,\n",
+	)
+
+	gen = NewGenerator(WithUnionEnums("Level"), WithNumericEnums("Level"))
+	gen.AddSource(context.Background(), "b.graphql", "enum Level { A }\ntype Query { level: Level }", "")
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), "both numeric and a union") {
+		t.Errorf("Expected an error for a numeric union enum, got %v", err)
+	}
+}
+
+func TestTypeNameHelpers(t *testing.T) {
+	gen := NewGenerator(WithTypeNameUnion(true), WithTypeMap(true), WithRename("Project", "ApiProject"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		interface Node { id: ID! }
+		type User implements Node { id: ID! }
+		type Project implements Node { id: ID! }
+		type Query { node: Node }
+	`, "")
+
+	expectContains(t, emit(t, gen),
+		"export type TypeName = 'Project' | 'User';\n",
+		"export interface TypeMap {\n  Node: Node;\n  Project: ApiProject;\n  User: User;\n}\n",
+	)
+}
+
+func TestEnumValues(t *testing.T) {
+	gen := NewGenerator(WithEnumValues(true), WithRename("Role", "UserRole"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum Role { ADMIN MEMBER }
+		enum Priority @tsNumeric { LOW HIGH @tsValue(value: 10) }
+	`, "")
+
+	expectContains(t, emit(t, gen),
+		"export const UserRoleValues = ['ADMIN', 'MEMBER'] as const;\n",
+		"export const PriorityValues = [0, 10] as const;\n",
+	)
+}
+
+func TestEnumHelpers(t *testing.T) {
+	gen := NewGenerator(WithEnumHelpers(true), WithRename("Role", "UserRole"), WithUnionEnums("Status"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum Role { ADMIN MEMBER }
+		enum Priority @tsNumeric { LOW HIGH @tsValue(value: 10) }
+		enum Status { ACTIVE }
+	`, "")
+
+	expectContains(t, emit(t, gen),
+		"export function isUserRole(value: unknown): value is UserRole {\n  return ([UserRole.ADMIN, UserRole.MEMBER] as unknown[]).includes(value);\n}\n",
+		"export function parseUserRole(value: string): UserRole | undefined {\n  switch (value) {\n    case 'ADMIN':\n      return UserRole.ADMIN;\n",
+		"    case 'HIGH':\n      return Priority.HIGH;\n  }\n  return undefined;\n}\n",
+		"export function isStatus(value: unknown): value is Status {\n  return (['ACTIVE'] as unknown[]).includes(value);\n}\n",
+	)
+}
+
+func TestOperationNames(t *testing.T) {
+	gen := NewGenerator(WithOperationNames(true), WithExclude("Mutation.deleteUser"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		type User { id: ID! }
+		type Query { users: [User!]! user(id: ID!): User }
+		type Mutation { createUser: User deleteUser(id: ID!): Boolean }
+		type Subscription { user: User }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export const OperationNames = {\n  createUser: 'createUser',\n  user: 'user',\n  users: 'users',\n} as const;\n",
+		"export type OperationName = typeof OperationNames[keyof typeof OperationNames];\n",
+	)
+	expectNotContains(t, output, "deleteUser: 'deleteUser'")
+}
+
+func TestResultAliases(t *testing.T) {
+	gen := NewGenerator(WithResultAliases(true), WithExclude("Mutation.deleteUser"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		type Project { id: ID! }
+		type User { id: ID! }
+		type Query { getProjects: [Project!]! user(id: ID!): User }
+		type Mutation { createUser: User! user(id: ID!): User deleteUser(id: ID!): Boolean }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export type GetProjectsResult = Array<Project>;\n",
+		"export type UserResult = Nullable<User>;\n",
+		"export type CreateUserResult = User;\n",
+		"export type MutationUserResult = Nullable<User>;\n",
+	)
+	expectNotContains(t, output, "DeleteUserResult")
+
+	// The aliases don't take the names of schema types
+	gen = NewGenerator(WithResultAliases(true), WithPreset(PresetCodegen))
+	gen.AddSource(context.Background(), "a.graphql", `
+		type User { id: ID! }
+		type Post { id: ID! }
+		type UserResult { user: User }
+		union SearchResult = User | Post
+		type Query { search: [SearchResult!]! user: UserResult! }
+	`, "")
+	output = emit(t, gen)
+	expectContains(t, output,
+		"export type SearchResult = User | Post;\n",
+		"export type QuerySearchResult = Array<SearchResult>;\n",
+		"export type QueryUserResult = UserResult;\n",
+	)
+	expectNotContains(t, output, "export type SearchResult = Array", "export type UserResult = UserResult")
+}
+
+func TestInputTypes(t *testing.T) {
+	gen := newTestGenerator(t, `
+		input UserFilter { name: String role: Role! }
+		input Unused { flag: Boolean }
+		enum Role { ADMIN }
+		type User { id: ID! }
+		type Query { users(filter: UserFilter): [User!]! }
+	`)
+
+	expectContains(t, emit(t, gen),
+		"export interface UserFilter {\n  name?: Nullable<string>;\n  role: Role;\n}",
+		"export interface Unused {\n",
+	)
+
+	gen = NewGenerator(WithPrune(true))
+	gen.AddSource(context.Background(), "a.graphql", `
+		input UserFilter { name: String }
+		input Unused { flag: Boolean }
+		type Query { users(filter: UserFilter): [String!]! }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output, "export interface UserFilter {\n")
+	expectNotContains(t, output, "export interface Unused")
+}
+
+func TestResolvers(t *testing.T) {
+	gen := NewGenerator(WithResolvers(true))
+	gen.AddSource(context.Background(), "a.graphql", `
+		type User { id: ID! }
+		type Query { users: [User!]! user(id: ID!, active: Boolean): User }
+		type Mutation { deleteUser(id: ID!): Boolean! }
+		type Subscription { userAdded: User! }
+	`, "")
+
+	expectContains(t, emit(t, gen),
+		"import type { GraphQLResolveInfo } from 'graphql';\n",
+		"export type Resolver<TResult, TParent, TContext, TArgs> = (\n",
+		"export interface QueryUserArgs {\n  id: string;\n  active?: Nullable<boolean>;\n}",
+		"export interface QueryResolvers<TContext = unknown, TParent = {}> {\n"+
+			"  user?: Resolver<Nullable<User>, TParent, TContext, QueryUserArgs>;\n"+
+			"  users?: Resolver<Array<User>, TParent, TContext, Record<string, never>>;\n}",
+		"export interface MutationResolvers<TContext = unknown, TParent = {}> {\n"+
+			"  deleteUser?: Resolver<boolean, TParent, TContext, MutationDeleteUserArgs>;\n}",
+		"  userAdded?: SubscriptionResolver<User, TParent, TContext, Record<string, never>>;\n",
+	)
+}
+
+func TestFederation(t *testing.T) {
+	gen := NewGenerator(WithFederation(true))
+	if err := gen.AddSource(context.Background(), "a.graphql", `
+		type Organization { id: ID! name: String }
+		type User @key(fields: "id") @key(fields: "email organization { id }") @shareable {
+			id: ID!
+			email: String!
+			organization: Organization!
+		}
+		type Product @key(fields: "upc") { upc: String! price: Int @external }
+		type Query { me: User }
+	`, ""); err != nil {
+		t.Fatalf("Failed to add source with federation directives: %v", err)
+	}
+
+	expectContains(t, emit(t, gen),
+		"export type UserKeyFields = { __typename: 'User'; id: string; } | { __typename: 'User'; email: string; organization: { id: string; }; };\n",
+		"export type ProductKeyFields = { __typename: 'Product'; upc: string; };\n",
+		"export type _Any = { __typename: string; [key: string]: unknown };\n",
+		"export type _Entity = Product | User;\n",
+	)
+
+	gen = NewGenerator(WithFederation(true))
+	gen.AddSource(context.Background(), "a.graphql", `type User @key(fields: "missing") { id: ID! }`, "")
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), "unknown field User.missing") {
+		t.Errorf("Expected unknown key field error, got: %v", err)
+	}
+}
+
+func TestInternalDirective(t *testing.T) {
+	gen := newTestGenerator(t, `
+		type User { id: ID! passwordHash: String @internal }
+		type AdminStats @internal { users: Int }
+		enum Role { ADMIN MEMBER SUPPORT @internal }
+		input UserFilter { name: String internalOnly: Boolean @internal }
+		type Query { users(filter: UserFilter, debug: Boolean @internal): [User!]! adminStats: Int @internal }
+	`)
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export interface User {\n  id: string;\n}",
+		"export enum Role {\n  ADMIN = 'ADMIN',\n  MEMBER = 'MEMBER',\n}",
+		"export interface UserFilter {\n  name?: Nullable<string>;\n}",
+	)
+	expectNotContains(t, output, "passwordHash", "AdminStats", "SUPPORT", "internalOnly", "adminStats")
+
+	// Public fields must not reference internal types
+	gen = newTestGenerator(t, `
+		type AdminStats @internal { users: Int }
+		type Query { stats: AdminStats }
+	`)
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), "Query.stats references excluded type AdminStats") {
+		t.Errorf("Expected reference to internal type error, got: %v", err)
+	}
+
+	// The directive name is configurable
+	gen = NewGenerator(WithInternalDirective("private"))
+	gen.AddSource(context.Background(), "a.graphql", "type User { id: ID! secret: String @private }", "")
+	expectNotContains(t, emit(t, gen), "secret")
+}
+
+func TestInheritInterfaceFields(t *testing.T) {
+	source := `
+		interface Node { id: ID! }
+		interface Timestamped { createdAt: String! }
+		type User implements Node & Timestamped { id: ID! createdAt: String! @internal name: String }
+	`
+	expectContains(t, emit(t, newTestGenerator(t, source)), "export interface User {\n  id: string;\n  name?: Nullable<string>;\n}")
+
+	gen := NewGenerator(WithInheritInterfaceFields(true))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	expectContains(t, emit(t, gen), "export interface User {\n  id: string;\n  name?: Nullable<string>;\n  createdAt: string;\n}")
+}
+
+func TestTsNameDirective(t *testing.T) {
+	gen := newTestGenerator(t, `
+		type Customer @tsName(name: "CustomerDto") { id: ID! fullName: String @tsName(name: "name") }
+		enum Tier @tsName(name: "CustomerTier") { FREE }
+		input CustomerInput @tsName(name: "CustomerInputDto") { tier: Tier! }
+		type Query { customer: Customer }
+	`)
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export interface CustomerDto {\n  id: string;\n  name?: Nullable<string>;\n}",
+		"export enum CustomerTier {\n",
+		"export interface CustomerInputDto {\n  tier: CustomerTier;\n}",
+		"  customer?: Nullable<CustomerDto>;\n",
+	)
+
+	// Configured renames take precedence over the directive
+	gen = NewGenerator(WithRename("Customer", "Client"))
+	gen.AddSource(context.Background(), "a.graphql", `type Customer @tsName(name: "CustomerDto") { id: ID! }`, "")
+	expectContains(t, emit(t, gen), "export interface Client {\n")
+}
+
+func TestTags(t *testing.T) {
+	gen := NewGenerator(WithTags("public", "mobile"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		type User @tag(name: "public") { id: ID! role: Role! }
+		type Account { id: ID! @tag(name: "mobile") balance: Int }
+		type AdminStats { users: Int }
+		enum Role { ADMIN MEMBER }
+		type Query {
+			me: User @tag(name: "public")
+			account: Account @tag(name: "mobile")
+			stats: AdminStats
+			partnerData: String @tag(name: "partner")
+		}
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export interface User {\n  id: string;\n  role: Role;\n}",
+		"export interface Account {\n  id: string;\n}",
+		"export enum Role {\n",
+		"export interface Query {\n  account?: Nullable<Account>;\n  me?: Nullable<User>;\n}",
+	)
+	expectNotContains(t, output, "AdminStats", "balance", "partnerData", "stats")
+
+	// Tagged types are kept when only untagged root fields reach them, along with their dependencies
+	gen = NewGenerator(WithTags("public"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		type Product @tag(name: "public") { id: ID! status: Status! }
+		enum Status { ACTIVE ARCHIVED }
+		type Warehouse { id: ID! }
+		type Query { products: [Product!]! warehouses: [Warehouse!]! }
+	`, "")
+	output = emit(t, gen)
+	expectContains(t, output, "export interface Product {\n  id: string;\n  status: Status;\n}", "export enum Status {\n")
+	expectNotContains(t, output, "Warehouse", "products")
+}
+
+func TestDates(t *testing.T) {
+	gen := NewGenerator(WithDates(true))
+	gen.AddSource(context.Background(), "a.graphql", `
+		scalar DateTime
+		type User { id: ID! createdAt: DateTime! lastLogin: DateTime }
+		type Project { name: String! owner: User! members: [User!]! }
+		type Tag { name: String! }
+		input ProjectFilter { dueBefore: DateTime }
+		type Query { projects(filter: ProjectFilter): [Project!]! tags: [Tag!]! }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"  createdAt: Date;\n  lastLogin?: Nullable<Date>;\n",
+		"export const DateFields: Record<string, Record<string, string>> = {\n"+
+			"  Project: { owner: 'User', members: 'User' },\n"+
+			"  ProjectFilter: { dueBefore: 'DateTime' },\n"+
+			"  User: { createdAt: 'DateTime', lastLogin: 'DateTime' },\n"+
+			"};\n",
+		"export function parseDates<T>(typeName: string, value: unknown): T {\n",
+		"export function serializeDates<T>(typeName: string, value: T): unknown {\n",
+	)
+	expectNotContains(t, output, "Tag: {")
+
+	// Without the option DateTime stays a string
+	gen = newTestGenerator(t, "scalar DateTime\ntype User { createdAt: DateTime! }")
+	output = emit(t, gen)
+	expectContains(t, output, "  createdAt: string;\n")
+	expectNotContains(t, output, "DateFields")
+}
+
+func TestBigIntScalars(t *testing.T) {
+	source := `
+		scalar BigInt
+		scalar Long
+		scalar Money
+		type Account { balance: BigInt! views: Long cents: Money }
+	`
+	gen := NewGenerator(WithScalarsRecord(true))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export interface Scalars {\n  ID: string;\n  String: string;\n  Boolean: boolean;\n  Int: number;\n  Float: number;\n  BigInt: bigint;\n  Long: bigint;\n}",
+		"  balance: Scalars['BigInt'];\n  views?: Nullable<Scalars['Long']>;\n",
+	)
+	expectNotContains(t, output, "Scalars['Money']")
+
+	gen = NewGenerator(WithBigInt("string"), WithScalar("Long", "number"), WithScalarsRecord(true))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	output = emit(t, gen)
+	expectContains(t, output,
+		"  BigInt: string;\n  Long: number;\n}",
+		"  balance: Scalars['BigInt'];\n  views?: Nullable<Scalars['Long']>;\n",
+	)
+
+	// Split files import the record from objects.ts
+	gen = NewGenerator(WithScalarsRecord(true))
+	gen.AddSource(context.Background(), "a.graphql", source+"input AccountInput { balance: BigInt! }", "")
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	for _, file := range files {
+		if file.Name == SplitInputs+".ts" {
+			expectContains(t, string(file.Content),
+				"import type { Scalars } from './objects';\n",
+				"  balance: Scalars['BigInt'];\n",
+			)
+		}
+	}
+
+	// Without the option nothing references a Scalars record, so none is emitted
+	output = emit(t, newTestGenerator(t, source))
+	expectContains(t, output, "  balance: bigint;\n")
+	expectNotContains(t, output, "interface Scalars")
+}
+
+func TestUploadScalar(t *testing.T) {
+	gen := NewGenerator(WithResolvers(true))
+	gen.AddSource(context.Background(), "a.graphql", `
+		scalar Upload
+		input AvatarInput { file: Upload! caption: String }
+		type User { id: ID! avatar: Upload }
+		type Mutation { uploadFiles(files: [Upload!]!): Boolean }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export interface AvatarInput {\n  file: File | Blob;\n",
+		"export interface User {\n  id: string;\n}",
+		"export interface MutationUploadFilesArgs {\n  files: Array<File | Blob>;\n}",
+	)
+
+	warnings := gen.Warnings()
+	if len(warnings) != 1 || warnings[0].Code != WarnUploadOutput || !strings.Contains(warnings[0].Message, "User.avatar") {
+		t.Errorf("Expected an upload-output warning for User.avatar, got: %v", warnings)
+	}
+}
+
+func TestJSONScalar(t *testing.T) {
+	source := "scalar JSON\ntype Event { payload: JSON! }"
+	expectContains(t, emit(t, newTestGenerator(t, source)), "  payload: unknown;\n")
+
+	for mode, tsType := range map[string]string{JSONAny: "any", JSONRecord: "Record<string, unknown>"} {
+		gen := NewGenerator(WithJSON(mode))
+		gen.AddSource(context.Background(), "a.graphql", source, "")
+		expectContains(t, emit(t, gen), "  payload: "+tsType+";\n")
+	}
+}
+
+func TestJSONObjectScalar(t *testing.T) {
+	source := "scalar JSONObject\ntype Event { payload: JSONObject! }"
+	expectContains(t, emit(t, newTestGenerator(t, source)), "  payload: Record<string, unknown>;\n")
+
+	gen := NewGenerator(WithJSONObject(JSONUnknown))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	expectContains(t, emit(t, gen), "  payload: unknown;\n")
+
+	gen = NewGenerator(WithScalar("JSONObject", "./json#Json"))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	expectContains(t, emit(t, gen), "import type { Json } from './json';\n", "  payload: Json;\n")
+}
+
+func TestScalarPatterns(t *testing.T) {
+	source := `
+		scalar JSON
+		scalar JSONObject
+		scalar Money
+		scalar DateTime
+		type Event { id: ID! name: String! data: JSON meta: JSONObject price: Money at: DateTime }
+	`
+	gen := NewGenerator(WithScalar("*", "unknown"), WithScalar("JSON*", "./json#Json"), WithScalar("DateTime", "Date"), WithStrictScalars(true))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	expectContains(t, emit(t, gen),
+		"  id: string;\n  name: string;\n",
+		"  data?: Nullable<Json>;\n  meta?: Nullable<Json>;\n",
+		"  price?: Nullable<unknown>;\n",
+		"  at?: Nullable<Date>;\n",
+	)
+	if warnings := gen.Warnings(); len(warnings) != 0 {
+		t.Errorf("Expected the pattern to map every custom scalar, got: %v", warnings)
+	}
+}
+
+func TestEmitSplit(t *testing.T) {
+	gen := newTestGenerator(t, `
+		enum Role { ADMIN MEMBER }
+		input UserFilter { role: Role }
+		type User { id: ID! role: Role! }
+		type Query { users(filter: UserFilter): [User!]! }
+	`)
+
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	contents := make(map[string]string)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+		contents[file.Name] = string(file.Content)
+	}
+	if strings.Join(names, ",") != "enums.ts,inputs.ts,objects.ts,operations.ts,index.ts" {
+		t.Fatalf("Unexpected split files: %v", names)
+	}
+
+	expectContains(t, contents["enums.ts"], "export enum Role {\n")
+	expectNotContains(t, contents["enums.ts"], "import type", "Nullable")
+	expectContains(t, contents["inputs.ts"],
+		"import type { Role } from './enums';\n\ntype Nullable<T> = T | null;\n\n",
+		"export interface UserFilter {\n",
+	)
+	expectContains(t, contents["objects.ts"], "import type { Role } from './enums';\n", "export interface User {\n")
+	expectNotContains(t, contents["objects.ts"], "export interface Query")
+	expectContains(t, contents["operations.ts"], "import type { User } from './objects';\n", "export interface Query {\n")
+	expectContains(t, contents["index.ts"], "export * from './enums';\nexport * from './inputs';\nexport * from './objects';\nexport * from './operations';\n")
+
+	// Empty files are still modules
+	files, _ = newTestGenerator(t, "type Query { ok: Boolean }").EmitSplit(context.Background())
+	expectContains(t, string(files[0].Content), "export {};\n")
+}
+
+func TestSplitImports(t *testing.T) {
+	gen := NewGenerator(
+		WithResolvers(true),
+		WithFieldType("User.metadata", "./metadata#UserMetadata"),
+		WithScalar("Money", "./money#Money"),
+		WithScalar("Decimal", "decimal.js#Decimal"),
+	)
+	gen.AddSource(context.Background(), "a.graphql", `
+		scalar Money
+		scalar Decimal
+		input PaymentInput { amount: Money! }
+		type User { id: ID! metadata: String }
+		type Query { me: User pay(input: PaymentInput!): Boolean }
+	`, "")
+
+	// A single file imports everything that is configured
+	expectContains(t, emit(t, gen),
+		"import type { UserMetadata } from './metadata';\n"+
+			"import type { Money } from './money';\n"+
+			"import type { Decimal } from 'decimal.js';\n"+
+			"import type { GraphQLResolveInfo } from 'graphql';\n",
+	)
+
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	contents := make(map[string]string)
+	for _, file := range files {
+		contents[file.Name] = string(file.Content)
+	}
+
+	// Split files only import what they use, relative modules are resolved from the output directory
+	expectContains(t, contents["inputs.ts"], "import type { Money } from '../money';\n")
+	expectNotContains(t, contents["inputs.ts"], "metadata", "graphql")
+	expectContains(t, contents["objects.ts"], "import type { UserMetadata } from '../metadata';\n")
+	expectNotContains(t, contents["objects.ts"], "'../money'", "decimal.js")
+	expectContains(t, contents["operations.ts"],
+		"import type { GraphQLResolveInfo } from 'graphql';\n\nimport type { PaymentInput } from './inputs';\nimport type { User } from './objects';\n",
+	)
+	expectNotContains(t, contents["operations.ts"], "'../money'")
+}
+
+func TestLintSuppressions(t *testing.T) {
+	source := "type User { id: ID! }"
+	expectContains(t, emit(t, newTestGenerator(t, source)), " */\n\n/* tslint:disable */\n/* eslint-disable */\n\n")
+
+	gen := NewGenerator(WithLintSuppressions(SuppressEslint, SuppressBiome))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	output := emit(t, gen)
+	expectContains(t, output, " */\n\n/* eslint-disable */\n/* biome-ignore-all lint: generated file */\n\n")
+	expectNotContains(t, output, "tslint")
+
+	gen = NewGenerator(WithLintSuppressions())
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	expectContains(t, emit(t, gen), " */\n\ntype Nullable<T>")
+
+	gen = NewGenerator(WithLintSuppressions("jshint"))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), `unknown lint suppression "jshint"`) {
+		t.Errorf("Expected unknown lint suppression error, got: %v", err)
+	}
+}
+
+func TestSplitESM(t *testing.T) {
+	gen := NewGenerator(WithExtension(".mts"), WithESM(true), WithFieldType("User.metadata", "./metadata#UserMetadata"))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum Role { ADMIN }
+		type User { role: Role! metadata: String }
+	`, "")
+
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	contents := make(map[string]string)
+	for _, file := range files {
+		contents[file.Name] = string(file.Content)
+	}
+	expectContains(t, contents["objects.mts"],
+		"import type { UserMetadata } from '../metadata.mjs';\n",
+		"import type { Role } from './enums.mjs';\n",
+	)
+	expectContains(t, contents["index.mts"], "export * from './enums.mjs';\n")
+
+	gen = NewGenerator(WithExtension(".tsx"))
+	if _, err := gen.EmitSplit(context.Background()); err == nil {
+		t.Errorf("Expected unsupported extension error")
+	}
+}
+
+func TestHeaderMetadata(t *testing.T) {
+	source := "type User { id: ID! }"
+	expectNotContains(t, emit(t, newTestGenerator(t, source)), "Schema hash", "Generator:", "Generated at")
+
+	generatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	newGen := func(sources ...string) *Generator {
+		gen := NewGenerator(WithHeaderMetadata(true, true), WithHeaderTimestamp(generatedAt))
+		for i, source := range sources {
+			gen.AddSource(context.Background(), fmt.Sprintf("schema%d.graphql", i), source, "")
+		}
+		return gen
+	}
+
+	output := emit(t, newGen(source, "type Query { me: User }"))
+	expectContains(t, output,
+		" * -------------------------------------------------------\n * Schema hash: sha256:",
+		" * Generator: graphql-ts-generator "+Version+"\n",
+		" * Generated at: 2024-05-01T12:00:00Z\n */\n",
+	)
+
+	// The schema hash does not depend on the order of the sources
+	if emit(t, newGen("type Query { me: User }", source)) != output {
+		t.Errorf("Expected the same header for sources added in another order")
+	}
+}
+
+func TestComplexity(t *testing.T) {
+	gen := newTestGenerator(t, `
+		type User { id: ID! friends: [User!]! }
+		type Comment { text: String! }
+		type Post { title: String! comments: [Comment!]! author: Author! }
+		type Author { name: String! }
+		union SearchResult = Post | Comment
+		input PostFilter { title: String authorName: String }
+		type Query { me: User search(filter: PostFilter): [SearchResult!]! count: Int }
+	`)
+
+	report := gen.Complexity()
+	expected := []RootFieldComplexity{
+		{Root: "Query", Field: "count", Depth: 0, ReachableTypes: 0},
+		{Root: "Query", Field: "me", Depth: 1, Cyclic: true, ReachableTypes: 1},
+		{Root: "Query", Field: "search", Depth: 2, ReachableTypes: 5},
+	}
+	if !reflect.DeepEqual(report.RootFields, expected) {
+		t.Errorf("Unexpected root fields:\n%+v\nwant:\n%+v", report.RootFields, expected)
+	}
+	if report.Types[0] != (TypeComplexity{"Post", 2}) {
+		t.Errorf("Expected Post to have the highest fan-out, got %+v", report.Types)
+	}
+	if report.ReachableTypes != 6 || report.TotalTypes != 6 {
+		t.Errorf("Expected 6 of 6 reachable types, got %d of %d", report.ReachableTypes, report.TotalTypes)
+	}
+}
+
+func TestGraph(t *testing.T) {
+	source := `
+		interface Node { id: ID! }
+		type User implements Node { id: ID! role: Role! posts(filter: PostFilter): [Post!]! }
+		type Post implements Node { id: ID! author: User! }
+		enum Role { ADMIN MEMBER }
+		input PostFilter { title: String }
+		type Query { me: User node(id: ID!): Node }
+	`
+	gen := NewGenerator(WithTarget(TargetDOT))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	expectContains(t, emit(t, gen),
+		`  "Query" [shape=ellipse, style=bold];`,
+		`  "Node" [style=dashed];`,
+		`  "Role" [shape=hexagon];`,
+		`  "Query" -> "User" [label="me"];`,
+		`  "Query" -> "Node" [label="node"];`,
+		`  "User" -> "Node";`+"\n"+`  "User" -> "Post";`+"\n"+`  "User" -> "PostFilter";`+"\n"+`  "User" -> "Role";`,
+	)
+
+	gen = NewGenerator(WithTarget(TargetMermaid))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	output := emit(t, gen)
+	expectContains(t, output,
+		"graph LR\n  Query([Query])\n",
+		"  Node[/Node/]\n",
+		"  Query -->|me| User\n",
+		"  Post --> Node\n  Post --> User\n",
+	)
+	expectNotContains(t, output, "ID")
+}
+
+func TestSelfReferentialTypes(t *testing.T) {
+	source := `
+		scalar DateTime
+		interface Node { id: ID! parent: Node }
+		type Comment implements Node { id: ID! parent: Node replies: [Comment!]! author: User! postedAt: DateTime! }
+		type User implements Node { id: ID! parent: Node comments: [Comment!]! manager: User }
+		union Thread = Comment | User
+		input CommentFilter { and: [CommentFilter!] or: [CommentFilter!] text: String }
+		type Query { comments(filter: CommentFilter): [Comment!]! thread: Thread }
+	`
+	gen := NewGenerator(
+		WithPrune(true),
+		WithDates(true),
+		WithTypeMap(true),
+		WithResolvers(true),
+		WithInheritInterfaceFields(true),
+	)
+	if err := gen.AddSource(context.Background(), "a.graphql", source, ""); err != nil {
+		t.Fatalf("Failed to add source: %v", err)
+	}
+
+	// Every walker over the type graph has to stop at types it already visited
+	done := make(chan string)
+	go func() {
+		done <- emit(t, gen)
+	}()
+	var output string
+	select {
+	case output = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Emission of recursive types did not terminate")
+	}
+
+	expectContains(t, output,
+		"export interface Comment {\n  id: string;\n  parent?: Nullable<Node>;\n  replies: Array<Comment>;\n  author: User;\n  postedAt: Date;\n}",
+		"export interface User {\n  id: string;\n  parent?: Nullable<Node>;\n  comments: Array<Comment>;\n  manager?: Nullable<User>;\n}",
+		"export interface CommentFilter {\n  and?: Nullable<Array<CommentFilter>>;\n  or?: Nullable<Array<CommentFilter>>;\n",
+		"  Comment: { replies: 'Comment', author: 'User', postedAt: 'DateTime' },\n",
+		"  User: { comments: 'Comment', manager: 'User' },\n",
+	)
+
+	report := gen.Complexity()
+	for _, field := range report.RootFields {
+		if !field.Cyclic {
+			t.Errorf("Expected %s.%s to reach a cycle", field.Root, field.Field)
+		}
+	}
+	// Mutually recursive Go structs are only valid through pointers
+	gen = NewGenerator(WithTarget(TargetGo))
+	gen.AddSource(context.Background(), "a.graphql", "type Post { author: Author! title: String! } type Author { latest: Post! }", "")
+	expectContains(t, emit(t, gen), "\tAuthor *Author `json:\"author\"`\n", "\tLatest *Post `json:\"latest\"`\n")
+
+	gen = NewGenerator(WithInheritInterfaceFields(true))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	for _, target := range []string{TargetFlow, TargetGo, TargetDOT, TargetSDL} {
+		gen.opts.Target = target
+		if err := gen.Emit(context.Background(), io.Discard); err != nil {
+			t.Errorf("Failed to emit %s: %v", target, err)
+		}
+	}
+}
+
+func TestReservedFieldNames(t *testing.T) {
+	source := `
+		type Item { id: ID! delete: Boolean! new: String constructor: String @tsName(name: "ctor") }
+		type Query { item(default: ID!): Item }
+	`
+	gen := NewGenerator(WithResolvers(true), WithOperationNames(true))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	expectContains(t, emit(t, gen),
+		"export interface Item {\n  id: string;\n  'delete': boolean;\n  'new'?: Nullable<string>;\n  ctor?: Nullable<string>;\n}",
+		"export interface QueryItemArgs {\n  'default': string;\n}",
+		"  item: 'item',\n",
+	)
+
+	gen = NewGenerator(WithReservedFields(ReservedRename))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	expectContains(t, emit(t, gen), "  /** GraphQL field: delete */\n  delete_: boolean;\n  /** GraphQL field: new */\n  new_?: Nullable<string>;\n")
+
+	gen = NewGenerator(WithReservedFields(ReservedKeep))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	expectContains(t, emit(t, gen), "  delete: boolean;\n  new?: Nullable<string>;\n")
+
+	gen = NewGenerator(WithReservedFields("escape"))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), `unknown reserved fields mode "escape"`) {
+		t.Errorf("Expected unknown mode error, got: %v", err)
+	}
+}
+
+func TestNonIdentifierNames(t *testing.T) {
+	gen := newTestGenerator(t, `
+		type Row { id: ID! firstName: String @tsName(name: "first-name") total: Int @tsName(name: "2021_total") note: String @tsName(name: "it's") }
+	`)
+	expectContains(t, emit(t, gen),
+		"  'first-name'?: Nullable<string>;\n  '2021_total'?: Nullable<number>;\n  'it\\'s'?: Nullable<string>;\n",
+	)
+
+	gen = NewGenerator(WithScalarsRecord(true))
+	gen.AddSource(context.Background(), "a.graphql", "type Scalars { id: ID! } type Row { id: ID! }", "")
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), "Scalars collides with the generated Scalars interface; rename it, e.g. with -rename Scalars=ScalarsType") {
+		t.Errorf("Expected helper collision error, got: %v", err)
+	}
+
+	gen = NewGenerator(WithRename("Row", "Table-Row"))
+	gen.AddSource(context.Background(), "a.graphql", "type Row { id: ID! }", "")
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), `Row is emitted as "Table-Row", which is not a valid TypeScript identifier`) {
+		t.Errorf("Expected invalid identifier error, got: %v", err)
+	}
+
+	// Unions and custom scalars are checked too
+	gen = NewGenerator(WithTypeNameUnion(true), WithRename("Money", "Item"))
+	gen.AddSource(context.Background(), "a.graphql", "type Item { id: ID! } scalar Money union TypeName = Item type Query { item: Item price: Money name: TypeName }", "")
+	err := gen.Emit(context.Background(), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "TypeName collides with the generated TypeName union") ||
+		!strings.Contains(err.Error(), "Item and Money are both emitted as Item") {
+		t.Errorf("Expected union and scalar collision errors, got: %v", err)
+	}
+}
+
+func TestDeclarationOrder(t *testing.T) {
+	sources := []string{
+		"type User { id: ID! } enum Status { ACTIVE } type Query { users: [User!]! me: User }",
+		"type Account { id: ID! } type Query { account: Account }",
+	}
+	gen := NewGenerator(WithDeclarationOrder(true))
+	for i, source := range sources {
+		gen.AddSource(context.Background(), fmt.Sprintf("schema%d.graphql", i+1), source, "")
+	}
+	output := emit(t, gen)
+	expectContains(t, output, "export interface Query {\n  users: Array<User>;\n  me?: Nullable<User>;\n  account?: Nullable<Account>;\n}")
+	if strings.Index(output, "interface User") > strings.Index(output, "interface Account") {
+		t.Errorf("Expected User to be emitted before Account")
+	}
+
+	expectContains(t, emit(t, newTestGenerator(t, sources...)), "export interface Query {\n  account?: Nullable<Account>;\n  me?: Nullable<User>;\n  users: Array<User>;\n}")
+}
+
+func TestGeneratedNameCollisions(t *testing.T) {
+	gen := NewGenerator(WithRename("Account", "User"))
+	gen.AddSource(context.Background(), "a.graphql", "type Account { id: ID! } type User { id: ID! } type UserType { id: ID! }", "")
+	err := gen.Emit(context.Background(), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "Account and User are both emitted as User; rename one, e.g. with -rename User=UserType2") {
+		t.Errorf("Expected duplicate name error, got: %v", err)
+	}
+
+	gen = NewGenerator(WithEnumValues(true), WithFederation(true))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum Role { ADMIN }
+		type RoleValues { id: ID! }
+		type Product @key(fields: "id") { id: ID! }
+		type ProductKeyFields { id: ID! }
+		type Query { product: Product }
+	`, "")
+	err = gen.Emit(context.Background(), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "ProductKeyFields collides with the generated Product key fields type") ||
+		!strings.Contains(err.Error(), "RoleValues collides with the generated Role values constant") {
+		t.Errorf("Expected derived name collision errors, got: %v", err)
+	}
+}
+
+func TestExtractTaggedSDL(t *testing.T) {
+	source := "import { gql } from 'graphql-tag';\n" +
+		"// gql`type Commented { id: ID }`\n" +
+		"const label = \"gql`type Quoted { id: ID }`\";\n" +
+		"export const typeDefs = gql`\n" +
+		"  type User {\n" +
+		"    id: ID!\n" +
+		"    name: String ${nameDirective}\n" +
+		"  }\n" +
+		"`;\n" +
+		"export const query = gql`query Users { users { id } }`;\n" +
+		"export const extra = gql`\n" +
+		"  \"\"\"Root query\"\"\"\n" +
+		"  type Query { users: [User!]! }\n" +
+		"`;\n" +
+		"export const other = css`type Styled { id: ID }`;\n"
+
+	sdl := ExtractTaggedSDL(source, []string{"gql"})
+	if strings.Count(sdl, "\n") != strings.Count(source, "\n") {
+		t.Errorf("Expected line numbers to be kept, got:\n%s", sdl)
+	}
+	expectContains(t, sdl, "type User {", "name: String ", "type Query { users: [User!]! }")
+	expectNotContains(t, sdl, "Commented", "Quoted", "nameDirective", "query Users", "Styled", "import")
+
+	gen := NewGenerator()
+	if err := gen.AddSource(context.Background(), "schema.ts", sdl, ""); err != nil {
+		t.Fatalf("Failed to add extracted SDL: %v", err)
+	}
+	expectContains(t, emit(t, gen), "export interface User {")
+
+	invalid := "const a = 1;\nconst typeDefs = gql`\n  type User { id: }\n`;\n"
+	err := NewGenerator().AddSource(context.Background(), "schema.ts", ExtractTaggedSDL(invalid, []string{"gql"}), "")
+	if err == nil || !strings.Contains(err.Error(), "schema.ts:3:") {
+		t.Errorf("Expected error position in the source file, got: %v", err)
+	}
+}
+
+// Schema with many interfaces and cross references, the shape of a large supergraph
+func largeSchema(types, interfaces int) string {
+	var b strings.Builder
+	for i := 0; i < interfaces; i++ {
+		fmt.Fprintf(&b, "interface I%d { id: ID! }\n", i)
+	}
+	for i := 0; i < types; i++ {
+		fmt.Fprintf(&b, "type T%d implements I%d & I%d {\n  id: ID!\n  name: String\n", i, i%interfaces, (i*7+1)%interfaces)
+		fmt.Fprintf(&b, "  next: T%d\n  items(first: Int): [T%d!]!\n  node: I%d\n}\n", (i+1)%types, (i*31)%types, (i*13)%interfaces)
+	}
+	b.WriteString("type Query {\n")
+	for i := 0; i < types; i += 10 {
+		fmt.Fprintf(&b, "  t%d: T%d\n", i, i)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func BenchmarkLargeSchema(b *testing.B) {
+	schema := largeSchema(5000, 500)
+	for i := 0; i < b.N; i++ {
+		gen := NewGenerator(WithPrune(true))
+		if err := gen.AddSource(context.Background(), "schema.graphql", schema, ""); err != nil {
+			b.Fatalf("Failed to add source: %v", err)
+		}
+		if err := gen.Emit(context.Background(), io.Discard); err != nil {
+			b.Fatalf("Failed to emit: %v", err)
+		}
+		gen.Lint()
+		gen.Complexity()
+	}
+}
+
+func TestConcurrentGenerators(t *testing.T) {
+	schema := largeSchema(200, 20)
+	expected := emit(t, newTestGenerator(t, schema))
+
+	shared := newTestGenerator(t, schema)
+	var wg sync.WaitGroup
+	outputs := make([]string, 8)
+	for i := range outputs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			gen := shared
+			if i%2 == 0 {
+				// Separate generators share no state
+				gen = NewGenerator()
+				if err := gen.AddSource(context.Background(), "schema1.graphql", schema, ""); err != nil {
+					t.Errorf("Failed to add source: %v", err)
+					return
+				}
+			}
+			var buf bytes.Buffer
+			if err := gen.Emit(context.Background(), &buf); err != nil {
+				t.Errorf("Failed to emit: %v", err)
+			}
+			if _, err := gen.EmitSplit(context.Background()); err != nil {
+				t.Errorf("Failed to emit split files: %v", err)
+			}
+			gen.Lint()
+			gen.Complexity()
+			outputs[i] = buf.String()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, output := range outputs {
+		if output != expected {
+			t.Errorf("Output %d differs from a sequential run", i)
+		}
+	}
+}
+
+func TestEmitSections(t *testing.T) {
+	gen := newTestGenerator(t, "type Query { user: User }\ntype User { id: ID! }\ninterface Node { id: ID! }")
+	failure := errors.New("second section failed")
+	section := func(content string, err error) emitSection {
+		return func(g *Generator, file *bufio.Writer) error {
+			file.WriteString(content)
+			g.convertGraphqlTypeToTs(content)
+			return err
+		}
+	}
+
+	outputs, err := gen.emitSections([]emitSection{section("User", nil), section("Query", nil), section("Node", nil)})
+	if err != nil {
+		t.Fatalf("Failed to emit sections: %v", err)
+	}
+	for i, expected := range []string{"User", "Query", "Node"} {
+		if string(outputs[i].content) != expected {
+			t.Errorf("Expected section %d to be %q, got %q", i, expected, outputs[i].content)
+		}
+		if !outputs[i].references.types[expected] || len(outputs[i].references.types) != 1 {
+			t.Errorf("Expected section %d to reference only %s, got %v", i, expected, outputs[i].references.types)
+		}
+	}
+
+	if _, err := gen.emitSections([]emitSection{section("a", nil), section("b", failure), section("c", errors.New("third"))}); err != failure {
+		t.Errorf("Expected the error of the first failed section, got %v", err)
+	}
+}
+
+func TestParseCache(t *testing.T) {
+	cache := NewParseCache()
+	schema := "type User { id: ID! }\ntype Query { me: User }"
+	add := func(opts ...Option) string {
+		var log bytes.Buffer
+		gen := NewGenerator(append(opts, WithParseCache(cache), WithDebugLog(&log))...)
+		if err := gen.AddSource(context.Background(), "schema.graphql", schema, "hash"); err != nil {
+			t.Fatalf("Failed to add source: %v", err)
+		}
+		return log.String()
+	}
+
+	if log := add(); !strings.Contains(log, "Parsing file: schema.graphql") {
+		t.Errorf("Expected the first generator to parse the file, got:\n%s", log)
+	}
+	if log := add(WithTarget(TargetDocs)); !strings.Contains(log, "Reusing parsed file: schema.graphql") {
+		t.Errorf("Expected the second generator to reuse the parsed file, got:\n%s", log)
+	}
+	// The internal directive is declared when parsing, so the file is parsed again for another one
+	if log := add(WithInternalDirective("hidden")); !strings.Contains(log, "Parsing file: schema.graphql") {
+		t.Errorf("Expected a generator with another internal directive to parse the file, got:\n%s", log)
+	}
+}
+
+func TestOperationDocuments(t *testing.T) {
+	gen := newTestGenerator(t, `
+		interface Node { id: ID! }
+		type User implements Node { id: ID! name: String team: Team posts(first: Int!, after: String): [Post!]! }
+		type Team { id: ID! owner: User }
+		type Post { title: String! }
+		union SearchResult = User | Team
+		enum Role { ADMIN USER }
+		type Query { user(id: ID!): User node(id: ID!): Node search(text: String, first: Int = 10): [SearchResult!]! version: String }
+		type Mutation { setRole(id: ID!, role: Role!): User }
+	`)
+	files, err := gen.OperationDocuments(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Failed to generate operations: %v", err)
+	}
+	documents := make(map[string]string)
+	for _, file := range files {
+		documents[file.Name] = string(file.Content)
+	}
+
+	expected := map[string]string{
+		"user.query.graphql": `query User($id: ID!, $first: Int!) {
+  user(id: $id) {
+    id
+    name
+    team {
+      id
+    }
+    posts(first: $first) {
+      title
+    }
+  }
+}
+`,
+		"node.query.graphql": `query Node($id: ID!) {
+  node(id: $id) {
+    __typename
+    id
+  }
+}
+`,
+		"search.query.graphql": `query Search($text: String, $first: Int, $postsFirst: Int!) {
+  search(text: $text, first: $first) {
+    __typename
+    ... on User {
+      id
+      name
+      team {
+        id
+      }
+      posts(first: $postsFirst) {
+        title
+      }
+    }
+    ... on Team {
+      id
+      owner {
+        id
+        name
+      }
+    }
+  }
+}
+`,
+		"version.query.graphql": "query Version {\n  version\n}\n",
+		"setRole.mutation.graphql": `mutation SetRole($id: ID!, $role: Role!, $first: Int!) {
+  setRole(id: $id, role: $role) {
+    id
+    name
+    team {
+      id
+    }
+    posts(first: $first) {
+      title
+    }
+  }
+}
+`,
+	}
+	if len(documents) != len(expected) {
+		t.Errorf("Expected %d documents, got %d", len(expected), len(documents))
+	}
+	for name, document := range expected {
+		if documents[name] != document {
+			t.Errorf("Unexpected %s:\n%s\nExpected:\n%s", name, documents[name], document)
+		}
+	}
+
+	if _, err := gen.OperationDocuments(context.Background(), 0); err == nil {
+		t.Error("Expected an error for a depth of 0")
+	}
+}
+
+func TestValidateOperations(t *testing.T) {
+	gen := newTestGenerator(t, `
+		interface Node { id: ID! }
+		type User implements Node { id: ID! name: String posts(first: Int!): [Post!]! }
+		type Post { title: String! }
+		union SearchResult = User | Post
+		type Query { user(id: ID!): User search(text: String): [SearchResult!]! }
+		extend type Query { node(id: ID!): Node }
+	`)
+
+	// The generated skeletons are valid operations
+	files, err := gen.OperationDocuments(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Failed to generate operations: %v", err)
+	}
+	var sources []OperationSource
+	for _, file := range files {
+		sources = append(sources, OperationSource{Name: file.Name, Content: string(file.Content)})
+	}
+	sources = append(sources,
+		OperationSource{Name: "fragments.graphql", Content: "fragment UserName on User { name }"},
+		OperationSource{Name: "named.graphql", Content: "query Named { user(id: 1) { ...UserName } }"},
+	)
+	problems, err := gen.ValidateOperations(context.Background(), sources)
+	if err != nil {
+		t.Fatalf("Failed to validate operations: %v", err)
+	}
+	for _, problem := range problems {
+		t.Errorf("Unexpected problem: %v", problem)
+	}
+
+	problems, err = gen.ValidateOperations(context.Background(), []OperationSource{
+		{Name: "bad.graphql", Content: "query Bad($id: Int) {\n  user(id: $id) { email }\n  search(text: $text) { __typename }\n}"},
+		{Name: "syntax.graphql", Content: "query { user(id: 1 { id } }"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to validate operations: %v", err)
+	}
+	expected := []string{
+		`syntax.graphql:1:20: Expected Name, found {`,
+		`bad.graphql:2:12: Variable "$id" of type "Int" used in position expecting type "ID!".`,
+		`bad.graphql:2:19: Cannot query field "email" on type "User".`,
+		`bad.graphql:3:16: Variable "$text" is not defined by operation "Bad".`,
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("Expected %d problems, got: %v", len(expected), problems)
+	}
+	for i, problem := range problems {
+		var positionErr *PositionError
+		if !errors.As(problem, &positionErr) || problem.Error() != expected[i] {
+			t.Errorf("Expected %s, got: %v", expected[i], problem)
+		}
+	}
+}
+
+func TestFieldUsages(t *testing.T) {
+	gen := newTestGenerator(t, `
+		interface Node { id: ID! }
+		type User implements Node { id: ID! name: String email: String }
+		type Team implements Node { id: ID! name: String }
+		type Query { user(id: ID!): User node(id: ID!): Node teams: [Team!]! }
+	`)
+	usages, err := gen.FieldUsages(context.Background(), []OperationSource{
+		{Name: "user.graphql", Content: "query User { user(id: 1) { ...UserName } }\nfragment UserName on User { name }"},
+		{Name: "node.graphql", Content: "{ node(id: 1) { id ... on User { name } } }"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to report field usage: %v", err)
+	}
+	var lines []string
+	for _, usage := range usages {
+		lines = append(lines, usage.Type+"."+usage.Field+": "+strings.Join(usage.Operations, ","))
+	}
+	expected := []string{
+		"Query.node: node.graphql",
+		"Query.teams: ",
+		"Query.user: User",
+		"Node.id: node.graphql",
+		"Team.id: node.graphql",
+		"Team.name: ",
+		"User.id: node.graphql",
+		"User.name: User,node.graphql",
+		"User.email: ",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected usage:\n%s", strings.Join(lines, "\n"))
+	}
+
+	if _, err := gen.FieldUsages(context.Background(), []OperationSource{{Name: "bad.graphql", Content: "{ user(id: 1) { age } }"}}); err == nil {
+		t.Error("Expected an error for an invalid operation")
+	}
+}
+
+func TestDeprecatedUsages(t *testing.T) {
+	gen := newTestGenerator(t, `
+		type User { id: ID! login: String @deprecated(reason: "Use email") email: String }
+		type Query { user(id: ID!, legacy: Boolean @deprecated): User users: [User!]! @deprecated(reason: "Paginate") }
+	`)
+	usages, err := gen.DeprecatedUsages(context.Background(), []OperationSource{
+		{Name: "user.graphql", Content: "query User {\n  user(id: 1, legacy: true) { ...Login }\n}"},
+		{Name: "fragments.graphql", Content: "fragment Login on User { id login }"},
+		{Name: "users.graphql", Content: "{ users { ... on User { email login } } }"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to report deprecated usage: %v", err)
+	}
+	var lines []string
+	for _, usage := range usages {
+		lines = append(lines, fmt.Sprintf("%s:%d:%d %s %s.%s(%s) %s", usage.File, usage.Line, usage.Column, usage.Definition, usage.Type, usage.Field, usage.Argument, usage.Reason))
+	}
+	expected := []string{
+		"fragments.graphql:1:29 fragment Login User.login() Use email",
+		"user.graphql:2:15 query User Query.user(legacy) No longer supported",
+		"users.graphql:1:3 query Query.users() Paginate",
+		"users.graphql:1:31 query User.login() Use email",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected deprecated usage:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestInputDefaults(t *testing.T) {
+	gen := newTestGenerator(t, `
+		scalar DateTime
+		scalar Decimal
+		enum Role { MEMBER ADMIN }
+		input AddressInput { city: String! country: String = "FR" }
+		input CreateUserInput {
+			name: String!
+			age: Int!
+			role: Role! = ADMIN
+			roles: [Role!]! = MEMBER
+			active: Boolean
+			tags: [String!]!
+			address: AddressInput!
+			home: AddressInput = { city: "Lyon" }
+			joined: DateTime!
+			balance: Decimal!
+		}
+		input PageInput { first: Int = 10 after: String }
+		type Query { users(input: CreateUserInput, page: PageInput): [String!]! }
+	`)
+	gen.opts.InputDefaults = true
+	output := emit(t, gen)
+	expectContains(t, output,
+		`export function defaultCreateUserInput(): CreateUserInput {
+  return {
+    name: '',
+    age: 0,
+    role: Role.ADMIN,
+    roles: [Role.MEMBER],
+    tags: [],
+    address: defaultAddressInput(),
+    home: { ...defaultAddressInput(), city: 'Lyon' },
+    joined: '',
+    balance: null!,
+  };
+}`,
+		"export function defaultAddressInput(): AddressInput {\n  return {\n    city: '',\n    country: 'FR',\n  };\n}",
+		"export function defaultPageInput(): PageInput {\n  return {\n    first: 10,\n  };\n}",
+	)
+
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	// Enum members are values, so inputs.ts needs a runtime import of the enum
+	expectContains(t, string(files[1].Content), "import { Role } from './enums';\n")
+	expectNotContains(t, string(files[1].Content), "import type { Role }")
+}
+
+func TestInputClasses(t *testing.T) {
+	gen := newTestGenerator(t, `
+		enum Role { MEMBER ADMIN }
+		input AddressInput { city: String! country: String = "FR" }
+		input CreateUserInput { name: String! role: Role! = ADMIN nickname: String address: AddressInput = { city: "Lyon" } }
+		type Query { users(input: CreateUserInput): [String!]! }
+	`)
+	gen.opts.InputClasses = true
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export class AddressInput {\n  city!: string;\n  country?: Nullable<string> = 'FR';\n\n  constructor(init?: Partial<AddressInput>) {\n    Object.assign(this, init);\n  }\n}\n",
+		"export class CreateUserInput {\n  name!: string;\n  role: Role = Role.ADMIN;\n  nickname?: Nullable<string>;\n  address?: Nullable<AddressInput> = new AddressInput({ city: 'Lyon' });\n",
+	)
+	expectNotContains(t, output, "export interface CreateUserInput")
+
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	for _, file := range files {
+		if file.Name == "inputs.ts" {
+			expectContains(t, string(file.Content), "import { Role } from './enums';\n")
+		}
+	}
+
+	// A constructor field, even quoted, is a syntax error in a class
+	source := "input FilterInput { constructor: String }\ntype Query { users(filter: FilterInput): [String!]! }"
+	gen = NewGenerator(WithInputClasses(true))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), "FilterInput.constructor cannot be a field of the FilterInput class") {
+		t.Errorf("Expected constructor field error, got: %v", err)
+	}
+	gen = NewGenerator(WithInputClasses(true), WithReservedFields(ReservedRename))
+	gen.AddSource(context.Background(), "a.graphql", source, "")
+	expectContains(t, emit(t, gen), "export class FilterInput {\n", "  constructor_?: Nullable<string>;\n")
+}
+
+func TestPartialInputs(t *testing.T) {
+	gen := newTestGenerator(t, `
+		input AddressInput { city: String! }
+		input CreateUserInput { name: String! address: AddressInput! }
+		type Query { users(input: CreateUserInput): [String!]! }
+	`)
+	gen.opts.PartialInputs = true
+	expectContains(t, emit(t, gen),
+		"export type DeepPartial<T> = T extends ReadonlyArray<infer U>\n",
+		"export type PartialAddressInput = DeepPartial<AddressInput>;\nexport type PartialCreateUserInput = DeepPartial<CreateUserInput>;\n",
+	)
+
+	gen = newTestGenerator(t, "input PartialUserInput { id: ID }\ninput UserInput { id: ID }\ntype Query { ok(input: UserInput, partial: PartialUserInput): Boolean }")
+	gen.opts.PartialInputs = true
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), "UserInput partial type") {
+		t.Errorf("Expected a collision with the partial type, got %v", err)
+	}
+}
+
+func TestInputCoercion(t *testing.T) {
+	gen := newTestGenerator(t, `
+		enum Role { ADMIN USER }
+		input AddressInput { city: String! }
+		input CreateUserInput { name: String! age: Int role: Role! = USER tags: [String!] address: AddressInput! score: Int = 3 }
+		type Query { users(input: CreateUserInput): [String!]! }
+	`)
+	gen.opts.InputCoercion = true
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export class InputCoercionError extends Error {\n",
+		"function coerceInt(value: unknown, path: string): number {\n",
+		"export function coerceCreateUserInput(raw: unknown, path = 'CreateUserInput'): CreateUserInput {\n  const value = coerceObjectValue(raw, path, 'CreateUserInput');\n",
+		"    name: coerceString(value['name'], `${path}.name`),\n",
+		"    role: value['role'] === undefined ? Role.USER : coerceEnumValue(value['role'], `${path}.role`, 'Role', { ADMIN: Role.ADMIN, USER: Role.USER }),\n",
+		"    address: coerceAddressInput(value['address'], `${path}.address`),\n",
+		// Defaults apply to nullable fields too, an explicit null is kept
+		"    score: value['score'] === undefined ? 3 : value['score'] === null ? null : coerceInt(value['score'], `${path}.score`),\n",
+		"  if (value['age'] !== undefined) {\n    result.age = value['age'] === null ? null : coerceInt(value['age'], `${path}.age`);\n  }\n",
+		"coerceListOf(value['tags'], `${path}.tags`, (item, itemPath) => coerceString(item, itemPath))",
+	)
+	// Helpers are only written when used, for noUnusedLocals
+	expectNotContains(t, output, "function coerceFloat(", "function coerceDate(")
+}
+
+func TestZod(t *testing.T) {
+	gen := newTestGenerator(t, `
+		directive @constraint(minLength: Int, maxLength: Int, pattern: String, format: String, min: Float, max: Float, minItems: Int) on INPUT_FIELD_DEFINITION
+		enum Role { ADMIN USER }
+		input AddressInput { city: String! @constraint(minLength: 1, maxLength: 80) }
+		input CreateUserInput {
+			email: String! @constraint(format: "email")
+			code: String @constraint(pattern: "^[A-Z]{3}$")
+			age: Int @constraint(min: 0, max: 150)
+			tags: [String!]! @constraint(minItems: 1, maxLength: 20)
+			role: Role!
+			address: AddressInput
+		}
+		type Query { users(input: CreateUserInput): [String!]! }
+	`)
+	gen.opts.Zod = true
+	expectContains(t, emit(t, gen),
+		"import { z } from 'zod';\n",
+		"export const AddressInputSchema: z.ZodType<AddressInput> = z.object({\n  city: z.string().min(1).max(80),\n});\n",
+		"  email: z.string().email(),\n",
+		"  code: z.string().regex(new RegExp('^[A-Z]{3}$')).nullish(),\n",
+		"  age: z.number().int().gte(0).lte(150).nullish(),\n",
+		"  tags: z.array(z.string().max(20)).min(1),\n",
+		"  role: z.nativeEnum(Role),\n",
+		"  address: z.lazy(() => AddressInputSchema).nullish(),\n",
+	)
+
+	gen = newTestGenerator(t, "input UserSchema { id: ID }\ninput User { id: ID }\ntype Query { ok(input: User, schema: UserSchema): Boolean }")
+	gen.opts.Zod = true
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), "Zod schema") {
+		t.Errorf("Expected a collision with the Zod schema, got %v", err)
+	}
+}
+
+func TestArraySyntax(t *testing.T) {
+	gen := newTestGenerator(t, `
+		scalar Id
+		input Filter { ids: [[Id!]!] }
+		type Query { names: [String!]! ids(filter: Filter): [Id] }
+	`)
+	gen.opts.ArraySyntax = ArrayType
+	gen.opts.Scalars = map[string]string{"Id": "string | number"}
+	gen.opts.PartialInputs = true
+	expectContains(t, emit(t, gen),
+		"  names: string[];\n",
+		"  ids?: Nullable<(string | number)[]>;\n",
+		"  ids?: Nullable<(string | number)[][]>;\n",
+		"T extends readonly (infer U)[]\n  ? DeepPartial<U>[]\n",
+	)
+
+	for tsType, expected := range map[string]bool{
+		"Record<string, unknown>": false,
+		"{ a: string | null }":    false,
+		"string | number":         true,
+		"A & B":                   true,
+		"() => void":              true,
+	} {
+		if hasTopLevelOperator(tsType) != expected {
+			t.Errorf("Expected hasTopLevelOperator(%q) to be %v", tsType, expected)
+		}
+	}
+
+	gen.opts.ArraySyntax = "list"
+	if err := gen.Emit(context.Background(), io.Discard); err == nil {
+		t.Error("Expected an error for an unknown array syntax")
+	}
+}
+
+func TestInlineNull(t *testing.T) {
+	gen := newTestGenerator(t, `
+		scalar Callback
+		input UserFilter { name: String id: ID! }
+		type User { id: ID! name: String onSave: Callback }
+		type Query { users(filter: UserFilter, first: Int): [User!]! }
+	`)
+	gen.opts.InlineNull = true
+	gen.opts.Resolvers = true
+	gen.opts.Scalars = map[string]string{"Callback": "() => void"}
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export interface User {\n  id: string;\n  name?: string | null;\n  onSave?: (() => void) | null;\n}",
+		"export interface UserFilter {\n  name?: string | null;\n  id: string;\n}",
+		"  first?: number | null;\n",
+	)
+	expectNotContains(t, output, "Nullable")
+
+	gen.opts.InputMaybe = true
+	output = emit(t, gen)
+	expectContains(t, output, "  name?: string | null | undefined;\n", "  first?: number | null | undefined;\n")
+	expectNotContains(t, output, "InputMaybe")
+}
+
+func TestStrictTS(t *testing.T) {
+	gen := newTestGenerator(t, `
+		input UserFilter { name: String }
+		type User { name: String }
+		type Query { users(filter: UserFilter): [User!]! }
+	`)
+	gen.opts.StrictTS = true
+	gen.opts.PartialInputs = true
+	expectContains(t, emit(t, gen),
+		"type Nullable<T> = T | null | undefined;\n",
+		"  name?: Nullable<string>;\n",
+		"? { [K in keyof T]?: DeepPartial<T[K]> | undefined }\n",
+	)
+
+	gen.opts.InlineNull = true
+	expectContains(t, emit(t, gen), "  name?: string | null | undefined;\n")
+}
+
+func TestCodegenPreset(t *testing.T) {
+	gen := newTestGenerator(t, `
+		enum Role { ADMIN USER_ROLE }
+		interface Node { id: ID! }
+		type User implements Node { id: ID! name: String posts(first: Int = 10): [Post] }
+		type Post { title: String! }
+		union SearchResult = User | Post
+		input UserFilter { role: Role = ADMIN }
+		type Query { search(filter: UserFilter): [SearchResult!]! }
+	`)
+	gen.opts.Preset = PresetCodegen
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export type Maybe<T> = T | null;\nexport type InputMaybe<T> = Maybe<T>;\nexport type Exact<",
+		"  ID: { input: string; output: string; }\n",
+		"export enum Role {\n  Admin = 'ADMIN',\n  UserRole = 'USER_ROLE',\n}",
+		"export type Node = {\n  id: string;\n};",
+		"export type User = {\n  __typename?: 'User';\n  id: string;\n  name?: Maybe<string>;\n  posts?: Maybe<Array<Maybe<Post>>>;\n};",
+		"export type UserPostsArgs = {\n  first?: InputMaybe<number>;\n};",
+		"export type SearchResult = User | Post;",
+		"export type UserFilter = {\n  role?: InputMaybe<Role>;\n};",
+		"export type Query = {\n  __typename?: 'Query';\n  search: Array<SearchResult>;\n};",
+		"export type QuerySearchArgs = {\n  filter?: InputMaybe<UserFilter>;\n};",
+	)
+	expectNotContains(t, output, "Nullable", "export interface")
+
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	expectContains(t, string(files[1].Content), "import type { InputMaybe } from './objects';\n")
+	expectContains(t, string(files[3].Content), "import type { InputMaybe, SearchResult } from './objects';\n")
+
+	for name, expected := range map[string]string{"ADMIN": "Admin", "HTTPStatus": "HttpStatus", "inProgress": "InProgress", "_1": "_1"} {
+		if converted := toPascalCase(name); converted != expected {
+			t.Errorf("Expected toPascalCase(%q) to be %q, got %q", name, expected, converted)
+		}
+	}
+
+	gen = newTestGenerator(t, "enum Status { IN_PROGRESS InProgress } type Query { status: Status }")
+	gen.opts.Preset = PresetCodegen
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), "InProgress") {
+		t.Errorf("Expected an error for enum values with the same member name, got %v", err)
+	}
+	gen.opts.Preset = "apollo"
+	if err := gen.Emit(context.Background(), io.Discard); err == nil {
+		t.Error("Expected an error for an unknown preset")
+	}
+}
+
+func TestSplitRoots(t *testing.T) {
+	gen := newTestGenerator(t, `
+		enum Order { ASC DESC }
+		type User { id: ID! posts(order: Order = DESC): [String!]! }
+		type Query { users(order: Order = ASC): [User!]! }
+		type Mutation { rename(id: ID!): User }
+	`)
+	gen.opts.SplitRoots = true
+	gen.opts.Resolvers = true
+	gen.opts.ArgumentDefaults = true
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	if expected := "enums.ts inputs.ts objects.ts operations.ts queries.ts mutations.ts index.ts"; strings.Join(names, " ") != expected {
+		t.Fatalf("Expected files %s, got %s", expected, strings.Join(names, " "))
+	}
+
+	operations := string(files[3].Content)
+	expectContains(t, operations, "export type Resolver<", "export const USER_POSTS_DEFAULTS = {\n  order: Order.DESC,\n} as const;")
+	expectNotContains(t, operations, "export interface Query", "QueryResolvers", "USERS_DEFAULTS")
+	expectContains(t, string(files[4].Content),
+		"import { Order } from './enums';\n",
+		"import type { User } from './objects';\nimport type { Resolver } from './operations';\n",
+		"export interface Query {\n",
+		"export interface QueryUsersArgs {\n",
+		"export interface QueryResolvers<",
+		"export const USERS_DEFAULTS = {\n  order: Order.ASC,\n} as const;",
+	)
+	expectNotContains(t, string(files[4].Content), "Mutation")
+	expectContains(t, string(files[5].Content), "export interface MutationResolvers<")
+	expectContains(t, string(files[6].Content), "export * from './queries';\nexport * from './mutations';\n")
+}
+
+func TestResponseTypes(t *testing.T) {
+	gen := newTestGenerator(t, `
+		type Project { id: ID! }
+		type Query { projects: [Project!]! }
+	`)
+	gen.opts.ResponseTypes = true
+	gen.opts.Vue = VueUrql
+	expectContains(t, emit(t, gen),
+		"export interface GraphQLError {\n  message: string;\n  locations?: Array<GraphQLErrorLocation>;\n  path?: Array<string | number>;\n",
+		"export interface GraphQLResponse<T> {\n  data?: T | null;\n  errors?: Array<GraphQLError>;\n",
+		"export type ProjectsQueryResponse = GraphQLResponse<ProjectsQuery>;\n",
+	)
+
+	gen.opts.ArraySyntax = ArrayType
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	expectContains(t, string(files[2].Content), "  path?: (string | number)[];\n", "  errors?: GraphQLError[];\n")
+	expectContains(t, string(files[3].Content), "import type { GraphQLResponse, Project } from './objects';\n")
+}
+
+func TestPagination(t *testing.T) {
+	schema := `
+		type User { id: ID! }
+		type UserEdge { cursor: String! node: User }
+		type UserConnection { edges: [UserEdge] pageInfo: PageInfo! }
+		type PageInfo { hasNextPage: Boolean! }
+		type Query { users: UserConnection! posts(skip: Int, limit: Int): [User!]! }
+	`
+	gen := newTestGenerator(t, schema)
+	gen.opts.Pagination = true
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export interface Edge<T> {\n  cursor: string;\n  node: T;\n}",
+		"export interface Paginated<T> {\n  edges: Array<Edge<T>>;\n  pageInfo: PageInfo;\n}",
+		"export type NodeOf<C> = C extends { edges?: ReadonlyArray<infer E> | null }\n",
+		"  after?: Nullable<string>;\n",
+	)
+	expectNotContains(t, output, "OffsetPaginationVariables", "hasPreviousPage")
+
+	gen.opts.PaginationFields = map[string]string{"offset": "skip"}
+	expectContains(t, emit(t, gen), "export interface OffsetPaginationVariables {\n  skip?: Nullable<number>;\n  limit?: Nullable<number>;\n}")
+
+	gen.opts.PaginationFields = map[string]string{"edges": "items"}
+	output = emit(t, gen)
+	expectNotContains(t, output, "Paginated", "OffsetPaginationVariables")
+
+	gen = newTestGenerator(t, "type Query { users(first: Int, after: String): [String!]! }")
+	gen.opts.Pagination = true
+	expectContains(t, emit(t, gen), "export interface PageInfo {\n  hasNextPage: boolean;\n", "export interface CursorPaginationVariables {\n")
+
+	gen.opts.PaginationFields = map[string]string{"page": "p"}
+	if err := gen.Emit(context.Background(), io.Discard); err == nil {
+		t.Error("Expected an error for an unknown pagination role")
+	}
+}
+
+func TestJSDoc(t *testing.T) {
+	gen := NewGenerator(WithJSDoc(true), WithResolvers(true))
+	err := gen.AddSource(context.Background(), "a.graphql", `# Copyright header
+
+# A user of the app
+# with two lines
+type User {
+  # Unique id
+  id: ID! # trailing comment
+  "Display name"
+  name: String @deprecated(reason: "Use fullName")
+  email: String
+}
+
+enum Role {
+  # Administrator
+  ADMIN
+  USER
+}
+
+type Query {
+  users(
+    # Page size
+    first: Int
+  ): [User!]!
+  role: Role
+}
+`, "")
+	if err != nil {
+		t.Fatalf("Failed to add source: %v", err)
+	}
+	output := emit(t, gen)
+	expectContains(t, output,
+		"/**\n * A user of the app\n * with two lines\n */\nexport interface User {\n  /** Unique id */\n  id: string;\n",
+		"  /**\n   * Display name\n   * @deprecated Use fullName\n   */\n  name?: Nullable<string>;\n  email?: Nullable<string>;\n",
+		"export enum Role {\n  /** Administrator */\n  ADMIN = 'ADMIN',\n  USER = 'USER',\n}",
+		"export interface QueryUsersArgs {\n  /** Page size */\n  first?: Nullable<number>;\n}",
+	)
+	expectNotContains(t, output, "Copyright", "trailing")
+}
+
+func TestImplementerUnions(t *testing.T) {
+	gen := newTestGenerator(t, `
+		interface Node { id: ID! }
+		interface Entity implements Node { id: ID! }
+		interface Orphan { id: ID! }
+		type User implements Node & Entity { id: ID! }
+		type Project implements Node { id: ID! }
+		type Query { node: Node orphan: Orphan }
+	`)
+	gen.opts.ImplementerUnions = true
+	gen.opts.Renames = map[string]string{"Project": "ApiProject"}
+	expectContains(t, emit(t, gen),
+		"export type EntityTypes = User;\n",
+		"export type NodeTypes = ApiProject | User;\n",
+		"export type OrphanTypes = never;\n",
+	)
+
+	gen = newTestGenerator(t, "interface Node { id: ID! }\ntype NodeTypes { id: ID! }\ntype Query { node: Node types: NodeTypes }")
+	gen.opts.ImplementerUnions = true
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), "Node implementers union") {
+		t.Errorf("Expected a collision with the implementers union, got %v", err)
+	}
+}
+
+func TestArgumentDefaults(t *testing.T) {
+	gen := newTestGenerator(t, `
+		enum Order { NEWEST OLDEST }
+		input PostFilter { tag: String limit: Int = 5 }
+		type User { id: ID! posts(first: Int = 10, order: Order = NEWEST, filter: PostFilter = { tag: "go" }): [String!]! }
+		type Query { getProjects(limit: Int = 20, after: String): [String!]! items(limit: Int = 1): Int user: User }
+		type Mutation { items(limit: Int = 3): Int }
+	`)
+	gen.opts.ArgumentDefaults = true
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export const GET_PROJECTS_DEFAULTS = {\n  limit: 20,\n} as const;\n",
+		"export const ITEMS_DEFAULTS = {\n  limit: 1,\n} as const;\n",
+		"export const MUTATION_ITEMS_DEFAULTS = {\n  limit: 3,\n} as const;\n",
+		"export const USER_POSTS_DEFAULTS = {\n  first: 10,\n  order: Order.NEWEST,\n  filter: { tag: 'go' },\n} as const;\n",
+	)
+
+	gen.opts.InputDefaults = true
+	expectContains(t, emit(t, gen), "  filter: { ...defaultPostFilter(), tag: 'go' },\n")
+
+	for name, expected := range map[string]string{"getProjects": "GET_PROJECTS", "getHTTPStatus": "GET_HTTP_STATUS", "user2FA": "USER2_FA", "ID": "ID"} {
+		if constant := constantName(name); constant != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, name, constant)
+		}
+	}
+}
+
+func TestExhaustive(t *testing.T) {
+	gen := newTestGenerator(t, `
+		enum Role { ADMIN MEMBER }
+		type User { id: ID! }
+		type Project { id: ID! }
+		type Secret { id: ID! }
+		union SearchResult = User | Project | Secret
+		type Query { search: [SearchResult!]! role: Role }
+	`)
+	gen.opts.Exhaustive = true
+	gen.opts.Exclude = []string{"Secret"}
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export function matchRole<R>(value: Role, cases: Record<Role, () => R>): R {\n  return cases[value]();\n}\n",
+		"export function assertNever(value: never): never {\n",
+		`export function matchSearchResult<R>(
+  value: (User & { __typename: 'User' }) | (Project & { __typename: 'Project' }),
+  cases: {
+    User: (value: User) => R;
+    Project: (value: Project) => R;
+  },
+): R {
+  switch (value.__typename) {
+    case 'User':
+      return cases.User(value);
+    case 'Project':
+      return cases.Project(value);
+    default:
+      return assertNever(value);
+  }
+}
+`,
+	)
+	expectNotContains(t, output, "Secret")
+}
+
+func TestInputMaybe(t *testing.T) {
+	schema := `
+		input UserFilter { name: String id: ID! }
+		type User { id: ID! name: String }
+		type Query { users(filter: UserFilter, first: Int): [User!]! }
+	`
+	gen := newTestGenerator(t, schema)
+	gen.opts.InputMaybe = true
+	gen.opts.Resolvers = true
+	output := emit(t, gen)
+	expectContains(t, output,
+		"type InputMaybe<T> = T | null | undefined;\n",
+		"export interface UserFilter {\n  name?: InputMaybe<string>;\n  id: string;\n}",
+		"export interface User {\n  id: string;\n  name?: Nullable<string>;\n}",
+		"  first?: InputMaybe<number>;\n",
+	)
+
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	expectContains(t, string(files[1].Content), "type InputMaybe<T> = T | null | undefined;\n")
+	expectNotContains(t, string(files[2].Content), "InputMaybe")
+
+	expectNotContains(t, emit(t, newTestGenerator(t, schema)), "InputMaybe")
+}
+
+func TestVueComposables(t *testing.T) {
+	schema := `
+		type Project { id: ID! name: String owner: User }
+		type User { id: ID! }
+		type Query { projects(first: Int!): [Project!]! viewer: User }
+		type Mutation { rename(id: ID!, name: String!): Project }
+	`
+	gen := newTestGenerator(t, schema)
+	gen.opts.Vue = VueUrql
+	gen.opts.VueDepth = 1
+	output := emit(t, gen)
+	expectContains(t, output,
+		"import type { UseQueryArgs } from '@urql/vue';\n\nimport { useMutation, useQuery } from '@urql/vue';\n",
+		"export const ProjectsQueryDocument = `query Projects($first: Int!) {\n  projects(first: $first) {\n    id\n    name\n  }\n}\n`;\n",
+		"export interface ProjectsQuery {\n  projects: Array<{\n    id: string;\n    name?: Nullable<string>;\n  }>;\n}\n",
+		"export interface ProjectsQueryVariables {\n  first: number;\n}\n",
+		"export function useProjectsQuery(options: Omit<UseQueryArgs<ProjectsQuery, ProjectsQueryVariables>, 'query'>) {\n"+
+			"  return useQuery<ProjectsQuery, ProjectsQueryVariables>({ ...options, query: ProjectsQueryDocument } as UseQueryArgs<ProjectsQuery, ProjectsQueryVariables>);\n}\n",
+		"export type ViewerQueryVariables = Record<string, never>;\n",
+		"export function useViewerQuery(options: Omit<UseQueryArgs<ViewerQuery, ViewerQueryVariables>, 'query'> = {}) {\n",
+		"export function useRenameMutation() {\n  return useMutation<RenameMutation, RenameMutationVariables>(RenameMutationDocument);\n}\n",
+	)
+
+	gen.opts.Vue = VueVillus
+	files, err := gen.EmitSplit(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to emit split files: %v", err)
+	}
+	expectContains(t, string(files[3].Content),
+		"import type { QueryCompositeOptions } from 'villus';\n\nimport { useMutation, useQuery } from 'villus';\n",
+		"export function useViewerQuery(options: Omit<QueryCompositeOptions<ViewerQuery, ViewerQueryVariables>, 'query'> = {}) {\n",
+	)
+	expectNotContains(t, string(files[2].Content), "villus")
+
+	gen.opts.Vue = "apollo"
+	if err := gen.Emit(context.Background(), io.Discard); err == nil || !strings.Contains(err.Error(), "unknown Vue client") {
+		t.Errorf("Expected an unknown Vue client error, got %v", err)
+	}
+}
+
+func TestTypeMetadata(t *testing.T) {
+	gen := NewGenerator(WithTypeMetadata(true))
+	gen.AddSource(context.Background(), "a.graphql", `
+		enum Role { ADMIN }
+		interface Node { id: ID! }
+		type Post implements Node { id: ID! }
+		type User implements Node { id: ID! role: Role posts: [Post!]! }
+		input UserFilter { role: Role ids: [ID!] }
+		type Query { users(filter: UserFilter): [User!]! }
+	`, "")
+
+	output := emit(t, gen)
+	expectContains(t, output,
+		"export const UserMetadata = {\n  name: 'User',\n  kind: 'object',\n  fields: {\n    id: { type: 'ID!', typeName: 'ID', kind: 'scalar', nullable: false, list: false },\n"+
+			"    role: { type: 'Role', typeName: 'Role', kind: 'enum', nullable: true, list: false },\n"+
+			"    posts: { type: '[Post!]!', typeName: 'Post', kind: 'object', nullable: false, list: true },\n  },\n} as const;\n",
+		"export const NodeMetadata = {\n  name: 'Node',\n  kind: 'interface',\n",
+		"export const UserFilterMetadata = {\n  name: 'UserFilter',\n  kind: 'input',\n  fields: {\n    role: { type: 'Role', typeName: 'Role', kind: 'enum', nullable: true, list: false },\n"+
+			"    ids: { type: '[ID!]', typeName: 'ID', kind: 'scalar', nullable: true, list: true },\n",
+	)
+	expectNotContains(t, output, "QueryMetadata")
+}
@@ -0,0 +1,226 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Built-in GraphQL scalar -> Go type mappings. Other custom scalars become any.
+var goScalars = map[string]string{
+	"String":     "string",
+	"Int":        "int",
+	"Float":      "float64",
+	"Boolean":    "bool",
+	"ID":         "string",
+	"DateTime":   "string",
+	"JSONObject": "map[string]any",
+}
+
+// Generate Go structs with json tags from the same model as the TypeScript output.
+// Nullable fields are pointers, enums are string types with a constant per value and
+// unions are interfaces implemented by their member structs.
+func (g *Generator) emitGo(ctx context.Context, w io.Writer) error {
+	schema := g.schema
+	selected := g.selectedTypes()
+	packageName := g.opts.GoPackage
+	if packageName == "" {
+		packageName = "generated"
+	}
+
+	// The output is gofmt-ed before writing, so it is built in memory
+	var file bytes.Buffer
+	file.WriteString("// Code generated by graphql-ts-generator. DO NOT EDIT.\n")
+	for _, line := range g.headerMetadata() {
+		file.WriteString("// " + line + "\n")
+	}
+	file.WriteString("\n")
+	file.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+
+	// Generate enums as string types with constants
+	for _, name := range orderedKeys(g, schema.Enums, "") {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if selected != nil && !selected[name] {
+			continue
+		}
+		enum := schema.Enums[name]
+		typeName := g.goTypeName(enum.Name)
+		file.WriteString(fmt.Sprintf("type %s string\n\nconst (\n", typeName))
+		for _, value := range enum.EnumValues {
+			file.WriteString(fmt.Sprintf("\t%s%s %s = %q\n", typeName, goIdentifier(strings.ToLower(value.Name)), typeName, value.Name))
+		}
+		file.WriteString(")\n\n")
+	}
+
+	// Generate structs for object types and interfaces
+	for _, name := range orderedKeys(g, schema.Types, "") {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if selected != nil && !selected[name] {
+			continue
+		}
+		typeInfo := schema.Types[name]
+		file.WriteString(fmt.Sprintf("type %s struct {\n", g.goTypeName(typeInfo.Name)))
+		for _, field := range g.objectFields(typeInfo.Definition) {
+			g.writeGoField(&file, typeInfo.Name, field)
+		}
+		file.WriteString("}\n\n")
+	}
+
+	// Generate unions as interfaces with a marker method implemented by their members
+	for _, name := range orderedKeys(g, schema.Unions, "") {
+		if selected != nil && !selected[name] {
+			continue
+		}
+		typeName := g.goTypeName(name)
+		file.WriteString(fmt.Sprintf("type %s interface {\n\tis%s()\n}\n\n", typeName, typeName))
+		for _, member := range schema.Unions[name].Types {
+			if _, found := schema.Types[member]; found && !g.isExcluded(member) && (selected == nil || selected[member]) {
+				file.WriteString(fmt.Sprintf("func (%s) is%s() {}\n\n", g.goTypeName(member), typeName))
+			}
+		}
+	}
+
+	// Generate structs for input types
+	for _, name := range orderedKeys(g, schema.Inputs, "") {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if selected != nil && !selected[name] {
+			continue
+		}
+		input := schema.Inputs[name]
+		file.WriteString(fmt.Sprintf("type %s struct {\n", g.goTypeName(input.Name)))
+		for _, field := range input.Fields {
+			g.writeGoField(&file, input.Name, field)
+		}
+		file.WriteString("}\n\n")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Generate root structs
+	for i, fields := range schema.roots() {
+		var fieldNames []string
+		for _, fieldName := range orderedKeys(g, fields, rootNames[i]) {
+			if g.rootFieldSelected(rootNames[i], fieldName) {
+				fieldNames = append(fieldNames, fieldName)
+			}
+		}
+		if len(fieldNames) == 0 {
+			continue
+		}
+
+		file.WriteString(fmt.Sprintf("type %s struct {\n", g.goTypeName(rootNames[i])))
+		for _, fieldName := range fieldNames {
+			g.writeGoField(&file, rootNames[i], fields[fieldName])
+		}
+		file.WriteString("}\n\n")
+	}
+
+	formatted, err := format.Source(file.Bytes())
+	if err != nil {
+		return fmt.Errorf("could not format Go output: %v", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// Generate a single struct field with its json tag
+func (g *Generator) writeGoField(file *bytes.Buffer, owner string, field *ast.FieldDefinition) {
+	fieldType := g.goType(field.Type)
+	// A struct can't contain itself, so references back to the owner, directly or through
+	// other structs, are always pointers
+	if field.Type.NonNull && field.Type.Elem == nil && g.goEmbeds(field.Type.NamedType, owner, make(map[string]bool)) {
+		fieldType = "*" + fieldType
+	}
+
+	tag := field.Name
+	if !field.Type.NonNull {
+		tag += ",omitempty"
+	}
+	file.WriteString(fmt.Sprintf("\t%s %s `json:\"%s\"`\n", goIdentifier(field.Name), fieldType, tag))
+}
+
+// Check whether the struct of the named type contains the struct of the target type by value,
+// through non-null fields that are not lists
+func (g *Generator) goEmbeds(name string, target string, visited map[string]bool) bool {
+	if name == target {
+		return true
+	}
+	var fields ast.FieldList
+	if typeInfo, found := g.schema.Types[name]; found {
+		fields = g.objectFields(typeInfo.Definition)
+	} else if input, found := g.schema.Inputs[name]; found {
+		fields = input.Fields
+	}
+	if fields == nil || visited[name] {
+		return false
+	}
+	visited[name] = true
+	for _, field := range fields {
+		if field.Type.NonNull && field.Type.Elem == nil && g.goEmbeds(field.Type.NamedType, target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// Convert a GraphQL type to a Go type. Nullable named types are pointers, lists are slices.
+func (g *Generator) goType(typ *ast.Type) string {
+	var goType string
+	if typ.Elem != nil {
+		return "[]" + g.goType(typ.Elem)
+	} else if scalar, found := goScalars[typ.NamedType]; found {
+		goType = scalar
+	} else if _, found := g.schema.Scalars[typ.NamedType]; found {
+		goType = "any"
+	} else if _, found := g.schema.Unions[typ.NamedType]; found {
+		// Interfaces are already nilable, so nullable unions aren't pointers
+		return g.goTypeName(typ.NamedType)
+	} else {
+		goType = g.goTypeName(typ.NamedType)
+	}
+
+	if !typ.NonNull && goType != "any" && !strings.HasPrefix(goType, "map[") {
+		return "*" + goType
+	}
+	return goType
+}
+
+// Return the exported Go name of a GraphQL type, honoring renames
+func (g *Generator) goTypeName(name string) string {
+	return goIdentifier(g.tsName(name))
+}
+
+// Convert a GraphQL name to an exported Go identifier, e.g. owner_name -> OwnerName, id -> ID
+func goIdentifier(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		if strings.EqualFold(part, "id") {
+			b.WriteString("ID")
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	if b.Len() == 0 {
+		return "X"
+	}
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package generator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// A reference from a root operation or a definition to another definition
+type graphEdge struct {
+	from string
+	to   string
+	// Root field name, empty for references between definitions
+	label string
+}
+
+// Return the definitions and references of the type dependency graph. Root operations
+// get one edge per field, other definitions one edge per referenced definition.
+// Scalars are left out, as every type references them.
+func (g *Generator) graphEdges() ([]*ast.Definition, []graphEdge) {
+	selected := g.selectedTypes()
+	included := func(name string) bool {
+		def := g.schema.definition(name)
+		return def != nil && def.Kind != ast.Scalar && !g.isExcluded(name) && (selected == nil || selected[name])
+	}
+
+	var edges []graphEdge
+	for i, fields := range g.schema.roots() {
+		for _, name := range sortedKeys(fields) {
+			if target := fields[name].Type.Name(); g.rootFieldSelected(rootNames[i], name) && included(target) {
+				edges = append(edges, graphEdge{rootNames[i], target, name})
+			}
+		}
+	}
+
+	var nodes []*ast.Definition
+	for _, section := range g.docsSections(selected) {
+		for _, def := range section.defs {
+			if !included(def.Name) {
+				continue
+			}
+			nodes = append(nodes, def)
+
+			references := make(map[string]bool)
+			for _, field := range def.Fields {
+				references[field.Type.Name()] = true
+				for _, arg := range field.Arguments {
+					references[arg.Type.Name()] = true
+				}
+			}
+			for _, name := range def.Interfaces {
+				references[name] = true
+			}
+			for _, name := range def.Types {
+				references[name] = true
+			}
+			for _, name := range sortedKeys(references) {
+				if included(name) {
+					edges = append(edges, graphEdge{def.Name, name, ""})
+				}
+			}
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes, edges
+}
+
+// Return the root operations with at least one edge, in Query, Mutation, Subscription order
+func usedRoots(edges []graphEdge) []string {
+	var roots []string
+	for _, root := range rootNames {
+		for _, edge := range edges {
+			if edge.from == root {
+				roots = append(roots, root)
+				break
+			}
+		}
+	}
+	return roots
+}
+
+// Generate a Graphviz DOT graph of the references between definitions
+func (g *Generator) emitDOT(ctx context.Context, w io.Writer) error {
+	nodes, edges := g.graphEdges()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	file := bufio.NewWriter(w)
+	file.WriteString("digraph schema {\n  rankdir=LR;\n  node [shape=box];\n\n")
+	for _, root := range usedRoots(edges) {
+		file.WriteString(fmt.Sprintf("  %q [shape=ellipse, style=bold];\n", root))
+	}
+	for _, def := range nodes {
+		file.WriteString(fmt.Sprintf("  %q%s;\n", def.Name, dotNodeStyle(def.Kind)))
+	}
+	file.WriteString("\n")
+	for _, edge := range edges {
+		if edge.label != "" {
+			file.WriteString(fmt.Sprintf("  %q -> %q [label=%q];\n", edge.from, edge.to, edge.label))
+		} else {
+			file.WriteString(fmt.Sprintf("  %q -> %q;\n", edge.from, edge.to))
+		}
+	}
+	file.WriteString("}\n")
+	return file.Flush()
+}
+
+func dotNodeStyle(kind ast.DefinitionKind) string {
+	switch kind {
+	case ast.Interface:
+		return " [style=dashed]"
+	case ast.Union:
+		return " [shape=diamond]"
+	case ast.InputObject:
+		return " [shape=parallelogram]"
+	case ast.Enum:
+		return " [shape=hexagon]"
+	}
+	return ""
+}
+
+// Generate a Mermaid flowchart of the references between definitions
+func (g *Generator) emitMermaid(ctx context.Context, w io.Writer) error {
+	nodes, edges := g.graphEdges()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	file := bufio.NewWriter(w)
+	file.WriteString("graph LR\n")
+	for _, root := range usedRoots(edges) {
+		file.WriteString(fmt.Sprintf("  %s([%s])\n", root, root))
+	}
+	for _, def := range nodes {
+		file.WriteString("  " + mermaidNode(def) + "\n")
+	}
+	for _, edge := range edges {
+		if edge.label != "" {
+			file.WriteString(fmt.Sprintf("  %s -->|%s| %s\n", edge.from, edge.label, edge.to))
+		} else {
+			file.WriteString(fmt.Sprintf("  %s --> %s\n", edge.from, edge.to))
+		}
+	}
+	return file.Flush()
+}
+
+func mermaidNode(def *ast.Definition) string {
+	shape := map[ast.DefinitionKind][2]string{
+		ast.Object:      {"[", "]"},
+		ast.Interface:   {"[/", "/]"},
+		ast.Union:       {"{", "}"},
+		ast.InputObject: {"[\\", "\\]"},
+		ast.Enum:        {"{{", "}}"},
+	}[def.Kind]
+	return def.Name + shape[0] + def.Name + shape[1]
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"bufio"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Version of the generator, shown in the header of generated files.
+// Release builds set it with -ldflags "-X graphql-ts-generator/generator.Version=...".
+var Version = "0.0.2-alpha1"
+
+// Comments disabling each linter for the whole file
+var lintSuppressions = map[string]string{
+	SuppressTslint: "/* tslint:disable */",
+	SuppressEslint: "/* eslint-disable */",
+	SuppressBiome:  "/* biome-ignore-all lint: generated file */",
+}
+
+// Generate the lint suppression comments of the configured linters, or of the target defaults
+func (g *Generator) writeLintSuppressions(file *bufio.Writer, defaults []string) {
+	linters := defaults
+	if g.opts.LintSuppressions != nil {
+		linters = g.opts.LintSuppressions
+	}
+	if len(linters) == 0 {
+		return
+	}
+	for _, linter := range linters {
+		file.WriteString(lintSuppressions[linter] + "\n")
+	}
+	file.WriteString("\n")
+}
+
+// Return the configured metadata lines of the header of generated files
+func (g *Generator) headerMetadata() []string {
+	var lines []string
+	if g.opts.HeaderSchemaHash {
+		lines = append(lines, "Schema hash: sha256:"+g.schemaHash())
+	}
+	if g.opts.HeaderVersion {
+		lines = append(lines, "Generator: graphql-ts-generator "+Version)
+	}
+	if !g.opts.HeaderTimestamp.IsZero() {
+		lines = append(lines, "Generated at: "+g.opts.HeaderTimestamp.UTC().Format(time.RFC3339))
+	}
+	return lines
+}
+
+// Return the hash of the added schema sources. It depends on their content only, not on their names or order.
+func (g *Generator) schemaHash() string {
+	hashes := append([]string{}, g.sourceHashes...)
+	sort.Strings(hashes)
+	return contentHash(strings.Join(hashes, "\n"))
+}
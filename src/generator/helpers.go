@@ -0,0 +1,218 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Generate the optional helper exports of the object types
+func (g *Generator) writeHelpers(file *bufio.Writer, selected map[string]bool) {
+	if g.opts.TypeNameUnion {
+		g.writeTypeNameUnion(file, selected)
+	}
+	if g.opts.TypeMap {
+		g.writeTypeMap(file, selected)
+	}
+	if g.opts.ImplementerUnions {
+		g.writeImplementerUnions(file, selected)
+	}
+	if g.opts.Exhaustive {
+		g.writeUnionMatches(file, selected)
+	}
+	if g.opts.ResponseTypes {
+		g.writeResponseTypes(file)
+	}
+	if g.opts.Pagination {
+		g.writePaginationHelpers(file, selected)
+	}
+}
+
+// Return the names of the emitted object types, sorted
+func (g *Generator) objectTypeNames(selected map[string]bool) []string {
+	var names []string
+	for _, name := range sortedKeys(g.schema.Types) {
+		if g.schema.Types[name].Definition.Kind == ast.Object && (selected == nil || selected[name]) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Generate a union of all object type names, as found in __typename
+func (g *Generator) writeTypeNameUnion(file *bufio.Writer, selected map[string]bool) {
+	names := g.objectTypeNames(selected)
+	if len(names) == 0 {
+		file.WriteString("export type TypeName = never;\n\n")
+		return
+	}
+
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("'%s'", name)
+	}
+	file.WriteString(fmt.Sprintf("export type TypeName = %s;\n\n", strings.Join(quoted, " | ")))
+}
+
+// Return the name of the union of the object types implementing an interface, e.g. NodeTypes
+func (g *Generator) implementerUnionName(iface string) string {
+	return g.tsName(iface) + "Types"
+}
+
+// Generate a union of the object types implementing each interface, which is what the values of
+// interface fields are, and what narrowing on __typename needs
+func (g *Generator) writeImplementerUnions(file *bufio.Writer, selected map[string]bool) {
+	for _, name := range orderedKeys(g, g.schema.Types, "") {
+		if g.schema.Types[name].Definition.Kind != ast.Interface || (selected != nil && !selected[name]) {
+			continue
+		}
+		var members []string
+		for _, implementer := range g.schema.implementers(name) {
+			def := g.schema.Types[implementer].Definition
+			if def.Kind == ast.Object && !g.isExcluded(implementer) && (selected == nil || selected[implementer]) {
+				members = append(members, g.convertGraphqlTypeToTs(implementer))
+			}
+		}
+		if len(members) == 0 {
+			members = []string{"never"}
+		}
+		file.WriteString(fmt.Sprintf("export type %s = %s;\n\n", g.implementerUnionName(name), strings.Join(members, " | ")))
+	}
+}
+
+// Generate an interface mapping every type name to its generated interface
+func (g *Generator) writeTypeMap(file *bufio.Writer, selected map[string]bool) {
+	file.WriteString("export interface TypeMap {\n")
+	for _, name := range sortedKeys(g.schema.Types) {
+		if selected == nil || selected[name] {
+			file.WriteString(fmt.Sprintf("  %s: %s;\n", name, g.tsName(name)))
+		}
+	}
+	file.WriteString("}\n\n")
+}
+
+// Generate a const array of the runtime values of an enum, for iterating its members
+func (g *Generator) writeEnumValues(file *bufio.Writer, enum *ast.Definition) {
+	values := make([]string, len(enum.EnumValues))
+	for i, value := range enum.EnumValues {
+		values[i] = g.enumValue(enum, value, i)
+	}
+	file.WriteString(fmt.Sprintf("export const %sValues = [%s] as const;\n\n", g.tsName(enum.Name), strings.Join(values, ", ")))
+}
+
+// Generate the helpers converting untyped values to an enum: `isUserRole(value: unknown): value is UserRole`,
+// and `parseUserRole(value: string): UserRole | undefined` returning the member of a GraphQL value name,
+// for values read from URLs, local storage or external APIs
+func (g *Generator) writeEnumHelpers(file *bufio.Writer, enum *ast.Definition) {
+	tsName := g.tsName(enum.Name)
+	members := make([]string, len(enum.EnumValues))
+	for i, value := range enum.EnumValues {
+		members[i] = g.enumMember(enum.Name, value.Name)
+	}
+	file.WriteString(fmt.Sprintf("export function is%s(value: unknown): value is %s {\n", tsName, tsName))
+	file.WriteString(fmt.Sprintf("  return ([%s] as unknown[]).includes(value);\n}\n\n", strings.Join(members, ", ")))
+
+	file.WriteString(fmt.Sprintf("export function parse%s(value: string): %s | undefined {\n  switch (value) {\n", tsName, tsName))
+	for i, value := range enum.EnumValues {
+		file.WriteString(fmt.Sprintf("    case %s:\n      return %s;\n", quoteString(value.Name), members[i]))
+	}
+	file.WriteString("  }\n  return undefined;\n}\n\n")
+}
+
+// Generate a const object of the root field names of all operation types, with a union of its values
+func (g *Generator) writeOperationNames(file *bufio.Writer) {
+	names := map[string]bool{}
+	for i, fields := range g.schema.roots() {
+		for name := range fields {
+			if g.rootFieldSelected(rootNames[i], name) {
+				names[name] = true
+			}
+		}
+	}
+
+	file.WriteString("export const OperationNames = {\n")
+	for _, name := range sortedKeys(names) {
+		file.WriteString(fmt.Sprintf("  %s: '%s',\n", g.propertyKey(name), name))
+	}
+	file.WriteString("} as const;\n\n")
+	file.WriteString("export type OperationName = typeof OperationNames[keyof typeof OperationNames];\n\n")
+}
+
+// A Query or Mutation field with the name of the alias of its result type
+type resultAlias struct {
+	name string
+	op   operationField
+}
+
+// Return the result type aliases of the selected Query and Mutation fields, named after the field,
+// e.g. GetProjectsResult, or after the root type and the field when a query and a mutation share the name,
+// or when the name is taken by a schema type, such as QuerySearchResult for a search field returning a SearchResult union
+func (g *Generator) resultAliases() []resultAlias {
+	var aliases []resultAlias
+	used := make(map[string]bool)
+	for _, name := range rootNames {
+		used[name] = true
+	}
+	for _, defs := range []map[string]*ast.Definition{g.definitionsOfKind(ast.Object), g.definitionsOfKind(ast.Interface), g.schema.Enums, g.schema.Inputs, g.schema.Unions, g.schema.Scalars} {
+		for name := range defs {
+			used[g.tsName(name)] = true
+		}
+	}
+	for _, op := range g.operations() {
+		name := operationName(op.field) + "Result"
+		if used[name] {
+			name = op.root + name
+		}
+		used[name] = true
+		aliases = append(aliases, resultAlias{name, op})
+	}
+	return aliases
+}
+
+// Generate an alias of the result type of each Query and Mutation field, e.g. `export type GetProjectsResult = Array<Project>`,
+// so that client code names the result of an operation without indexing the root interface.
+// Only the fields of the given root type are written, or of every root type if empty.
+func (g *Generator) writeResultAliases(file *bufio.Writer, root string) {
+	for _, alias := range g.resultAliases() {
+		if root != "" && alias.op.root != root {
+			continue
+		}
+		tsType := g.fieldType(alias.op.root, alias.op.field)
+		if !alias.op.field.Type.NonNull {
+			tsType = g.nullable(tsType)
+		}
+		file.WriteString(fmt.Sprintf("export type %s = %s;\n\n", alias.name, tsType))
+	}
+}
+
+// Recursively optional version of a type. Arrays keep their element type partial, dates are kept whole.
+const deepPartialHelper = `export type DeepPartial<T> = T extends ReadonlyArray<infer U>
+  ? Array<DeepPartial<U>>
+  : T extends Date
+    ? T
+    : T extends object
+      ? { [K in keyof T]?: DeepPartial<T[K]> }
+      : T;
+
+`
+
+// Generate the DeepPartial helper and a partial alias of each input type.
+// With the StrictTS option, the optional properties also accept an explicit undefined.
+func (g *Generator) writePartialInputs(file *bufio.Writer, selected map[string]bool) {
+	var replacements []string
+	if g.opts.ArraySyntax == ArrayType {
+		replacements = append(replacements, "ReadonlyArray<infer U>", "readonly (infer U)[]", "Array<DeepPartial<U>>", "DeepPartial<U>[]")
+	}
+	if g.opts.StrictTS {
+		replacements = append(replacements, "DeepPartial<T[K]> }", "DeepPartial<T[K]> | undefined }")
+	}
+	file.WriteString(strings.NewReplacer(replacements...).Replace(deepPartialHelper))
+	for _, name := range orderedKeys(g, g.schema.Inputs, "") {
+		if selected == nil || selected[name] {
+			file.WriteString(fmt.Sprintf("export type Partial%s = DeepPartial<%s>;\n", g.tsName(name), g.tsName(name)))
+		}
+	}
+	file.WriteString("\n")
+}
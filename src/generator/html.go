@@ -0,0 +1,204 @@
+package generator
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+//go:embed templates/docs.html.tmpl
+var htmlDocsTemplate string
+
+var htmlDocs = template.Must(template.New("docs").Parse(htmlDocsTemplate))
+
+type htmlDocsPage struct {
+	Title    string
+	Sections []htmlDocsSection
+}
+
+type htmlDocsSection struct {
+	Title   string
+	Entries []htmlDocsEntry
+}
+
+type htmlDocsEntry struct {
+	Name         string
+	Anchor       string
+	Kind         string
+	Description  string
+	Deprecated   string
+	Returns      *htmlDocsType
+	RelatedLabel string
+	Related      []htmlDocsType
+	RowLabel     string
+	Rows         []htmlDocsRow
+	SearchText   string
+}
+
+type htmlDocsRow struct {
+	Name        string
+	Type        *htmlDocsType
+	Default     string
+	Description string
+	Deprecated  string
+}
+
+// A type reference, linked to its entry when documented
+type htmlDocsType struct {
+	Text string
+	Link string
+}
+
+// Generate a self-contained, searchable HTML page documenting the root fields and all types
+func (g *Generator) emitHTMLDocs(ctx context.Context, w io.Writer) error {
+	selected := g.selectedTypes()
+	page := htmlDocsPage{Title: "Schema Reference"}
+
+	for i, fields := range g.schema.roots() {
+		section := htmlDocsSection{Title: rootNames[i]}
+		for _, name := range sortedKeys(fields) {
+			if !g.rootFieldSelected(rootNames[i], name) {
+				continue
+			}
+			field := fields[name]
+			entry := htmlDocsEntry{
+				Name:        rootNames[i] + "." + field.Name,
+				Anchor:      rootNames[i] + "." + field.Name,
+				Kind:        "field",
+				Description: strings.TrimSpace(field.Description),
+				Returns:     g.htmlDocsType(field.Type),
+				RowLabel:    "Argument",
+				Rows:        g.htmlDocsArguments(field.Arguments),
+			}
+			entry.Deprecated, _ = deprecationReason(field.Directives)
+			section.Entries = append(section.Entries, entry)
+		}
+		if len(section.Entries) > 0 {
+			page.Sections = append(page.Sections, section)
+		}
+	}
+
+	for _, docs := range g.docsSections(selected) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		section := htmlDocsSection{Title: docs.title}
+		for _, def := range docs.defs {
+			section.Entries = append(section.Entries, g.htmlDocsDefinition(def))
+		}
+		page.Sections = append(page.Sections, section)
+	}
+
+	for i := range page.Sections {
+		for j := range page.Sections[i].Entries {
+			entry := &page.Sections[i].Entries[j]
+			entry.SearchText = htmlDocsSearchText(entry)
+		}
+	}
+
+	file := bufio.NewWriter(w)
+	if err := htmlDocs.Execute(file, page); err != nil {
+		return err
+	}
+	return file.Flush()
+}
+
+// Build the entry of a type, input, enum, union or scalar
+func (g *Generator) htmlDocsDefinition(def *ast.Definition) htmlDocsEntry {
+	entry := htmlDocsEntry{
+		Name:        def.Name,
+		Anchor:      def.Name,
+		Kind:        strings.ToLower(strings.ReplaceAll(string(def.Kind), "_", " ")),
+		Description: strings.TrimSpace(def.Description),
+	}
+	entry.Deprecated, _ = deprecationReason(def.Directives)
+
+	if len(def.Interfaces) > 0 {
+		entry.RelatedLabel = "Implements"
+		for _, name := range def.Interfaces {
+			entry.Related = append(entry.Related, htmlDocsType{Text: name, Link: name})
+		}
+	}
+	if len(def.Types) > 0 {
+		entry.RelatedLabel = "Possible types"
+		for _, name := range def.Types {
+			entry.Related = append(entry.Related, htmlDocsType{Text: name, Link: name})
+		}
+	}
+
+	if len(def.Fields) > 0 {
+		entry.RowLabel = "Field"
+		for _, field := range def.Fields {
+			row := htmlDocsRow{
+				Name:        field.Name,
+				Type:        g.htmlDocsType(field.Type),
+				Description: strings.TrimSpace(field.Description),
+			}
+			if field.DefaultValue != nil {
+				row.Default = field.DefaultValue.String()
+			}
+			row.Deprecated, _ = deprecationReason(field.Directives)
+			entry.Rows = append(entry.Rows, row)
+			entry.Rows = append(entry.Rows, g.htmlDocsArgumentsOf(field)...)
+		}
+	}
+	if len(def.EnumValues) > 0 {
+		entry.RowLabel = "Value"
+		for _, value := range def.EnumValues {
+			row := htmlDocsRow{Name: value.Name, Description: strings.TrimSpace(value.Description)}
+			row.Deprecated, _ = deprecationReason(value.Directives)
+			entry.Rows = append(entry.Rows, row)
+		}
+	}
+	return entry
+}
+
+// Build the argument rows of a root field
+func (g *Generator) htmlDocsArguments(args ast.ArgumentDefinitionList) []htmlDocsRow {
+	var rows []htmlDocsRow
+	for _, arg := range args {
+		row := htmlDocsRow{
+			Name:        arg.Name,
+			Type:        g.htmlDocsType(arg.Type),
+			Description: strings.TrimSpace(arg.Description),
+		}
+		if arg.DefaultValue != nil {
+			row.Default = arg.DefaultValue.String()
+		}
+		row.Deprecated, _ = deprecationReason(arg.Directives)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Build the argument rows of a type field, listed below the field as field.argument
+func (g *Generator) htmlDocsArgumentsOf(field *ast.FieldDefinition) []htmlDocsRow {
+	rows := g.htmlDocsArguments(field.Arguments)
+	for i := range rows {
+		rows[i].Name = field.Name + "." + rows[i].Name
+	}
+	return rows
+}
+
+// Render a type reference, linking the named type unless it is a built-in scalar
+func (g *Generator) htmlDocsType(typ *ast.Type) *htmlDocsType {
+	name := typ.Name()
+	if _, builtIn := defaultScalars[name]; builtIn && g.schema.Scalars[name] == nil {
+		return &htmlDocsType{Text: typ.String()}
+	}
+	return &htmlDocsType{Text: typ.String(), Link: name}
+}
+
+// Lowercased text matched by the search box
+func htmlDocsSearchText(entry *htmlDocsEntry) string {
+	parts := []string{entry.Name, entry.Description}
+	for _, row := range entry.Rows {
+		parts = append(parts, row.Name, row.Description)
+	}
+	return strings.ToLower(strings.Join(parts, " "))
+}
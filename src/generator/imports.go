@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// A TypeScript type, optionally imported from a module
+type importedType struct {
+	tsType string
+	module string
+}
+
+// Parse a type reference of the form Name or module#Name
+func parseImportedType(value string) importedType {
+	if module, name, found := strings.Cut(value, "#"); found {
+		return importedType{tsType: name, module: module}
+	}
+	return importedType{tsType: value}
+}
+
+// Look up the configured TypeScript type of a Type.field
+func (g *Generator) fieldTypeOverride(owner string, field string) (importedType, bool) {
+	value, found := g.opts.FieldTypes[owner+"."+field]
+	if !found {
+		return importedType{}, false
+	}
+	imported := parseImportedType(value)
+	g.useImport(imported)
+	return imported, true
+}
+
+// Type of the info argument of resolvers
+var resolveInfoImport = importedType{tsType: "GraphQLResolveInfo", module: "graphql"}
+
+// Types referenced by the declarations written so far, collected while emitting split files
+type references struct {
+	// GraphQL names of the referenced schema types
+	types map[string]bool
+	// GraphQL names of the schema types used as values, such as enums whose members are referenced
+	values map[string]bool
+	// Imported type names by module
+	imports map[string]map[string]bool
+}
+
+func newReferences() *references {
+	return &references{types: make(map[string]bool), values: make(map[string]bool), imports: make(map[string]map[string]bool)}
+}
+
+// Record an imported type, if it comes from a module
+func (r *references) addImport(imported importedType) {
+	if imported.module == "" {
+		return
+	}
+	if r.imports[imported.module] == nil {
+		r.imports[imported.module] = make(map[string]bool)
+	}
+	r.imports[imported.module][imported.tsType] = true
+}
+
+// Record the use of an imported type while references are collected
+func (g *Generator) useImport(imported importedType) {
+	if g.references != nil {
+		g.references.addImport(imported)
+	}
+}
+
+// Record the use of a generated helper type while references are collected, so that split files import it
+func (g *Generator) useHelper(name string) {
+	if g.references != nil {
+		g.references.types[name] = true
+	}
+}
+
+// Record the use of a schema type as a value while references are collected
+func (g *Generator) useValue(name string) {
+	if g.references != nil {
+		g.references.values[name] = true
+	}
+}
+
+// Return all types imported by the configured field types and scalars, and by the resolver types
+func (g *Generator) configuredImports() *references {
+	refs := newReferences()
+	for _, value := range g.opts.FieldTypes {
+		refs.addImport(parseImportedType(value))
+	}
+	for _, value := range g.opts.Scalars {
+		refs.addImport(parseImportedType(value))
+	}
+	if g.opts.Resolvers {
+		refs.addImport(resolveInfoImport)
+	}
+	if queries, _ := g.vueOperationKinds(); queries && g.opts.Vue != "" {
+		client := vueClients[g.opts.Vue]
+		refs.addImport(importedType{tsType: client.queryOptions, module: client.module})
+	}
+	return refs
+}
+
+// Generate the import statements of all configured imported types, one per module
+func (g *Generator) writeImports(file *bufio.Writer) {
+	g.writeImportStatements(file, g.configuredImports().imports, 0)
+}
+
+// Generate import statements, one per module. Relative modules are resolved from the output directory;
+// depth is the number of directories between it and the written file.
+func (g *Generator) writeImportStatements(file *bufio.Writer, modules map[string]map[string]bool, depth int) {
+	if len(modules) == 0 {
+		return
+	}
+
+	for _, module := range sortedKeys(modules) {
+		specifier := module
+		if depth > 0 && (strings.HasPrefix(module, "./") || strings.HasPrefix(module, "../")) {
+			specifier = strings.Repeat("../", depth) + strings.TrimPrefix(path.Clean(module), "./")
+		}
+		file.WriteString(fmt.Sprintf("import type { %s } from '%s';\n", strings.Join(sortedKeys(modules[module]), ", "), g.moduleSpecifier(specifier)))
+	}
+	file.WriteString("\n")
+}
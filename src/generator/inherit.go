@@ -0,0 +1,32 @@
+package generator
+
+import "github.com/vektah/gqlparser/v2/ast"
+
+// Return the fields emitted for an object type or interface: its own fields, followed by the
+// fields of the interfaces it implements, directly or not, when InheritInterfaceFields is set
+func (g *Generator) objectFields(def *ast.Definition) ast.FieldList {
+	if !g.opts.InheritInterfaceFields || len(def.Interfaces) == 0 {
+		return def.Fields
+	}
+
+	fields := append(ast.FieldList{}, def.Fields...)
+	visited := map[string]bool{def.Name: true}
+	var inherit func(names []string)
+	inherit = func(names []string) {
+		for _, name := range names {
+			typeInfo, found := g.schema.Types[name]
+			if visited[name] || !found || g.isExcluded(name) {
+				continue
+			}
+			visited[name] = true
+			for _, field := range typeInfo.Definition.Fields {
+				if fields.ForName(field.Name) == nil {
+					fields = append(fields, field)
+				}
+			}
+			inherit(typeInfo.Definition.Interfaces)
+		}
+	}
+	inherit(def.Interfaces)
+	return fields
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Return the name of the directive hiding types and fields from the output
+func (g *Generator) internalDirective() string {
+	if g.opts.InternalDirective == "" {
+		return "internal"
+	}
+	return g.opts.InternalDirective
+}
+
+// Check whether a definition or field carries the internal directive
+func (g *Generator) isInternal(directives ast.DirectiveList) bool {
+	return directives.ForName(g.internalDirective()) != nil
+}
+
+// Return the definition without its internal fields, arguments and enum values.
+// Parsed schemas are shared between runs, so a copy is returned instead of changing the definition.
+func (g *Generator) withoutInternal(def *ast.Definition) *ast.Definition {
+	var fields ast.FieldList
+	changed := false
+	for _, field := range def.Fields {
+		if g.isInternal(field.Directives) {
+			changed = true
+			continue
+		}
+
+		var args ast.ArgumentDefinitionList
+		for _, arg := range field.Arguments {
+			if !g.isInternal(arg.Directives) {
+				args = append(args, arg)
+			}
+		}
+		if len(args) != len(field.Arguments) {
+			copied := *field
+			copied.Arguments = args
+			field = &copied
+			changed = true
+		}
+		fields = append(fields, field)
+	}
+
+	var values ast.EnumValueList
+	for _, value := range def.EnumValues {
+		if !g.isInternal(value.Directives) {
+			values = append(values, value)
+		}
+	}
+	if len(values) != len(def.EnumValues) {
+		changed = true
+	}
+
+	if !changed {
+		return def
+	}
+	copied := *def
+	copied.Fields = fields
+	copied.EnumValues = values
+	return &copied
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/lexer"
+)
+
+// Full-line # comments of a schema file by line, collected before parsing as the parser drops them
+type lineComments map[int]string
+
+// Collect the comments that are alone on their line. Comments after a definition on the same line
+// are not leading comments of the next one.
+func scanComments(name string, content string) (lineComments, error) {
+	comments := make(lineComments)
+	lex := lexer.New(&ast.Source{Name: name, Input: content})
+	previousLine := 0
+	for {
+		token, err := lex.ReadToken()
+		if err != nil {
+			return nil, err
+		}
+		if token.Kind == lexer.EOF {
+			return comments, nil
+		}
+		if token.Kind == lexer.Comment && token.Pos.Line != previousLine {
+			comments[token.Pos.Line] = strings.TrimSpace(strings.TrimPrefix(token.Value, "#"))
+		}
+		previousLine = token.Pos.Line
+	}
+}
+
+// Return the description of an element, or the # comment lines right above it when it has none,
+// as older schemas document their fields with comments. Comments separated from the element
+// by a blank line, such as file headers, are not part of its description.
+func (g *Generator) description(description string, pos *ast.Position) string {
+	if description != "" || pos == nil || pos.Src == nil {
+		return description
+	}
+	comments := g.comments[pos.Src.Name]
+	var lines []string
+	for line := pos.Line - 1; ; line-- {
+		comment, found := comments[line]
+		if !found {
+			break
+		}
+		lines = append([]string{comment}, lines...)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Return the lines of the JSDoc of an element with the JSDoc option: its description and its deprecation
+func (g *Generator) docLines(description string, pos *ast.Position, directives ast.DirectiveList) []string {
+	if !g.opts.JSDoc {
+		return nil
+	}
+	var lines []string
+	if text := g.description(description, pos); text != "" {
+		lines = strings.Split(text, "\n")
+	}
+	if reason, deprecated := deprecationReason(directives); deprecated {
+		lines = append(lines, "@deprecated "+reason)
+	}
+	return lines
+}
+
+// Return the JSDoc lines of a field, with the GraphQL name of fields renamed from reserved words
+func (g *Generator) fieldDocLines(field *ast.FieldDefinition) []string {
+	lines := g.docLines(field.Description, field.Position, field.Directives)
+	if g.renamesReserved(field) {
+		lines = append(lines, "GraphQL field: "+field.Name)
+	}
+	return lines
+}
+
+// Return the JSDoc lines of a type definition
+func (g *Generator) definitionDocLines(def *ast.Definition) []string {
+	return g.docLines(def.Description, def.Position, def.Directives)
+}
+
+// Generate a JSDoc comment, on one line if it has a single line
+func writeDocComment(file *bufio.Writer, indent string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(line, "*/", "*\\/")
+	}
+	if len(lines) == 1 {
+		file.WriteString(indent + "/** " + lines[0] + " */\n")
+		return
+	}
+	file.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		file.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	file.WriteString(indent + " */\n")
+}
@@ -0,0 +1,171 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Severity of a lint rule
+type Severity string
+
+const (
+	SeverityOff     Severity = "off"
+	SeverityWarning Severity = "warn"
+	SeverityError   Severity = "error"
+)
+
+// Lint rules
+const (
+	LintTypeNames         = "type-names"         // Types, interfaces and enums are PascalCase
+	LintFieldNames        = "field-names"        // Fields are camelCase
+	LintEnumValues        = "enum-values"        // Enum values are UPPER_CASE
+	LintDescriptions      = "descriptions"       // Types and fields have a description
+	LintForbiddenPrefixes = "forbidden-prefixes" // Type names don't start with a forbidden prefix
+	LintOrphanTypes       = "orphan-types"       // Types, enums, inputs and unions are reachable from a root field
+)
+
+// Severities used for rules not configured in Options.LintRules
+var defaultLintSeverities = map[string]Severity{
+	LintTypeNames:         SeverityWarning,
+	LintFieldNames:        SeverityWarning,
+	LintEnumValues:        SeverityWarning,
+	LintDescriptions:      SeverityOff,
+	LintForbiddenPrefixes: SeverityError,
+	LintOrphanTypes:       SeverityWarning,
+}
+
+var (
+	pascalCase = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	camelCase  = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+	upperCase  = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+)
+
+// LintIssue is a lint rule violation
+type LintIssue struct {
+	Rule     string
+	Severity Severity
+	Position string
+	Message  string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s [%s]: %s", i.Position, i.Severity, i.Rule, i.Message)
+}
+
+// ParseSeverity validates a severity name
+func ParseSeverity(name string) (Severity, error) {
+	switch severity := Severity(name); severity {
+	case SeverityOff, SeverityWarning, SeverityError:
+		return severity, nil
+	}
+	return "", fmt.Errorf("unknown severity %q, expected off, warn or error", name)
+}
+
+// IsLintRule reports whether name is a known lint rule
+func IsLintRule(name string) bool {
+	_, found := defaultLintSeverities[name]
+	return found
+}
+
+// Lint checks the merged schema against the lint rules and returns all violations in schema order
+func (g *Generator) Lint() []LintIssue {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var issues []LintIssue
+	report := func(rule string, pos *ast.Position, format string, a ...any) {
+		severity := g.lintSeverity(rule)
+		if severity == SeverityOff {
+			return
+		}
+		issues = append(issues, LintIssue{
+			Rule:     rule,
+			Severity: severity,
+			Position: formatPosition(pos),
+			Message:  fmt.Sprintf(format, a...),
+		})
+	}
+
+	checkDefinition := func(def *ast.Definition) {
+		if !pascalCase.MatchString(def.Name) {
+			report(LintTypeNames, def.Position, "%s should be PascalCase", def.Name)
+		}
+		if strings.TrimSpace(def.Description) == "" {
+			report(LintDescriptions, def.Position, "%s has no description", def.Name)
+		}
+		for _, prefix := range g.opts.ForbiddenPrefixes {
+			if strings.HasPrefix(def.Name, prefix) {
+				report(LintForbiddenPrefixes, def.Position, "%s starts with forbidden prefix %q", def.Name, prefix)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(g.schema.Enums) {
+		enum := g.schema.Enums[name]
+		checkDefinition(enum)
+		for _, value := range enum.EnumValues {
+			if !upperCase.MatchString(value.Name) {
+				report(LintEnumValues, value.Position, "%s.%s should be UPPER_CASE", enum.Name, value.Name)
+			}
+		}
+	}
+	for _, name := range sortedKeys(g.schema.Types) {
+		checkDefinition(g.schema.Types[name].Definition)
+	}
+	g.forEachField(func(owner string, field *ast.FieldDefinition) {
+		if !camelCase.MatchString(field.Name) {
+			report(LintFieldNames, field.Position, "%s.%s should be camelCase", owner, field.Name)
+		}
+		if strings.TrimSpace(field.Description) == "" {
+			report(LintDescriptions, field.Position, "%s.%s has no description", owner, field.Name)
+		}
+	})
+	for _, def := range g.orphanTypes() {
+		report(LintOrphanTypes, def.Position, "%s is not reachable from any Query, Mutation or Subscription field", def.Name)
+	}
+
+	return issues
+}
+
+// Return the definitions that no root field reaches, sorted by name.
+// A schema without root fields has no orphans, as it only declares shared types.
+func (g *Generator) orphanTypes() []*ast.Definition {
+	walker := &typeWalker{schema: g.schema, reachable: make(map[string]bool), excluded: g.isExcluded}
+	hasRoots := false
+	for _, fields := range g.schema.roots() {
+		for _, name := range sortedKeys(fields) {
+			walker.visitField(fields[name])
+			hasRoots = true
+		}
+	}
+	if !hasRoots {
+		return nil
+	}
+
+	defs := make(map[string]*ast.Definition)
+	for name, typeInfo := range g.schema.Types {
+		defs[name] = typeInfo.Definition
+	}
+	for _, kind := range []map[string]*ast.Definition{g.schema.Enums, g.schema.Inputs, g.schema.Unions} {
+		for name, def := range kind {
+			defs[name] = def
+		}
+	}
+
+	var orphans []*ast.Definition
+	for _, name := range sortedKeys(defs) {
+		if !walker.reachable[name] && !g.isExcluded(name) {
+			orphans = append(orphans, defs[name])
+		}
+	}
+	return orphans
+}
+
+func (g *Generator) lintSeverity(rule string) Severity {
+	if severity, found := g.opts.LintRules[rule]; found {
+		return severity
+	}
+	return defaultLintSeverities[rule]
+}
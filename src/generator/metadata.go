@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Return the name of the runtime metadata constant of a type
+func (g *Generator) metadataName(name string) string {
+	return g.tsName(name) + "Metadata"
+}
+
+// Return the kind of a named type in the metadata: scalar, enum, object, interface, union or input
+func (g *Generator) metadataKind(name string) string {
+	if def := g.schema.definition(name); def != nil {
+		switch def.Kind {
+		case ast.Enum:
+			return "enum"
+		case ast.Object:
+			return "object"
+		case ast.Interface:
+			return "interface"
+		case ast.Union:
+			return "union"
+		case ast.InputObject:
+			return "input"
+		}
+	}
+	return "scalar"
+}
+
+// Generate a constant describing each of the given types and its fields at runtime, e.g.
+// `export const UserMetadata = { name: 'User', kind: 'object', fields: { ... } } as const`.
+// Fields are keyed by their GraphQL name and hold their GraphQL type, the name and kind of the named type,
+// and whether they are nullable and lists, for table builders and selection sets built without introspection.
+func (g *Generator) writeTypeMetadata(file *bufio.Writer, defs []*ast.Definition) {
+	for _, def := range defs {
+		file.WriteString(fmt.Sprintf("export const %s = {\n  name: %s,\n  kind: %s,\n  fields: {\n", g.metadataName(def.Name), quoteString(def.Name), quoteString(g.metadataKind(def.Name))))
+		fields := def.Fields
+		if def.Kind != ast.InputObject {
+			fields = g.objectFields(def)
+		}
+		for _, field := range fields {
+			if g.isUploadOutput(def.Name, field) {
+				continue
+			}
+			name := field.Type.Name()
+			file.WriteString(fmt.Sprintf("    %s: { type: %s, typeName: %s, kind: %s, nullable: %t, list: %t },\n",
+				g.propertyKey(field.Name), quoteString(field.Type.String()), quoteString(name), quoteString(g.metadataKind(name)), !field.Type.NonNull, field.Type.Elem != nil))
+		}
+		file.WriteString("  },\n} as const;\n\n")
+	}
+}
+
+// Return the selected object types and interfaces, or input types, whose metadata is emitted
+func (g *Generator) metadataDefinitions(selected map[string]bool, inputs bool) []*ast.Definition {
+	var defs []*ast.Definition
+	if inputs {
+		for _, name := range orderedKeys(g, g.schema.Inputs, "") {
+			if selected == nil || selected[name] {
+				defs = append(defs, g.schema.Inputs[name])
+			}
+		}
+		return defs
+	}
+	for _, name := range orderedKeys(g, g.schema.Types, "") {
+		if selected == nil || selected[name] {
+			defs = append(defs, g.schema.Types[name].Definition)
+		}
+	}
+	return defs
+}
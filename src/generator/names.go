@@ -0,0 +1,305 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Names usable as TypeScript identifiers and unquoted property keys
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// JavaScript reserved words, and property names that break in classes or namespaces
+var reservedWords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"implements": true, "interface": true, "let": true, "package": true, "private": true,
+	"protected": true, "public": true, "static": true, "yield": true, "await": true,
+	"constructor": true, "prototype": true, "__proto__": true,
+}
+
+// Return the key of a property in a type or object literal, quoted when it is a reserved word
+// or not an identifier, such as a @tsName with dashes or a leading digit
+func (g *Generator) propertyKey(name string) string {
+	if !identifier.MatchString(name) || (reservedWords[name] && g.opts.ReservedFields != ReservedKeep) {
+		return quoteString(name)
+	}
+	return name
+}
+
+// Escapes of the characters that end or break a single-quoted string literal
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\u2028", `\u2028`, "\u2029", `\u2029`)
+
+// Return a single-quoted JavaScript string literal
+func quoteString(s string) string {
+	return "'" + stringEscaper.Replace(s) + "'"
+}
+
+// Check that the generated type names are identifiers, are unique after renames,
+// and don't collide with the generated helpers
+func (g *Generator) checkTypeNames() error {
+	helpers := g.helperNames()
+	emitted := make(map[string]string) // TypeScript name -> GraphQL name
+	var errs []string
+	check := func(name string) {
+		tsName := g.tsName(name)
+		if !identifier.MatchString(tsName) {
+			errs = append(errs, fmt.Sprintf("%s is emitted as %q, which is not a valid TypeScript identifier", name, tsName))
+			return
+		}
+		if helper, found := helpers[tsName]; found {
+			errs = append(errs, fmt.Sprintf("%s collides with the generated %s; rename it, e.g. with -rename %s=%s",
+				name, helper, name, g.suggestName(tsName, helpers, emitted)))
+		} else if other, found := emitted[tsName]; found {
+			errs = append(errs, fmt.Sprintf("%s and %s are both emitted as %s; rename one, e.g. with -rename %s=%s",
+				other, name, tsName, name, g.suggestName(tsName, helpers, emitted)))
+		}
+		emitted[tsName] = name
+	}
+
+	for i, fields := range g.schema.roots() {
+		if len(fields) > 0 {
+			check(rootNames[i])
+		}
+	}
+	for _, defs := range []map[string]*ast.Definition{g.definitionsOfKind(ast.Object), g.definitionsOfKind(ast.Interface), g.schema.Enums, g.schema.Inputs, g.schema.Unions, g.schema.Scalars} {
+		for _, name := range sortedKeys(defs) {
+			if !g.isExcluded(name) {
+				check(name)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid type names: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Suggest a free name for a type whose generated name is taken
+func (g *Generator) suggestName(tsName string, helpers map[string]string, emitted map[string]string) string {
+	for i := 1; ; i++ {
+		suggestion := tsName + "Type"
+		if i > 1 {
+			suggestion += strconv.Itoa(i)
+		}
+		_, isHelper := helpers[suggestion]
+		_, isEmitted := emitted[suggestion]
+		if !isHelper && !isEmitted && g.schema.definition(suggestion) == nil {
+			return suggestion
+		}
+	}
+}
+
+// HelperNames returns the names of the types and constants generated next to the schema types by any option
+// or preset, such as Nullable, PartialCreateUserInput or QueryUserResult, with what generates them.
+// Tools reading generated code, such as the reverse mode of the CLI, use it to leave them out.
+func (g *Generator) HelperNames() map[string]string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	configured := g.opts
+	defer func() { g.opts = configured }()
+
+	helpers := make(map[string]string)
+	for _, preset := range []string{"", PresetCodegen} {
+		all := configured
+		all.Preset, all.InlineNull, all.InputMaybe, all.ScalarsRecord = preset, false, true, true
+		all.TypeNameUnion, all.TypeMap, all.OperationNames, all.ResultAliases = true, true, true, true
+		all.Dates, all.Federation, all.EnumValues, all.TypeMetadata, all.EnumHelpers = true, true, true, true, true
+		all.InputDefaults, all.InputCoercion, all.Zod, all.PartialInputs = true, true, true, true
+		all.ImplementerUnions, all.ArgumentDefaults, all.Exhaustive, all.Pagination = true, true, true, true
+		all.ResponseTypes, all.Resolvers, all.Vue = true, true, VueUrql
+		g.opts = all
+		for name, helper := range g.helperNames() {
+			helpers[name] = helper
+		}
+	}
+	// Result aliases take the name of their root type when a schema type has theirs, which may be
+	// the alias itself in a schema read back from generated code, so both names are helpers
+	for _, op := range g.operations() {
+		name := operationName(op.field) + "Result"
+		helpers[name] = op.root + "." + op.field.Name + " result type"
+		helpers[op.root+name] = op.root + "." + op.field.Name + " result type"
+	}
+	return helpers
+}
+
+// Return the names of the types and constants generated next to the schema types, with what generates them
+func (g *Generator) helperNames() map[string]string {
+	helpers := make(map[string]string)
+	if g.opts.ScalarsRecord || g.codegen() {
+		helpers["Scalars"] = "Scalars interface"
+	}
+	if g.codegen() {
+		for _, name := range codegenHelperNames {
+			helpers[name] = name + " helper"
+		}
+		for i, fields := range g.schema.roots() {
+			for _, field := range fields {
+				if len(field.Arguments) > 0 {
+					helpers[argsTypeName(rootNames[i], field)] = rootNames[i] + "." + field.Name + " arguments type"
+				}
+			}
+		}
+		for name, typeInfo := range g.schema.Types {
+			for _, field := range typeInfo.Definition.Fields {
+				if len(field.Arguments) > 0 {
+					helpers[argsTypeName(g.tsName(name), field)] = name + "." + field.Name + " arguments type"
+				}
+			}
+		}
+	} else if !g.opts.InlineNull {
+		helpers["Nullable"] = "Nullable helper"
+	}
+	if g.opts.InputMaybe && !g.opts.InlineNull {
+		helpers["InputMaybe"] = "InputMaybe helper"
+	}
+	if g.opts.TypeNameUnion {
+		helpers["TypeName"] = "TypeName union"
+	}
+	if g.opts.TypeMap {
+		helpers["TypeMap"] = "TypeMap interface"
+	}
+	if g.opts.OperationNames {
+		helpers["OperationNames"] = "OperationNames constant"
+		helpers["OperationName"] = "OperationName type"
+	}
+	if g.opts.ResultAliases {
+		for _, alias := range g.resultAliases() {
+			helpers[alias.name] = alias.op.root + "." + alias.op.field.Name + " result type"
+		}
+	}
+	if g.opts.Dates {
+		helpers["DateFields"] = "DateFields constant"
+	}
+	if g.opts.Federation {
+		helpers["_Any"] = "_Any type"
+		helpers["_Entity"] = "_Entity union"
+		for name, typeInfo := range g.schema.Types {
+			if typeInfo.Definition.Directives.ForName("key") != nil {
+				helpers[g.tsName(name)+"KeyFields"] = name + " key fields type"
+			}
+		}
+	}
+	if g.opts.EnumValues {
+		for name := range g.schema.Enums {
+			helpers[g.tsName(name)+"Values"] = name + " values constant"
+		}
+	}
+	if g.opts.TypeMetadata {
+		for name := range g.schema.Types {
+			helpers[g.metadataName(name)] = name + " metadata constant"
+		}
+		for name := range g.schema.Inputs {
+			helpers[g.metadataName(name)] = name + " metadata constant"
+		}
+	}
+	if g.opts.EnumHelpers {
+		for name := range g.schema.Enums {
+			helpers["is"+g.tsName(name)] = name + " type guard"
+			helpers["parse"+g.tsName(name)] = name + " parse function"
+		}
+	}
+	if g.opts.InputDefaults {
+		for name := range g.schema.Inputs {
+			helpers[g.inputDefaultsName(name)] = name + " default value factory"
+		}
+	}
+	if g.opts.InputCoercion {
+		helpers["InputCoercionError"] = "InputCoercionError class"
+		for name := range g.schema.Inputs {
+			helpers[g.coercionName(name)] = name + " coercion function"
+		}
+	}
+	if g.opts.Zod {
+		for name := range g.schema.Inputs {
+			helpers[g.zodSchemaName(name)] = name + " Zod schema"
+		}
+	}
+	if g.opts.ImplementerUnions {
+		for name, def := range g.definitionsOfKind(ast.Interface) {
+			helpers[g.implementerUnionName(name)] = def.Name + " implementers union"
+		}
+	}
+	if g.opts.ArgumentDefaults {
+		for _, defaults := range g.argumentDefaults(nil) {
+			helpers[defaults.name] = defaults.field.Name + " argument defaults constant"
+		}
+	}
+	if g.opts.Exhaustive {
+		helpers["assertNever"] = "assertNever helper"
+		for _, defs := range []map[string]*ast.Definition{g.schema.Enums, g.schema.Unions} {
+			for name := range defs {
+				helpers[g.matchName(name)] = name + " match function"
+			}
+		}
+	}
+	if g.opts.PartialInputs {
+		helpers["DeepPartial"] = "DeepPartial helper"
+		for name := range g.schema.Inputs {
+			helpers["Partial"+g.tsName(name)] = name + " partial type"
+		}
+	}
+	if g.opts.Pagination {
+		for _, name := range g.paginationHelperNames() {
+			helpers[name] = name + " pagination helper"
+		}
+	}
+	if g.opts.ResponseTypes {
+		for _, name := range responseTypeNames {
+			helpers[name] = name + " response type"
+		}
+	}
+	if g.opts.Vue != "" {
+		for _, op := range g.operations() {
+			name := vueOperationName(op)
+			if g.opts.ResponseTypes {
+				helpers[name+"Response"] = op.root + "." + op.field.Name + " response type"
+			}
+			helpers[name] = op.root + "." + op.field.Name + " result type"
+			helpers[name+"Variables"] = op.root + "." + op.field.Name + " variables type"
+			helpers[name+"Document"] = op.root + "." + op.field.Name + " document"
+		}
+	}
+	if g.opts.Resolvers {
+		helpers["Resolver"] = "Resolver type"
+		helpers["SubscriptionResolver"] = "SubscriptionResolver type"
+		helpers["GraphQLResolveInfo"] = "GraphQLResolveInfo import"
+		for i, fields := range g.schema.roots() {
+			if len(fields) > 0 {
+				helpers[g.tsName(rootNames[i])+"Resolvers"] = rootNames[i] + " resolvers interface"
+			}
+			for _, field := range fields {
+				if len(field.Arguments) > 0 {
+					helpers[argsTypeName(rootNames[i], field)] = rootNames[i] + "." + field.Name + " arguments interface"
+				}
+			}
+		}
+	}
+	return helpers
+}
+
+// Return the property name of a field: its @tsName, the renamed reserved word, or the quoted field name
+func (g *Generator) propertyName(field *ast.FieldDefinition) string {
+	if tsName, found := tsNameDirective(field.Directives); found {
+		return g.propertyKey(tsName)
+	}
+	if g.renamesReserved(field) {
+		return field.Name + "_"
+	}
+	return g.propertyKey(field.Name)
+}
+
+// Check whether a field is a reserved word renamed with a trailing underscore
+func (g *Generator) renamesReserved(field *ast.FieldDefinition) bool {
+	if _, found := tsNameDirective(field.Directives); found {
+		return false
+	}
+	return g.opts.ReservedFields == ReservedRename && reservedWords[field.Name]
+}
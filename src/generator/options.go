@@ -0,0 +1,699 @@
+package generator
+
+import (
+	"io"
+	"time"
+)
+
+// Emit targets
+const (
+	TargetTypescript = "typescript"
+	TargetFlow       = "flow"
+	TargetGo         = "go"
+	TargetSDL        = "sdl"      // Merged GraphQL schema
+	TargetDocs       = "docs"     // Markdown reference
+	TargetHTML       = "html"     // Searchable HTML reference
+	TargetDOT        = "dot"      // Graphviz graph of type references
+	TargetMermaid    = "mermaid"  // Mermaid flowchart of type references
+	TargetFixtures   = "fixtures" // Example JSON payloads
+)
+
+// Handling of fields named after reserved words such as delete or new
+const (
+	ReservedQuote  = "quote"  // Quoted property names, such as 'delete' (default)
+	ReservedRename = "rename" // Trailing underscore, such as delete_, with a comment naming the GraphQL field
+	ReservedKeep   = "keep"   // Unchanged names
+)
+
+// Syntax of list types
+const (
+	ArrayGeneric = "generic" // Array<T> (default)
+	ArrayType    = "array"   // T[], with parentheses around unions
+)
+
+// Strategies resolving the conflicting definitions of a type declared in several files
+const (
+	ConflictError       = "error"        // Fail generation (default)
+	ConflictFirstWins   = "first-wins"   // Keep the first definition, like SkipChecks
+	ConflictLastWins    = "last-wins"    // Keep the last definition
+	ConflictMergeFields = "merge-fields" // Merge the fields, enum values and union members, failing when a field has two types
+)
+
+// Presets of naming and shape conventions
+const (
+	PresetCodegen = "codegen" // The typescript plugin of graphql-codegen
+)
+
+// Linters whose rules can be disabled in the header of generated files
+const (
+	SuppressTslint = "tslint"
+	SuppressEslint = "eslint"
+	SuppressBiome  = "biome"
+)
+
+// Mappings of the JSON scalar
+const (
+	JSONUnknown = "unknown"
+	JSONAny     = "any"
+	JSONRecord  = "record" // Record<string, unknown>
+)
+
+// Options configures a generator. Every CLI flag has a matching field.
+type Options struct {
+	// Skip type mismatch checks when the same type is declared in several files
+	SkipChecks bool
+	// Types whose conflicting definitions are ignored, keeping the first one, while the others are checked
+	SkipChecksTypes []string
+	// Strategy resolving the conflicting definitions of all types: ConflictError (default), ConflictFirstWins,
+	// ConflictLastWins or ConflictMergeFields
+	Conflicts string
+	// Type name -> conflict strategy, taking precedence over Conflicts
+	TypeConflicts map[string]string
+	// Destination for debug logs, nil disables them
+	DebugLog io.Writer
+	// GraphQL scalar name -> TypeScript type, taking precedence over the built-in mappings
+	Scalars map[string]string
+	// Fail generation when a custom scalar has no TypeScript mapping
+	StrictScalars bool
+	// Lint rule -> severity, overriding the default severities
+	LintRules map[string]Severity
+	// Type name prefixes reported by the forbidden-prefixes lint rule
+	ForbiddenPrefixes []string
+	// Only emit types reachable from the Query, Mutation and Subscription fields
+	Prune bool
+	// Allow-list of type names and Root.field patterns (globs) to emit, with their dependencies
+	Only []string
+	// Deny-list of type names and Root.field patterns (globs) to omit
+	Exclude []string
+	// GraphQL type name -> emitted TypeScript name, applied to declarations and references
+	Renames map[string]string
+	// Type.field -> TypeScript type replacing the generated one. Use module#Type to import the type.
+	FieldTypes map[string]string
+	// Output language, TargetTypescript by default
+	Target string
+	// Package name of the Go target output, "generated" by default
+	GoPackage string
+	// Enums emitted with numeric values instead of mirrored strings, in addition to those marked @tsNumeric
+	NumericEnums []string
+	// Enums emitted as unions of string literals instead of TypeScript enums, in addition to those marked @tsUnion
+	UnionEnums []string
+	// Emit a TypeName union of all object type names
+	TypeNameUnion bool
+	// Emit a TypeMap interface from type name to generated interface
+	TypeMap bool
+	// Emit a const array of the values of each enum
+	EnumValues bool
+	// Emit a runtime metadata constant of the fields of each object type, interface and input type
+	TypeMetadata bool
+	// Emit an is<Enum> type guard and a parse<Enum> function of each enum
+	EnumHelpers bool
+	// Emit a const object of the root field names of Query, Mutation and Subscription
+	OperationNames bool
+	// Emit an alias of the result type of each Query and Mutation field, e.g. GetProjectsResult
+	ResultAliases bool
+	// Emit resolver signature types for the root operation types
+	Resolvers bool
+	// Emit the Apollo Federation _Entity, _Any and entity key types
+	Federation bool
+	// Name of the directive hiding types and fields from the output, "internal" if empty
+	InternalDirective string
+	// Keep only the types and fields with one of these @tag names, and the types they reference
+	Tags []string
+	// Map DateTime to Date and emit parseDates/serializeDates helpers
+	Dates bool
+	// TypeScript type of the BigInt and Long scalars, "bigint" if empty
+	BigInt string
+	// Mapping of the JSON scalar: JSONUnknown (default), JSONAny or JSONRecord
+	JSON string
+	// Mapping of the JSONObject scalar: JSONRecord (default), JSONUnknown or JSONAny
+	JSONObject string
+	// Linters disabled by comments in the file header, nil for the default of the target. Empty disables none.
+	LintSuppressions []string
+	// Extension of split files: .ts (default), .mts or .cts
+	Extension string
+	// Add JavaScript extensions to relative import specifiers, as required by ESM and NodeNext resolution
+	ESM bool
+	// Header metadata. All of it is off by default so that the output is reproducible.
+	HeaderSchemaHash bool
+	HeaderVersion    bool
+	// Generation time shown in the header, omitted if zero
+	HeaderTimestamp time.Time
+	// Copy the fields of implemented interfaces missing from object types
+	InheritInterfaceFields bool
+	// Emit generic types of the pagination conventions found in the schema
+	Pagination bool
+	// Pagination role -> field or argument name, overriding the defaults such as edges or offset
+	PaginationFields map[string]string
+	// Emit the GraphQLError and GraphQLResponse types of execution results
+	ResponseTypes bool
+	// Write the declarations of each root type to queries.ts, mutations.ts and subscriptions.ts in split output
+	SplitRoots bool
+	// Naming and shape conventions of another generator: PresetCodegen, or empty for the native ones
+	Preset string
+	// Emit types compiling under strict, exactOptionalPropertyTypes and noUncheckedIndexedAccess
+	StrictTS bool
+	// Write nullable types as T | null instead of the Nullable<T> alias
+	InlineNull bool
+	// Syntax of list types: ArrayGeneric (default) or ArrayType
+	ArraySyntax string
+	// Handling of fields named after reserved words, ReservedQuote by default
+	ReservedFields string
+	// Emit definitions and root fields in schema declaration order instead of sorted by name
+	DeclarationOrder bool
+	// Emit a default<Input>() factory for each input type, filled from default values and zero values
+	InputDefaults bool
+	// Emit a DeepPartial helper and a Partial<Input> alias of each input type
+	PartialInputs bool
+	// Wrap nullable input fields and arguments in InputMaybe<T> = T | null | undefined instead of Nullable
+	InputMaybe bool
+	// Emit the exported Scalars interface listing the TypeScript type of every built-in and mapped scalar
+	ScalarsRecord bool
+	// Vue client of the composables emitted for each Query and Mutation field: VueUrql or VueVillus, none if empty
+	Vue string
+	// Levels of object fields selected by the documents of the composables, DefaultOperationDepth if 0
+	VueDepth int
+	// Emit the descriptions of the schema, or the # comments above elements without one, as JSDoc comments
+	JSDoc bool
+	// Emit a union of the object types implementing each interface
+	ImplementerUnions bool
+	// Emit input types as classes whose constructor takes a partial value and applies the schema defaults
+	InputClasses bool
+	// Emit a constant of the argument default values of each field with defaulted arguments
+	ArgumentDefaults bool
+	// Emit assertNever and a match function of each enum and union requiring a case for every value or member
+	Exhaustive bool
+	// Emit a coerce<Input>() function converting untyped data, such as form data, into each input type
+	InputCoercion bool
+	// Emit a Zod schema of each input type, refined with the arguments of the @constraint directives of its fields
+	Zod bool
+	// Parsed schema files shared with other generators, a cache of the generator's own if nil
+	ParseCache *ParseCache
+}
+
+// Option changes a single setting of the generator
+type Option func(*Options)
+
+// WithOptions replaces all settings with the given Options struct
+func WithOptions(opts Options) Option {
+	return func(o *Options) {
+		*o = opts
+	}
+}
+
+// WithSkipChecks disables conflict checks between duplicate definitions
+func WithSkipChecks(skip bool) Option {
+	return func(o *Options) {
+		o.SkipChecks = skip
+	}
+}
+
+// WithSkipChecksTypes ignores the conflicting definitions of the given types, such as shared wrapper types
+// copied into every service schema, keeping their first definition. Other types are still checked.
+func WithSkipChecksTypes(names ...string) Option {
+	return func(o *Options) {
+		o.SkipChecksTypes = names
+	}
+}
+
+// WithConflicts sets the strategy resolving the conflicting definitions of a type declared in several files,
+// for the types without their own strategy: ConflictError, ConflictFirstWins, ConflictLastWins or ConflictMergeFields
+func WithConflicts(strategy string) Option {
+	return func(o *Options) {
+		o.Conflicts = strategy
+	}
+}
+
+// WithTypeConflict sets the conflict strategy of one type, or of the fields of a root type such as Query
+func WithTypeConflict(name string, strategy string) Option {
+	return func(o *Options) {
+		if o.TypeConflicts == nil {
+			o.TypeConflicts = make(map[string]string)
+		}
+		o.TypeConflicts[name] = strategy
+	}
+}
+
+// WithDebugLog writes debug logs to w
+func WithDebugLog(w io.Writer) Option {
+	return func(o *Options) {
+		o.DebugLog = w
+	}
+}
+
+// WithScalar maps a GraphQL scalar to a TypeScript type. The name may be a glob pattern such as * or JSON*,
+// mapping the custom scalars without a mapping of their own, e.g. WithScalar("*", "unknown") to forbid any.
+func WithScalar(name string, tsType string) Option {
+	return func(o *Options) {
+		if o.Scalars == nil {
+			o.Scalars = make(map[string]string)
+		}
+		o.Scalars[name] = tsType
+	}
+}
+
+// WithStrictScalars makes Emit fail when a custom scalar has no TypeScript mapping
+func WithStrictScalars(strict bool) Option {
+	return func(o *Options) {
+		o.StrictScalars = strict
+	}
+}
+
+// WithLintRule sets the severity of a lint rule
+func WithLintRule(rule string, severity Severity) Option {
+	return func(o *Options) {
+		if o.LintRules == nil {
+			o.LintRules = make(map[string]Severity)
+		}
+		o.LintRules[rule] = severity
+	}
+}
+
+// WithForbiddenPrefixes sets the type name prefixes reported by the forbidden-prefixes lint rule
+func WithForbiddenPrefixes(prefixes ...string) Option {
+	return func(o *Options) {
+		o.ForbiddenPrefixes = prefixes
+	}
+}
+
+// WithPrune only emits types reachable from the root operation fields
+func WithPrune(prune bool) Option {
+	return func(o *Options) {
+		o.Prune = prune
+	}
+}
+
+// WithOnly only emits the types and root fields matching the patterns, plus the types they depend on.
+// Patterns are type names (User, *Input) or root fields (Query.*, Mutation.createUser).
+func WithOnly(patterns ...string) Option {
+	return func(o *Options) {
+		o.Only = patterns
+	}
+}
+
+// WithExclude omits the types and root fields matching the patterns.
+// Emit fails if a remaining field references an excluded type, unless checks are skipped.
+func WithExclude(patterns ...string) Option {
+	return func(o *Options) {
+		o.Exclude = patterns
+	}
+}
+
+// WithRename emits a GraphQL type under a different TypeScript name
+func WithRename(name string, tsName string) Option {
+	return func(o *Options) {
+		if o.Renames == nil {
+			o.Renames = make(map[string]string)
+		}
+		o.Renames[name] = tsName
+	}
+}
+
+// WithFieldType overrides the TypeScript type of a Type.field.
+// A tsType of the form ./module#Name is imported from the module.
+func WithFieldType(field string, tsType string) Option {
+	return func(o *Options) {
+		if o.FieldTypes == nil {
+			o.FieldTypes = make(map[string]string)
+		}
+		o.FieldTypes[field] = tsType
+	}
+}
+
+// WithTarget selects the output language
+func WithTarget(target string) Option {
+	return func(o *Options) {
+		o.Target = target
+	}
+}
+
+// WithGoPackage sets the package name of the Go target output
+func WithGoPackage(name string) Option {
+	return func(o *Options) {
+		o.GoPackage = name
+	}
+}
+
+// WithNumericEnums emits the named enums with numeric values instead of mirrored strings
+func WithNumericEnums(names ...string) Option {
+	return func(o *Options) {
+		o.NumericEnums = names
+	}
+}
+
+// WithUnionEnums emits the named enums as unions of string literals, e.g. `export type CountryCode = 'FR' | 'US'`,
+// instead of TypeScript enums
+func WithUnionEnums(names ...string) Option {
+	return func(o *Options) {
+		o.UnionEnums = names
+	}
+}
+
+// WithTypeNameUnion emits `export type TypeName = 'User' | 'Project' | ...`
+func WithTypeNameUnion(enabled bool) Option {
+	return func(o *Options) {
+		o.TypeNameUnion = enabled
+	}
+}
+
+// WithTypeMap emits `export interface TypeMap { User: User; ... }`
+func WithTypeMap(enabled bool) Option {
+	return func(o *Options) {
+		o.TypeMap = enabled
+	}
+}
+
+// WithEnumValues emits `export const UserRoleValues = ['ADMIN', 'MEMBER'] as const` after each enum
+func WithEnumValues(enabled bool) Option {
+	return func(o *Options) {
+		o.EnumValues = enabled
+	}
+}
+
+// WithResultAliases emits `export type GetProjectsResult = Array<Project>` for each Query and Mutation field,
+// prefixed with the root type when a query and a mutation share a name, e.g. MutationUserResult
+func WithResultAliases(enabled bool) Option {
+	return func(o *Options) {
+		o.ResultAliases = enabled
+	}
+}
+
+// WithTypeMetadata emits `export const UserMetadata = { name: 'User', kind: 'object', fields: { ... } } as const`
+// for each object type, interface and input type, describing the GraphQL type, nullability and kind of each field
+func WithTypeMetadata(enabled bool) Option {
+	return func(o *Options) {
+		o.TypeMetadata = enabled
+	}
+}
+
+// WithEnumHelpers emits `isUserRole(value: unknown): value is UserRole` and `parseUserRole(value: string): UserRole | undefined`
+// after each enum, converting the values of URLs, local storage or external APIs to the enum type
+func WithEnumHelpers(enabled bool) Option {
+	return func(o *Options) {
+		o.EnumHelpers = enabled
+	}
+}
+
+// WithOperationNames emits `export const OperationNames = { users: 'users', ... } as const`
+func WithOperationNames(enabled bool) Option {
+	return func(o *Options) {
+		o.OperationNames = enabled
+	}
+}
+
+// WithResolvers emits `QueryResolvers<TContext>`, `MutationResolvers<TContext>` and `SubscriptionResolvers<TContext>`
+func WithResolvers(enabled bool) Option {
+	return func(o *Options) {
+		o.Resolvers = enabled
+	}
+}
+
+// WithFederation emits the `_Entity` union, `_Any` and a `UserKeyFields` type for each @key entity
+func WithFederation(enabled bool) Option {
+	return func(o *Options) {
+		o.Federation = enabled
+	}
+}
+
+// WithInternalDirective sets the directive that hides types and fields from the output, @internal by default
+func WithInternalDirective(name string) Option {
+	return func(o *Options) {
+		o.InternalDirective = name
+	}
+}
+
+// WithTags keeps only the elements annotated with one of the @tag names, like a federation contract.
+// Types and fields are kept when they or their parent type carry a selected tag; unreachable types are pruned.
+func WithTags(tags ...string) Option {
+	return func(o *Options) {
+		o.Tags = tags
+	}
+}
+
+// WithDates maps DateTime to Date and emits helpers converting the DateTime strings of
+// responses to Date objects and back for inputs
+func WithDates(enabled bool) Option {
+	return func(o *Options) {
+		o.Dates = enabled
+	}
+}
+
+// WithBigInt sets the TypeScript type of the BigInt and Long scalars, such as "string" for clients without bigint support
+func WithBigInt(tsType string) Option {
+	return func(o *Options) {
+		o.BigInt = tsType
+	}
+}
+
+// WithJSON sets the mapping of the JSON scalar: JSONUnknown, JSONAny or JSONRecord
+func WithJSON(mode string) Option {
+	return func(o *Options) {
+		o.JSON = mode
+	}
+}
+
+// WithJSONObject sets the mapping of the JSONObject scalar: JSONRecord, JSONUnknown or JSONAny
+func WithJSONObject(mode string) Option {
+	return func(o *Options) {
+		o.JSONObject = mode
+	}
+}
+
+// WithLintSuppressions sets the linters disabled in the header of generated files: SuppressTslint,
+// SuppressEslint or SuppressBiome. Without arguments the header disables no linter.
+func WithLintSuppressions(linters ...string) Option {
+	return func(o *Options) {
+		o.LintSuppressions = append([]string{}, linters...)
+	}
+}
+
+// WithExtension sets the extension of split files: .ts, .mts or .cts
+func WithExtension(extension string) Option {
+	return func(o *Options) {
+		o.Extension = extension
+	}
+}
+
+// WithESM adds the JavaScript extension to relative import specifiers, such as './enums.js'
+func WithESM(enabled bool) Option {
+	return func(o *Options) {
+		o.ESM = enabled
+	}
+}
+
+// WithHeaderMetadata shows the hash of the schema sources and the generator version in the header of generated files
+func WithHeaderMetadata(schemaHash bool, version bool) Option {
+	return func(o *Options) {
+		o.HeaderSchemaHash = schemaHash
+		o.HeaderVersion = version
+	}
+}
+
+// WithHeaderTimestamp shows the generation time in the header of generated files. The output is then
+// no longer reproducible, unless the time is fixed such as from SOURCE_DATE_EPOCH.
+func WithHeaderTimestamp(t time.Time) Option {
+	return func(o *Options) {
+		o.HeaderTimestamp = t
+	}
+}
+
+// WithInheritInterfaceFields adds the fields of implemented interfaces that an object type leaves out,
+// such as fields hidden from the object only, so that its output matches the interface
+func WithInheritInterfaceFields(enabled bool) Option {
+	return func(o *Options) {
+		o.InheritInterfaceFields = enabled
+	}
+}
+
+// WithReservedFields sets how fields named after reserved words are emitted: ReservedQuote, ReservedRename or ReservedKeep
+func WithReservedFields(mode string) Option {
+	return func(o *Options) {
+		o.ReservedFields = mode
+	}
+}
+
+// WithPagination emits generic types of the pagination conventions found in the schema: PageInfo, Edge<T>,
+// Paginated<T>, NodeOf<C> and CursorPaginationVariables for Relay connections and first/after arguments,
+// and OffsetPaginationVariables for offset/limit arguments
+func WithPagination(enabled bool) Option {
+	return func(o *Options) {
+		o.Pagination = enabled
+	}
+}
+
+// WithPaginationField sets the name of the field or argument of a pagination role: edges, node, cursor,
+// pageInfo, first, after, last, before, offset or limit
+func WithPaginationField(role string, name string) Option {
+	return func(o *Options) {
+		if o.PaginationFields == nil {
+			o.PaginationFields = make(map[string]string)
+		}
+		o.PaginationFields[role] = name
+	}
+}
+
+// WithResponseTypes emits the GraphQLError and GraphQLResponse<T> types of execution results,
+// and a response type of each Vue composable, such as ProjectsQueryResponse
+func WithResponseTypes(enabled bool) Option {
+	return func(o *Options) {
+		o.ResponseTypes = enabled
+	}
+}
+
+// WithSplitRoots writes the declarations of each root type (its interface, resolvers and argument defaults)
+// to queries.ts, mutations.ts and subscriptions.ts in split output, apart from the data model
+func WithSplitRoots(enabled bool) Option {
+	return func(o *Options) {
+		o.SplitRoots = enabled
+	}
+}
+
+// WithPreset follows the naming and shape conventions of another generator, so that the code consuming its output
+// compiles unchanged. PresetCodegen matches the typescript plugin of graphql-codegen: the Maybe, InputMaybe,
+// Exact, MakeOptional and MakeMaybe helpers, the Scalars record of input and output types, type aliases
+// with an optional __typename, PascalCase enum members, union types and the arguments types of every field.
+func WithPreset(preset string) Option {
+	return func(o *Options) {
+		o.Preset = preset
+	}
+}
+
+// WithStrictTS emits types for projects compiling with strict, exactOptionalPropertyTypes and
+// noUncheckedIndexedAccess: nullable properties and the properties of DeepPartial accept an explicit undefined
+func WithStrictTS(enabled bool) Option {
+	return func(o *Options) {
+		o.StrictTS = enabled
+	}
+}
+
+// WithInlineNull writes nullable types as `string | null` instead of `Nullable<string>`, without declaring
+// the Nullable and InputMaybe aliases
+func WithInlineNull(enabled bool) Option {
+	return func(o *Options) {
+		o.InlineNull = enabled
+	}
+}
+
+// WithArraySyntax sets the syntax of list types: ArrayGeneric for Array<T>, or ArrayType for T[],
+// as required by the @typescript-eslint/array-type rule
+func WithArraySyntax(syntax string) Option {
+	return func(o *Options) {
+		o.ArraySyntax = syntax
+	}
+}
+
+// WithDeclarationOrder emits definitions and root fields in the order of the schema files and of their
+// declarations within each file, instead of sorted by name
+func WithDeclarationOrder(enabled bool) Option {
+	return func(o *Options) {
+		o.DeclarationOrder = enabled
+	}
+}
+
+// WithInputDefaults emits `export function defaultCreateUserInput(): CreateUserInput` after the input types,
+// returning the default values of the fields declared in the schema and zero values for the other required fields
+func WithInputDefaults(enabled bool) Option {
+	return func(o *Options) {
+		o.InputDefaults = enabled
+	}
+}
+
+// WithPartialInputs emits a DeepPartial<T> helper and `export type PartialCreateUserInput = DeepPartial<CreateUserInput>`
+// for each input type, for the draft values of forms
+func WithPartialInputs(enabled bool) Option {
+	return func(o *Options) {
+		o.PartialInputs = enabled
+	}
+}
+
+// WithInputMaybe wraps nullable input fields and arguments in InputMaybe<T> = T | null | undefined,
+// since an omitted input value differs from an explicit null, while outputs keep Nullable<T> = T | null
+func WithInputMaybe(enabled bool) Option {
+	return func(o *Options) {
+		o.InputMaybe = enabled
+	}
+}
+
+// WithScalarsRecord emits the exported Scalars interface, listing the TypeScript type of every built-in
+// and mapped custom scalar, and types scalar fields as Scalars['Name']. The codegen preset always emits
+// its own Scalars record.
+func WithScalarsRecord(enabled bool) Option {
+	return func(o *Options) {
+		o.ScalarsRecord = enabled
+	}
+}
+
+// WithVue emits typed Vue 3 composables, such as useProjectsQuery, for the VueUrql or VueVillus client.
+// Their documents select depth levels of object fields, DefaultOperationDepth if 0.
+func WithVue(client string, depth int) Option {
+	return func(o *Options) {
+		o.Vue = client
+		o.VueDepth = depth
+	}
+}
+
+// WithJSDoc emits the descriptions of types, fields, arguments and enum values as JSDoc comments, with a
+// @deprecated tag for deprecated elements. Elements without description are documented by the # comment
+// lines right above them, as in schemas written before block string descriptions.
+func WithJSDoc(enabled bool) Option {
+	return func(o *Options) {
+		o.JSDoc = enabled
+	}
+}
+
+// WithImplementerUnions emits `export type NodeTypes = User | Project` for each interface
+func WithImplementerUnions(enabled bool) Option {
+	return func(o *Options) {
+		o.ImplementerUnions = enabled
+	}
+}
+
+// WithInputClasses emits input types as classes instead of interfaces, with the schema default values as
+// property initializers and a constructor taking a partial value: `new CreateUserInput({ name: 'Ada' })`
+func WithInputClasses(enabled bool) Option {
+	return func(o *Options) {
+		o.InputClasses = enabled
+	}
+}
+
+// WithArgumentDefaults emits `export const GET_PROJECTS_DEFAULTS = { limit: 20 } as const` for each field
+// with defaulted arguments, so that clients reuse the server defaults instead of hardcoding them
+func WithArgumentDefaults(enabled bool) Option {
+	return func(o *Options) {
+		o.ArgumentDefaults = enabled
+	}
+}
+
+// WithExhaustive emits an assertNever helper and match functions, such as
+// `matchSearchResult(result, { User: (user) => ..., Project: (project) => ... })`, which stop compiling
+// when an enum value or union member is added to the schema
+func WithExhaustive(enabled bool) Option {
+	return func(o *Options) {
+		o.Exhaustive = enabled
+	}
+}
+
+// WithInputCoercion emits `export function coerceCreateUserInput(raw: unknown): CreateUserInput` after the input types,
+// dropping unknown keys, converting strings to numbers and booleans, and throwing an InputCoercionError naming
+// the path of the first value that cannot be converted
+func WithInputCoercion(enabled bool) Option {
+	return func(o *Options) {
+		o.InputCoercion = enabled
+	}
+}
+
+// WithZod emits `export const CreateUserInputSchema: z.ZodType<CreateUserInput>` after the input types, validating
+// values with Zod. The minLength, maxLength, pattern, format, min and max arguments of @constraint on input fields,
+// among others, become refinements such as .min(), .regex() and .email().
+func WithZod(enabled bool) Option {
+	return func(o *Options) {
+		o.Zod = enabled
+	}
+}
+
+// WithParseCache shares the parsed schema files with the other generators using the same cache,
+// so that generators built from the same files parse each of them once
+func WithParseCache(cache *ParseCache) Option {
+	return func(o *Options) {
+		o.ParseCache = cache
+	}
+}
@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Fields and arguments of the pagination conventions, by role, with their default names:
+// Relay connections with edges of nodes and a page info, cursor arguments, and offset arguments
+var defaultPaginationFields = map[string]string{
+	"edges":    "edges",
+	"node":     "node",
+	"cursor":   "cursor",
+	"pageInfo": "pageInfo",
+	"first":    "first",
+	"after":    "after",
+	"last":     "last",
+	"before":   "before",
+	"offset":   "offset",
+	"limit":    "limit",
+}
+
+// Return the name of the field or argument with a pagination role, as configured or by default
+func (g *Generator) paginationField(role string) string {
+	if name, found := g.opts.PaginationFields[role]; found {
+		return name
+	}
+	return defaultPaginationFields[role]
+}
+
+// Pagination conventions found in the schema
+type paginationConventions struct {
+	// Connection types, with a list of edges holding nodes and a page info, or fields taking first and after arguments
+	cursor bool
+	// Fields taking offset and limit arguments
+	offset bool
+}
+
+// Detect the pagination conventions used by the selected types and root fields
+func (g *Generator) paginationConventions(selected map[string]bool) paginationConventions {
+	var conventions paginationConventions
+	checkArguments := func(field *ast.FieldDefinition) {
+		has := func(role string) bool {
+			return field.Arguments.ForName(g.paginationField(role)) != nil
+		}
+		conventions.cursor = conventions.cursor || (has("first") && has("after"))
+		conventions.offset = conventions.offset || (has("offset") && has("limit"))
+	}
+
+	for i, fields := range g.schema.roots() {
+		for _, name := range g.selectedRootFields(rootNames[i], fields) {
+			checkArguments(fields[name])
+		}
+	}
+	for _, name := range g.objectTypeNames(selected) {
+		def := g.schema.Types[name].Definition
+		for _, field := range g.objectFields(def) {
+			checkArguments(field)
+		}
+		edges := def.Fields.ForName(g.paginationField("edges"))
+		if edges == nil || edges.Type.Elem == nil || def.Fields.ForName(g.paginationField("pageInfo")) == nil {
+			continue
+		}
+		if edge, found := g.schema.Types[edges.Type.Name()]; found && edge.Definition.Fields.ForName(g.paginationField("node")) != nil {
+			conventions.cursor = true
+		}
+	}
+	return conventions
+}
+
+// Return the names of the pagination helpers emitted for the schema
+func (g *Generator) paginationHelperNames() []string {
+	var names []string
+	conventions := g.paginationConventions(nil)
+	if conventions.cursor {
+		if _, found := g.schema.Types["PageInfo"]; !found {
+			names = append(names, "PageInfo")
+		}
+		names = append(names, "Edge", "Paginated", "NodeOf", "CursorPaginationVariables")
+	}
+	if conventions.offset {
+		names = append(names, "OffsetPaginationVariables")
+	}
+	return names
+}
+
+// Generate generic types of the pagination conventions found in the schema: PageInfo unless the schema
+// declares it, Edge<T> and Paginated<T> connections, NodeOf<C> extracting the node type of a connection,
+// and the variables of cursor and offset pagination
+func (g *Generator) writePaginationHelpers(file *bufio.Writer, selected map[string]bool) {
+	conventions := g.paginationConventions(selected)
+	key := func(role string) string {
+		return g.propertyKey(g.paginationField(role))
+	}
+
+	if conventions.cursor {
+		pageInfo := "PageInfo"
+		if _, found := g.schema.Types["PageInfo"]; found {
+			pageInfo = g.tsName("PageInfo")
+		} else {
+			file.WriteString("export interface PageInfo {\n  hasNextPage: boolean;\n  hasPreviousPage: boolean;\n")
+			file.WriteString(fmt.Sprintf("  startCursor?: %s;\n  endCursor?: %s;\n}\n\n", g.nullable("string"), g.nullable("string")))
+		}
+		file.WriteString(fmt.Sprintf("export interface Edge<T> {\n  %s: string;\n  %s: T;\n}\n\n", key("cursor"), key("node")))
+		file.WriteString(fmt.Sprintf("export interface Paginated<T> {\n  %s: %s;\n  %s: %s;\n}\n\n", key("edges"), g.arrayType("Edge<T>"), key("pageInfo"), pageInfo))
+		file.WriteString(fmt.Sprintf("export type NodeOf<C> = C extends { %s?: ReadonlyArray<infer E> | null }\n", key("edges")))
+		file.WriteString(fmt.Sprintf("  ? E extends { %s?: infer N }\n    ? NonNullable<N>\n    : never\n  : never;\n\n", key("node")))
+		file.WriteString("export interface CursorPaginationVariables {\n")
+		file.WriteString(fmt.Sprintf("  %s?: %s;\n  %s?: %s;\n", key("first"), g.inputNullable("number"), key("after"), g.inputNullable("string")))
+		file.WriteString(fmt.Sprintf("  %s?: %s;\n  %s?: %s;\n}\n\n", key("last"), g.inputNullable("number"), key("before"), g.inputNullable("string")))
+	}
+	if conventions.offset {
+		file.WriteString("export interface OffsetPaginationVariables {\n")
+		file.WriteString(fmt.Sprintf("  %s?: %s;\n  %s?: %s;\n}\n\n", key("offset"), g.inputNullable("number"), key("limit"), g.inputNullable("number")))
+	}
+}
+
+// Check that the configured pagination fields have known roles
+func (g *Generator) checkPaginationFields() error {
+	for _, role := range sortedKeys(g.opts.PaginationFields) {
+		if _, found := defaultPaginationFields[role]; !found {
+			return fmt.Errorf("unknown pagination field %q, expected one of %s", role, strings.Join(sortedKeys(defaultPaginationFields), ", "))
+		}
+	}
+	return nil
+}
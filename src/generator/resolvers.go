@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Generic resolver signatures, shared by all root resolver interfaces
+const resolverTypes = `export type Resolver<TResult, TParent, TContext, TArgs> = (
+  parent: TParent,
+  args: TArgs,
+  context: TContext,
+  info: GraphQLResolveInfo,
+) => TResult | Promise<TResult>;
+
+export interface SubscriptionResolver<TResult, TParent, TContext, TArgs> {
+  subscribe: Resolver<AsyncIterable<unknown>, TParent, TContext, TArgs>;
+  resolve?: Resolver<TResult, unknown, TContext, TArgs>;
+}
+
+`
+
+// Generate the argument types and resolver interfaces of the root operation types
+func (g *Generator) writeResolvers(file *bufio.Writer) {
+	g.writeResolverTypes(file)
+	for i, fields := range g.schema.roots() {
+		g.writeRootResolvers(file, rootNames[i], fields)
+	}
+}
+
+// Generate the generic resolver signatures
+func (g *Generator) writeResolverTypes(file *bufio.Writer) {
+	file.WriteString(resolverTypes)
+	g.useImport(resolveInfoImport)
+}
+
+// Generate the argument types and resolver interface of a root operation type, skipped if it has no fields
+func (g *Generator) writeRootResolvers(file *bufio.Writer, root string, fields map[string]*ast.FieldDefinition) {
+	fieldNames := g.selectedRootFields(root, fields)
+	if len(fieldNames) == 0 {
+		return
+	}
+
+	// The codegen preset declares the arguments types next to the root types
+	for _, fieldName := range fieldNames {
+		if field := fields[fieldName]; len(field.Arguments) > 0 && !g.codegen() {
+			g.writeArgsInterface(file, argsTypeName(root, field), field.Arguments)
+		}
+	}
+
+	resolver := "Resolver"
+	if root == "Subscription" {
+		resolver = "SubscriptionResolver"
+	}
+	g.useHelper(resolver)
+	file.WriteString(fmt.Sprintf("export interface %sResolvers<TContext = unknown, TParent = {}> {\n", g.tsName(root)))
+	for _, fieldName := range fieldNames {
+		field := fields[fieldName]
+		resultType := g.fieldType(root, field)
+		if !field.Type.NonNull {
+			resultType = g.nullable(resultType)
+		}
+		argsType := "Record<string, never>"
+		if len(field.Arguments) > 0 {
+			argsType = argsTypeName(root, field)
+		}
+		file.WriteString(fmt.Sprintf("  %s?: %s<%s, TParent, TContext, %s>;\n", g.propertyKey(field.Name), resolver, resultType, argsType))
+	}
+	file.WriteString("}\n\n")
+}
+
+// Generate the interface of the arguments of a field. Nullable arguments are optional.
+func (g *Generator) writeArgsInterface(file *bufio.Writer, name string, args ast.ArgumentDefinitionList) {
+	file.WriteString(g.openDeclaration(name))
+	for _, arg := range args {
+		writeDocComment(file, "  ", g.docLines(arg.Description, arg.Position, arg.Directives))
+		argType := g.convertGraphqlTypeToTs(arg.Type.String())
+		if arg.Type.NonNull {
+			file.WriteString(fmt.Sprintf("  %s: %s;\n", g.propertyKey(arg.Name), argType))
+		} else {
+			file.WriteString(fmt.Sprintf("  %s?: %s;\n", g.propertyKey(arg.Name), g.inputNullable(argType)))
+		}
+	}
+	file.WriteString(g.closeDeclaration())
+}
+
+// Return the name of the arguments type of a field, such as QueryUserArgs
+func argsTypeName(owner string, field *ast.FieldDefinition) string {
+	return owner + strings.ToUpper(field.Name[:1]) + field.Name[1:] + "Args"
+}
@@ -0,0 +1,26 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+)
+
+// Generate the types of a GraphQL execution result, as sent by servers following the specification:
+// the data is null when execution failed, and missing when the request failed before execution
+func (g *Generator) writeResponseTypes(file *bufio.Writer) {
+	file.WriteString("export interface GraphQLErrorLocation {\n  line: number;\n  column: number;\n}\n\n")
+	file.WriteString("export interface GraphQLError {\n")
+	file.WriteString("  message: string;\n")
+	file.WriteString(fmt.Sprintf("  locations?: %s;\n", g.arrayType("GraphQLErrorLocation")))
+	file.WriteString(fmt.Sprintf("  path?: %s;\n", g.arrayType("string | number")))
+	file.WriteString("  extensions?: Record<string, unknown>;\n")
+	file.WriteString("}\n\n")
+	file.WriteString("export interface GraphQLResponse<T> {\n")
+	file.WriteString("  data?: T | null;\n")
+	file.WriteString(fmt.Sprintf("  errors?: %s;\n", g.arrayType("GraphQLError")))
+	file.WriteString("  extensions?: Record<string, unknown>;\n")
+	file.WriteString("}\n\n")
+}
+
+// Names of the response types
+var responseTypeNames = []string{"GraphQLErrorLocation", "GraphQLError", "GraphQLResponse"}
@@ -0,0 +1,561 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Struct to store type and interface information
+type TypeInfo struct {
+	Name       string
+	Definition *ast.Definition
+}
+
+// Schema is the merged model of all schema files added to a generator
+type Schema struct {
+	Types     map[string]*TypeInfo
+	Enums     map[string]*ast.Definition
+	Queries   map[string]*ast.FieldDefinition // Для Query
+	Mutations map[string]*ast.FieldDefinition // Для Mutation
+	// Subscription fields
+	Subscriptions map[string]*ast.FieldDefinition
+	Scalars       map[string]*ast.Definition
+	Inputs        map[string]*ast.Definition
+	Unions        map[string]*ast.Definition
+	// Custom directive definitions
+	Directives map[string]*ast.DirectiveDefinition
+	// Names of the definitions hidden by the internal directive or by tag filtering
+	hidden map[string]bool
+	// Where each definition and root field (as Root.field) was first declared
+	declared map[string]declaration
+	// Names of the definitions merged from extensions only, replaced by their base definition when it is added
+	extended map[string]bool
+	// Number of merged schema files
+	sources int
+	// Sorted implementers of each interface, built on first use and reset when a type is added
+	implementersIndex map[string][]string
+}
+
+// Position of a declaration, ordered by schema file then by offset in the file
+type declaration struct {
+	source int
+	offset int
+}
+
+// NewSchema creates an empty schema
+func NewSchema() *Schema {
+	return &Schema{
+		Types:         make(map[string]*TypeInfo),
+		Enums:         make(map[string]*ast.Definition),
+		Queries:       make(map[string]*ast.FieldDefinition),
+		Mutations:     make(map[string]*ast.FieldDefinition),
+		Subscriptions: make(map[string]*ast.FieldDefinition),
+		Scalars:       make(map[string]*ast.Definition),
+		Inputs:        make(map[string]*ast.Definition),
+		Unions:        make(map[string]*ast.Definition),
+		Directives:    make(map[string]*ast.DirectiveDefinition),
+		hidden:        make(map[string]bool),
+		declared:      make(map[string]declaration),
+		extended:      make(map[string]bool),
+	}
+}
+
+// Record the first declaration of a definition or root field
+func (s *Schema) declare(name string, pos *ast.Position) {
+	if _, found := s.declared[name]; !found && pos != nil {
+		s.declared[name] = declaration{source: s.sources, offset: pos.Start}
+	}
+}
+
+// Merge the definitions of a parsed schema file. Built-in scalars and introspection types are skipped.
+// Every conflicting definition of the file is reported, joined into one error, and the first definition is kept.
+// Types the file extends without defining them are merged into their definitions from other files.
+func (s *Schema) merge(schema *parsedSchema, path string, g *Generator) error {
+	defer func() { s.sources++ }()
+	if err := g.checkConflictStrategies(); err != nil {
+		return err
+	}
+	var conflicts []error
+
+	// Process types and interfaces
+	for _, name := range sortedKeys(schema.Types) {
+		typ := schema.Types[name]
+		if typ.BuiltIn {
+			continue
+		}
+		if schema.extensions[typ.Name] && !isRootName(typ.Name) {
+			if s.hidden[typ.Name] || g.isInternal(typ.Directives) {
+				continue
+			}
+			g.debugf("Processing extension: %s from file %s\n", typ.Name, path)
+			if err := s.addExtension(g.withoutUntaggedFields(g.withoutInternal(typ)), true); err != nil {
+				conflicts = append(conflicts, err)
+			}
+			continue
+		}
+		s.declare(typ.Name, typ.Position)
+		if g.isInternal(typ.Directives) || !g.isTagged(typ) {
+			g.debugf("Skipping hidden definition: %s from file %s\n", typ.Name, path)
+			s.hidden[typ.Name] = true
+			s.removeExtended(typ.Name)
+			continue
+		}
+		typ = g.withoutUntaggedFields(g.withoutInternal(typ))
+		if s.extended[typ.Name] {
+			if err := s.addExtension(typ, false); err != nil {
+				conflicts = append(conflicts, err)
+			}
+			continue
+		}
+
+		g.debugf("Processing type: %s from file %s\n", typ.Name, path)
+		if typ.Kind == ast.Object || typ.Kind == ast.Interface {
+			if typ.Name == "Query" {
+				// Добавляем все поля Query
+				for _, field := range typ.Fields {
+					if isIntrospectionField(field) {
+						continue
+					}
+					g.debugf("Adding Query field: %s\n", field.Name)
+					if err := s.addRootField("Query", s.Queries, field, g.conflictStrategy("Query")); err != nil {
+						conflicts = append(conflicts, err)
+					}
+				}
+			} else if typ.Name == "Mutation" {
+				// Добавляем все поля Mutation
+				for _, field := range typ.Fields {
+					if isIntrospectionField(field) {
+						continue
+					}
+					g.debugf("Adding Mutation field: %s\n", field.Name)
+					if err := s.addRootField("Mutation", s.Mutations, field, g.conflictStrategy("Mutation")); err != nil {
+						conflicts = append(conflicts, err)
+					}
+				}
+			} else if typ.Name == "Subscription" {
+				for _, field := range typ.Fields {
+					g.debugf("Adding Subscription field: %s\n", field.Name)
+					if err := s.addRootField("Subscription", s.Subscriptions, field, g.conflictStrategy("Subscription")); err != nil {
+						conflicts = append(conflicts, err)
+					}
+				}
+			} else {
+				if err := s.addTypeOrInterface(typ, g.conflictStrategy(typ.Name)); err != nil {
+					conflicts = append(conflicts, err)
+				}
+				g.debugf("Added type/interface: %s\n", typ.Name)
+			}
+		}
+
+		// Process custom scalars
+		if typ.Kind == ast.Scalar {
+			if _, found := s.Scalars[typ.Name]; !found {
+				s.Scalars[typ.Name] = typ
+			}
+		}
+
+		// Process enums
+		if typ.Kind == ast.Enum {
+			g.debugf("Processing enum: %s from file %s\n", typ.Name, path)
+			if err := s.addDefinition(s.Enums, "enum", typ, g.conflictStrategy(typ.Name), diffEnums); err != nil {
+				conflicts = append(conflicts, err)
+			}
+			g.debugf("Added enum: %s\n", typ.Name)
+		}
+
+		// Process input objects and unions
+		if typ.Kind == ast.InputObject {
+			if err := s.addDefinition(s.Inputs, "input", typ, g.conflictStrategy(typ.Name), diffFields); err != nil {
+				conflicts = append(conflicts, err)
+			}
+		}
+		if typ.Kind == ast.Union {
+			if err := s.addDefinition(s.Unions, "union", typ, g.conflictStrategy(typ.Name), diffUnions); err != nil {
+				conflicts = append(conflicts, err)
+			}
+		}
+	}
+
+	// Process custom directives
+	for _, name := range sortedKeys(schema.Directives) {
+		directive := schema.Directives[name]
+		if directive.Position == nil || directive.Position.Src == nil || directive.Position.Src.BuiltIn {
+			continue
+		}
+		if _, found := s.Directives[name]; !found {
+			s.Directives[name] = directive
+		}
+	}
+
+	if len(conflicts) == 1 {
+		return conflicts[0]
+	}
+	return errors.Join(conflicts...)
+}
+
+// Return the strategy resolving the conflicting definitions of a type or root type: its own strategy,
+// first-wins if it is listed in SkipChecksTypes, the global one, or first-wins with the SkipChecks option
+func (g *Generator) conflictStrategy(name string) string {
+	if strategy, found := g.opts.TypeConflicts[name]; found {
+		return strategy
+	}
+	if slices.Contains(g.opts.SkipChecksTypes, name) {
+		return ConflictFirstWins
+	}
+	if g.opts.Conflicts != "" {
+		return g.opts.Conflicts
+	}
+	if g.opts.SkipChecks {
+		return ConflictFirstWins
+	}
+	return ConflictError
+}
+
+// Check that the configured conflict strategies are known
+func (g *Generator) checkConflictStrategies() error {
+	strategies := map[string]string{"": g.opts.Conflicts}
+	for name, strategy := range g.opts.TypeConflicts {
+		strategies[name] = strategy
+	}
+	for _, name := range sortedKeys(strategies) {
+		switch strategies[name] {
+		case "", ConflictError, ConflictFirstWins, ConflictLastWins, ConflictMergeFields:
+		default:
+			return fmt.Errorf("unknown conflict strategy %q, expected error, first-wins, last-wins or merge-fields", strategies[name])
+		}
+	}
+	return nil
+}
+
+// Return the merged definition of a named type, enum, input, union or scalar, or nil if unknown
+func (s *Schema) definition(name string) *ast.Definition {
+	if typeInfo, found := s.Types[name]; found {
+		return typeInfo.Definition
+	}
+	for _, defs := range []map[string]*ast.Definition{s.Enums, s.Inputs, s.Unions, s.Scalars} {
+		if def, found := defs[name]; found {
+			return def
+		}
+	}
+	return nil
+}
+
+// Add the extension of a type from a file without its definition, or the definition of a type
+// only extended so far. The fields, enum values, union members, interfaces and directives of the extensions
+// are appended to the definition, and a field declared twice must keep its type.
+func (s *Schema) addExtension(def *ast.Definition, extension bool) error {
+	existing := s.definition(def.Name)
+	merged := def
+	switch {
+	case existing == nil:
+		s.extended[def.Name] = true
+		s.declare(def.Name, def.Position)
+	case existing.Kind != def.Kind:
+		return positionErrorf(def.Position, "%s is extended as %s but declared as %s at %s",
+			def.Name, def.Kind, existing.Kind, formatPosition(existing.Position))
+	default:
+		base, ext := existing, def
+		if !extension {
+			// The base definition comes after extensions from earlier files: it keeps its place and description
+			base, ext = def, existing
+			delete(s.extended, def.Name)
+			delete(s.declared, def.Name)
+			s.declare(def.Name, def.Position)
+		}
+		var diffs []string
+		merged, diffs = mergeDefinitions(base, ext)
+		if len(diffs) > 0 {
+			return positionErrorf(def.Position, "%s has conflicting extensions (defined at %s): %s",
+				def.Name, formatPosition(base.Position), strings.Join(diffs, "; "))
+		}
+		merged.Directives = append(append(ast.DirectiveList{}, base.Directives...), ext.Directives...)
+	}
+
+	switch def.Kind {
+	case ast.Object, ast.Interface:
+		s.Types[def.Name] = &TypeInfo{Name: def.Name, Definition: merged}
+		s.implementersIndex = nil
+	case ast.Enum:
+		s.Enums[def.Name] = merged
+	case ast.InputObject:
+		s.Inputs[def.Name] = merged
+	case ast.Union:
+		s.Unions[def.Name] = merged
+	case ast.Scalar:
+		s.Scalars[def.Name] = merged
+	}
+	return nil
+}
+
+// Remove a definition merged from extensions only, when its base definition is hidden
+func (s *Schema) removeExtended(name string) {
+	if !s.extended[name] {
+		return
+	}
+	delete(s.extended, name)
+	delete(s.Types, name)
+	for _, defs := range []map[string]*ast.Definition{s.Enums, s.Inputs, s.Unions, s.Scalars} {
+		delete(defs, name)
+	}
+	s.implementersIndex = nil
+}
+
+// Check for the __schema and __type fields the parser adds to the Query type
+func isIntrospectionField(field *ast.FieldDefinition) bool {
+	return strings.HasPrefix(field.Name, "__")
+}
+
+// Add type or interface to the schema
+func (s *Schema) addTypeOrInterface(def *ast.Definition, strategy string) error {
+	existing, found := s.Types[def.Name]
+	if !found {
+		// Add new type or interface
+		s.Types[def.Name] = &TypeInfo{
+			Name:       def.Name,
+			Definition: def,
+		}
+		s.implementersIndex = nil
+		return nil
+	}
+	kept, err := resolveConflict("type or interface", existing.Definition, def, strategy, diffFields)
+	if err != nil {
+		return err
+	}
+	if kept != existing.Definition {
+		existing.Definition = kept
+		s.implementersIndex = nil
+	}
+	return nil
+}
+
+// Add a root operation field to the schema. A field declared again is resolved with the conflict strategy
+// of its root type, merge-fields requiring the same type and arguments like error.
+func (s *Schema) addRootField(root string, fields map[string]*ast.FieldDefinition, field *ast.FieldDefinition, strategy string) error {
+	existing, found := fields[field.Name]
+	if !found {
+		s.declare(root+"."+field.Name, field.Position)
+		fields[field.Name] = field
+		return nil
+	}
+	switch strategy {
+	case ConflictFirstWins:
+		return nil
+	case ConflictLastWins:
+		fields[field.Name] = field
+		return nil
+	}
+	if diffs := diffRootFields(existing, field); len(diffs) > 0 {
+		return positionErrorf(field.Position, "%s.%s has conflicting definitions (previously defined at %s): %s",
+			root, field.Name, formatPosition(existing.Position), strings.Join(diffs, "; "))
+	}
+	return nil
+}
+
+// Compare the types and arguments of two declarations of a root field
+func diffRootFields(a, b *ast.FieldDefinition) []string {
+	var diffs []string
+	if a.Type.String() != b.Type.String() {
+		diffs = append(diffs, fmt.Sprintf("type is %s in %s but %s in %s", a.Type, sourceName(a.Position), b.Type, sourceName(b.Position)))
+	}
+	argTypes := func(field *ast.FieldDefinition) map[string]string {
+		args := make(map[string]string, len(field.Arguments))
+		for _, arg := range field.Arguments {
+			args[arg.Name] = arg.Type.String()
+		}
+		return args
+	}
+	return append(diffs, diffMembers("argument", a.Position, b.Position, argTypes(a), argTypes(b))...)
+}
+
+// Add an enum, input object or union to the schema
+func (s *Schema) addDefinition(defs map[string]*ast.Definition, kind string, def *ast.Definition, strategy string, diff definitionDiff) error {
+	existing, found := defs[def.Name]
+	if !found {
+		defs[def.Name] = def
+		return nil
+	}
+	kept, err := resolveConflict(kind, existing, def, strategy, diff)
+	if err != nil {
+		return err
+	}
+	defs[def.Name] = kept
+	return nil
+}
+
+// Return the definition to keep when a definition is declared again, according to the conflict strategy
+func resolveConflict(kind string, existing, def *ast.Definition, strategy string, diff definitionDiff) (*ast.Definition, error) {
+	switch strategy {
+	case ConflictFirstWins:
+		return existing, nil
+	case ConflictLastWins:
+		return def, nil
+	case ConflictMergeFields:
+		merged, diffs := mergeDefinitions(existing, def)
+		if len(diffs) > 0 {
+			return nil, conflictError(kind, existing, def, diffs)
+		}
+		return merged, nil
+	}
+	if diffs := diff(existing, def); len(diffs) > 0 {
+		return nil, conflictError(kind, existing, def, diffs)
+	}
+	return existing, nil
+}
+
+// Merge two definitions of the same name: the fields, enum values, union members and interfaces of the
+// second one missing from the first one are appended. Fields declared in both must have the same type,
+// and the differing ones are returned. The definitions are not modified, as parsed files may be cached.
+func mergeDefinitions(a, b *ast.Definition) (*ast.Definition, []string) {
+	merged := *a
+	merged.Fields = append(ast.FieldList{}, a.Fields...)
+	merged.EnumValues = append(ast.EnumValueList{}, a.EnumValues...)
+	merged.Types = append([]string{}, a.Types...)
+	merged.Interfaces = append([]string{}, a.Interfaces...)
+
+	var diffs []string
+	for _, field := range b.Fields {
+		existing := merged.Fields.ForName(field.Name)
+		if existing == nil {
+			merged.Fields = append(merged.Fields, field)
+		} else if existing.Type.String() != field.Type.String() {
+			diffs = append(diffs, fmt.Sprintf("field %s is %s in %s but %s in %s",
+				field.Name, existing.Type, sourceName(existing.Position), field.Type, sourceName(field.Position)))
+		}
+	}
+	for _, value := range b.EnumValues {
+		if merged.EnumValues.ForName(value.Name) == nil {
+			merged.EnumValues = append(merged.EnumValues, value)
+		}
+	}
+	merged.Types = appendMissing(merged.Types, b.Types)
+	merged.Interfaces = appendMissing(merged.Interfaces, b.Interfaces)
+	return &merged, diffs
+}
+
+// Append the names missing from a list
+func appendMissing(names []string, more []string) []string {
+	for _, name := range more {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Describe the differences between two definitions of the same name, or return nil if they match
+type definitionDiff func(a, b *ast.Definition) []string
+
+// Report two definitions of the same name that differ, with every difference and the files involved
+func conflictError(kind string, existing, def *ast.Definition, diffs []string) error {
+	return positionErrorf(def.Position, "%s %s has conflicting definitions (previously defined at %s): %s",
+		kind, def.Name, formatPosition(existing.Position), strings.Join(diffs, "; "))
+}
+
+// Compare the member types of two unions, in any order
+func diffUnions(a, b *ast.Definition) []string {
+	members := func(def *ast.Definition) map[string]string {
+		types := make(map[string]string, len(def.Types))
+		for _, name := range def.Types {
+			types[name] = ""
+		}
+		return types
+	}
+	return diffMembers("member", a.Position, b.Position, members(a), members(b))
+}
+
+// Compare the structures of two type or interface definitions by field name and type.
+// Field order, descriptions and directives may differ between files declaring the same type.
+func diffFields(a, b *ast.Definition) []string {
+	fieldTypes := func(def *ast.Definition) map[string]string {
+		fields := make(map[string]string, len(def.Fields))
+		for _, field := range def.Fields {
+			fields[field.Name] = field.Type.String()
+		}
+		return fields
+	}
+	return diffMembers("field", a.Position, b.Position, fieldTypes(a), fieldTypes(b))
+}
+
+// Compare the values of two enums, in any order
+func diffEnums(a, b *ast.Definition) []string {
+	values := func(def *ast.Definition) map[string]string {
+		names := make(map[string]string, len(def.EnumValues))
+		for _, value := range def.EnumValues {
+			names[value.Name] = ""
+		}
+		return names
+	}
+	return diffMembers("value", a.Position, b.Position, values(a), values(b))
+}
+
+// Describe the members missing from either definition and the members whose types differ
+func diffMembers(noun string, a, b *ast.Position, membersA, membersB map[string]string) []string {
+	all := make(map[string]bool)
+	for name := range membersA {
+		all[name] = true
+	}
+	for name := range membersB {
+		all[name] = true
+	}
+
+	var diffs []string
+	for _, name := range sortedKeys(all) {
+		typeA, inA := membersA[name]
+		typeB, inB := membersB[name]
+		switch {
+		case !inB:
+			diffs = append(diffs, fmt.Sprintf("%s %s only in %s", noun, name, sourceName(a)))
+		case !inA:
+			diffs = append(diffs, fmt.Sprintf("%s %s only in %s", noun, name, sourceName(b)))
+		case typeA != typeB:
+			diffs = append(diffs, fmt.Sprintf("%s %s is %s in %s but %s in %s", noun, name, typeA, sourceName(a), typeB, sourceName(b)))
+		}
+	}
+	return diffs
+}
+
+// Return the name of the file a position is in
+func sourceName(pos *ast.Position) string {
+	if pos == nil || pos.Src == nil {
+		return "unknown file"
+	}
+	return pos.Src.Name
+}
+
+// Return map keys in declaration order when DeclarationOrder is set, and in sorted order otherwise.
+// The keys are root fields of the given root operation type, or definitions if root is empty.
+func orderedKeys[T any](g *Generator, m map[string]T, root string) []string {
+	keys := sortedKeys(m)
+	if !g.opts.DeclarationOrder {
+		return keys
+	}
+	position := func(key string) declaration {
+		if root != "" {
+			key = root + "." + key
+		}
+		return g.schema.declared[key]
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := position(keys[i]), position(keys[j])
+		if a.source != b.source {
+			return a.source < b.source
+		}
+		return a.offset < b.offset
+	})
+	return keys
+}
+
+// Return map keys in sorted order so the generated output is stable between runs
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// Write the merged schema as a single normalized SDL document, with definitions sorted by name
+func (g *Generator) emitSDL(ctx context.Context, w io.Writer) error {
+	merged := g.mergedSchema()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	file := bufio.NewWriter(w)
+	formatter.NewFormatter(file, formatter.WithIndent("  ")).FormatSchema(merged)
+	return file.Flush()
+}
+
+// Return the definitions of the merged schema as a gqlparser schema
+func (g *Generator) mergedSchema() *ast.Schema {
+	merged := &ast.Schema{
+		Types:      make(map[string]*ast.Definition),
+		Directives: g.schema.Directives,
+	}
+	for _, defs := range []map[string]*ast.Definition{g.schema.Scalars, g.schema.Enums, g.schema.Inputs, g.schema.Unions} {
+		for name, def := range defs {
+			merged.Types[name] = def
+		}
+	}
+	for name, typeInfo := range g.schema.Types {
+		merged.Types[name] = typeInfo.Definition
+	}
+
+	// Root types are rebuilt from the fields merged from every file
+	for i, fields := range g.schema.roots() {
+		if len(fields) == 0 {
+			continue
+		}
+		root := &ast.Definition{Kind: ast.Object, Name: rootNames[i]}
+		for _, name := range sortedKeys(fields) {
+			root.Fields = append(root.Fields, fields[name])
+		}
+		merged.Types[root.Name] = root
+	}
+	return merged
+}
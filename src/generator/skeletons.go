@@ -0,0 +1,249 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Default number of levels of object fields selected by operation documents
+const DefaultOperationDepth = 2
+
+// OperationDocuments returns a .graphql operation document for each selected Query and Mutation field,
+// to be edited into client queries or smoke tests. The documents select the scalar and enum fields
+// of the result, nested up to depth levels of object fields. The arguments of the root field and the
+// required arguments of nested fields are passed as variables. Files are named after the field and
+// the operation type, e.g. user.query.graphql.
+func (g *Generator) OperationDocuments(ctx context.Context, depth int) ([]OutputFile, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if depth < 1 {
+		return nil, fmt.Errorf("operation depth must be at least 1, got %d", depth)
+	}
+
+	var files []OutputFile
+	for _, op := range g.operations() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		files = append(files, OutputFile{
+			Name:    op.field.Name + "." + op.keyword + ".graphql",
+			Content: []byte(g.buildOperation(op, depth).document),
+		})
+	}
+	return files, nil
+}
+
+// A selected Query or Mutation field
+type operationField struct {
+	root    string
+	keyword string
+	field   *ast.FieldDefinition
+}
+
+// Return the selected Query and Mutation fields, skipping those of excluded types
+func (g *Generator) operations() []operationField {
+	var operations []operationField
+	for _, root := range []string{"Query", "Mutation"} {
+		fields := g.schema.Queries
+		if root == "Mutation" {
+			fields = g.schema.Mutations
+		}
+		for _, name := range orderedKeys(g, fields, root) {
+			field := fields[name]
+			if g.rootFieldSelected(root, name) && !g.isExcluded(field.Type.Name()) {
+				operations = append(operations, operationField{root, strings.ToLower(root), field})
+			}
+		}
+	}
+	return operations
+}
+
+// Return the name of the operation selecting a root field, e.g. User for Query.user
+func operationName(field *ast.FieldDefinition) string {
+	return strings.ToUpper(field.Name[:1]) + field.Name[1:]
+}
+
+// An operation document with the TypeScript types of its result and variables
+type builtOperation struct {
+	document string
+	// Properties of the result interface, one per line
+	result    string
+	variables ast.ArgumentDefinitionList
+}
+
+// Builder of one operation document, collecting the variables of the selected arguments
+type operationBuilder struct {
+	g         *Generator
+	variables ast.ArgumentDefinitionList
+	used      map[string]bool
+}
+
+// A selection, written as GraphQL and as the TypeScript type of the selected value
+type selection struct {
+	graphql    string
+	typescript string
+}
+
+// Generate the operation selecting a root field
+func (g *Generator) buildOperation(op operationField, depth int) builtOperation {
+	o := &operationBuilder{g: g, used: make(map[string]bool)}
+	selected, _ := o.field(op.root, op.field, true, depth, 1)
+
+	var b strings.Builder
+	b.WriteString(op.keyword + " " + operationName(op.field))
+	if len(o.variables) > 0 {
+		declarations := make([]string, len(o.variables))
+		for i, variable := range o.variables {
+			declarations[i] = "$" + variable.Name + ": " + variable.Type.String()
+		}
+		b.WriteString("(" + strings.Join(declarations, ", ") + ")")
+	}
+	b.WriteString(" {\n" + selected.graphql + "}\n")
+	return builtOperation{document: b.String(), result: selected.typescript, variables: o.variables}
+}
+
+// Return the selection of a field with its selection set, or false if the field is an object
+// nested deeper than the remaining depth. The TypeScript selection is the property of the field.
+func (o *operationBuilder) field(owner string, field *ast.FieldDefinition, root bool, depth int, indent int) (selection, bool) {
+	typeName := field.Type.Name()
+	composite := o.isComposite(typeName)
+	if composite && depth == 0 {
+		return selection{}, false
+	}
+
+	var args []string
+	for _, arg := range field.Arguments {
+		if root || (arg.Type.NonNull && arg.DefaultValue == nil) {
+			args = append(args, arg.Name+": $"+o.variable(field, arg))
+		}
+	}
+
+	pad := strings.Repeat("  ", indent)
+	graphql := pad + field.Name
+	if len(args) > 0 {
+		graphql += "(" + strings.Join(args, ", ") + ")"
+	}
+	var tsType string
+	if composite {
+		set := o.selectionSet(typeName, depth-1, indent+1)
+		graphql += " {\n" + set.graphql + pad + "}\n"
+		tsType = o.g.listType(field.Type, set.typescript)
+	} else {
+		graphql += "\n"
+		tsType = o.g.fieldType(owner, field)
+	}
+
+	if field.Type.NonNull {
+		return selection{graphql, fmt.Sprintf("%s%s: %s;\n", pad, o.g.propertyName(field), tsType)}, true
+	}
+	return selection{graphql, fmt.Sprintf("%s%s?: %s;\n", pad, o.g.propertyName(field), o.g.nullable(tsType))}, true
+}
+
+// Wrap the type of the items of a field in the arrays of its list types
+func (g *Generator) listType(typ *ast.Type, item string) string {
+	if typ.Elem != nil {
+		return g.arrayType(g.listType(typ.Elem, item))
+	}
+	return item
+}
+
+// Return the fields selected from an object, interface or union, with __typename when nothing else
+// can be selected. The members of unions are selected with inline fragments, and typed as a union
+// discriminated by __typename.
+func (o *operationBuilder) selectionSet(typeName string, depth int, indent int) selection {
+	pad := strings.Repeat("  ", indent)
+	closing := strings.Repeat("  ", indent-1)
+	if union, found := o.g.schema.Unions[typeName]; found {
+		set := selection{graphql: pad + "__typename\n"}
+		var variants []string
+		for _, member := range union.Types {
+			if o.g.isExcluded(member) {
+				continue
+			}
+			fields := o.fields(member, depth, indent+1)
+			if fields.graphql != "" {
+				set.graphql += pad + "... on " + member + " {\n" + fields.graphql + pad + "}\n"
+			}
+			variants = append(variants, "{\n"+pad+"__typename: "+quoteString(member)+";\n"+outdent(fields.typescript)+closing+"}")
+		}
+		set.typescript = strings.Join(variants, " | ")
+		if len(variants) == 0 {
+			set.typescript = "{\n" + pad + "__typename: string;\n" + closing + "}"
+		}
+		return set
+	}
+
+	fields := o.fields(typeName, depth, indent)
+	if def := o.g.schema.Types[typeName].Definition; fields.graphql == "" || def.Kind == ast.Interface {
+		fields.graphql = pad + "__typename\n" + fields.graphql
+		fields.typescript = pad + "__typename: " + o.typeNames(def) + ";\n" + fields.typescript
+	}
+	fields.typescript = "{\n" + fields.typescript + closing + "}"
+	return fields
+}
+
+// Return the selectable fields of an object type or interface, as GraphQL fields and TypeScript properties
+func (o *operationBuilder) fields(typeName string, depth int, indent int) selection {
+	var fields selection
+	typeInfo, found := o.g.schema.Types[typeName]
+	if !found {
+		return fields
+	}
+	for _, field := range o.g.objectFields(typeInfo.Definition) {
+		if o.g.isExcluded(field.Type.Name()) {
+			continue
+		}
+		if selected, ok := o.field(typeName, field, false, depth, indent); ok {
+			fields.graphql += selected.graphql
+			fields.typescript += selected.typescript
+		}
+	}
+	return fields
+}
+
+// Return the TypeScript type of the __typename of an object or interface: the quoted names of the types it can be
+func (o *operationBuilder) typeNames(def *ast.Definition) string {
+	if def.Kind != ast.Interface {
+		return quoteString(def.Name)
+	}
+	var names []string
+	for _, name := range o.g.schema.implementers(def.Name) {
+		if !o.g.isExcluded(name) {
+			names = append(names, quoteString(name))
+		}
+	}
+	if len(names) == 0 {
+		return "string"
+	}
+	return strings.Join(names, " | ")
+}
+
+// Remove one level of indentation from each line
+func outdent(lines string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(lines, "  "), "\n  ", "\n")
+}
+
+func (o *operationBuilder) isComposite(name string) bool {
+	_, isType := o.g.schema.Types[name]
+	_, isUnion := o.g.schema.Unions[name]
+	return isType || isUnion
+}
+
+// Declare the variable of an argument, named after the argument, or after the field and the argument
+// when another argument already uses the name
+func (o *operationBuilder) variable(field *ast.FieldDefinition, arg *ast.ArgumentDefinition) string {
+	name := arg.Name
+	if o.used[name] {
+		name = field.Name + strings.ToUpper(arg.Name[:1]) + arg.Name[1:]
+	}
+	for i := 2; o.used[name]; i++ {
+		name = field.Name + strings.ToUpper(arg.Name[:1]) + arg.Name[1:] + strconv.Itoa(i)
+	}
+	o.used[name] = true
+	o.variables = append(o.variables, &ast.ArgumentDefinition{Name: name, Type: arg.Type})
+	return name
+}
@@ -0,0 +1,240 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Files of a split TypeScript output, without extension
+const (
+	SplitEnums      = "enums"
+	SplitInputs     = "inputs"
+	SplitObjects    = "objects"
+	SplitOperations = "operations"
+	SplitIndex      = "index" // Re-exports all other files
+
+	// Files of the root types with the SplitRoots option
+	SplitQueries       = "queries"
+	SplitMutations     = "mutations"
+	SplitSubscriptions = "subscriptions"
+)
+
+// Files of the root types with the SplitRoots option, in the order of rootNames
+var splitRootFiles = []string{SplitQueries, SplitMutations, SplitSubscriptions}
+
+// OutputFile is one file of a split output
+type OutputFile struct {
+	Name    string
+	Content []byte
+}
+
+// A file of a split output and the declarations it holds
+type splitSection struct {
+	name  string
+	write emitSection
+}
+
+// EmitSplit emits the TypeScript declarations as one file per kind of definition: enums.ts, inputs.ts,
+// objects.ts and operations.ts import the types they reference from each other, index.ts re-exports all of them.
+// With the SplitRoots option, the declarations of each root type with selected fields move from operations.ts
+// to queries.ts, mutations.ts and subscriptions.ts. Files are returned in that order, with the configured extension.
+func (g *Generator) EmitSplit(ctx context.Context) ([]OutputFile, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.opts.Target != "" && g.opts.Target != TargetTypescript {
+		return nil, fmt.Errorf("split output is not supported by the %s target", g.opts.Target)
+	}
+	if _, found := javascriptExtensions[g.extension()]; !found {
+		return nil, fmt.Errorf("unsupported file extension %q, expected .ts, .mts or .cts", g.opts.Extension)
+	}
+	if err := g.checkOutput(); err != nil {
+		return nil, err
+	}
+
+	selected := g.selectedTypes()
+	owners := make(map[string]string)
+	for name := range g.schema.Enums {
+		owners[name] = SplitEnums
+	}
+	for name := range g.schema.Inputs {
+		owners[name] = SplitInputs
+	}
+	for name := range g.schema.Types {
+		owners[name] = SplitObjects
+	}
+	for name := range g.schema.Unions {
+		owners[name] = SplitObjects
+	}
+	if g.codegen() {
+		for _, name := range codegenHelperNames {
+			owners[name] = SplitObjects
+		}
+	}
+
+	sections := []splitSection{
+		{SplitEnums, func(g *Generator, file *bufio.Writer) error {
+			return g.writeEnums(ctx, file, selected)
+		}},
+		{SplitInputs, func(g *Generator, file *bufio.Writer) error {
+			return g.writeInputs(ctx, file, selected)
+		}},
+		{SplitObjects, func(g *Generator, file *bufio.Writer) error {
+			if g.codegen() {
+				g.writeCodegenHelpers(file)
+			}
+			g.writeScalars(file)
+			if err := g.writeObjects(ctx, file, selected); err != nil {
+				return err
+			}
+			return g.writeObjectHelpers(file, selected)
+		}},
+		{SplitOperations, func(g *Generator, file *bufio.Writer) error {
+			if g.opts.SplitRoots {
+				g.writeSharedOperations(file, selected)
+			} else {
+				g.writeOperations(file, selected)
+			}
+			return nil
+		}},
+	}
+	if g.scalarsRecord() {
+		owners["Scalars"] = SplitObjects
+	}
+	if g.opts.ResponseTypes {
+		for _, name := range responseTypeNames {
+			owners[name] = SplitObjects
+		}
+	}
+	if g.opts.SplitRoots {
+		owners["Resolver"] = SplitOperations
+		owners["SubscriptionResolver"] = SplitOperations
+		for i, fields := range g.schema.roots() {
+			root, fields := rootNames[i], fields
+			if len(g.selectedRootFields(root, fields)) == 0 {
+				continue
+			}
+			owners[root] = splitRootFiles[i]
+			sections = append(sections, splitSection{splitRootFiles[i], func(g *Generator, file *bufio.Writer) error {
+				g.writeRootOperations(file, root, fields, selected)
+				return nil
+			}})
+		}
+	}
+
+	// The bodies of the files are written concurrently, each collecting the types it references
+	bodies := make([]emitSection, len(sections))
+	for i, section := range sections {
+		bodies[i] = section.write
+	}
+	outputs, err := g.emitSections(bodies)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []OutputFile
+	for i, section := range sections {
+		content, err := g.emitSection(section.name, outputs[i], owners)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, OutputFile{Name: section.name + g.extension(), Content: content})
+	}
+
+	var index bytes.Buffer
+	indexWriter := bufio.NewWriter(&index)
+	g.writeBanner(indexWriter)
+	for _, section := range sections {
+		indexWriter.WriteString(fmt.Sprintf("export * from '%s';\n", g.moduleSpecifier("./"+section.name)))
+	}
+	if err := indexWriter.Flush(); err != nil {
+		return nil, err
+	}
+	files = append(files, OutputFile{Name: SplitIndex + g.extension(), Content: index.Bytes()})
+	return files, nil
+}
+
+// Generate one file of a split output around its body, importing the types it references from the other files
+func (g *Generator) emitSection(section string, output sectionOutput, owners map[string]string) ([]byte, error) {
+	body, references := output.content, output.references
+
+	// Type-only imports are erased from the JavaScript output, so the files only import each other at runtime
+	// for the types used as values, such as the enums of input default values
+	imports := make(map[string][]string)
+	valueImports := make(map[string][]string)
+	for _, name := range sortedKeys(references.types) {
+		if owner := owners[name]; owner != "" && owner != section && !references.values[name] {
+			imports[owner] = append(imports[owner], g.tsName(name))
+		}
+	}
+	for _, name := range sortedKeys(references.values) {
+		if owner := owners[name]; owner != "" && owner != section {
+			valueImports[owner] = append(valueImports[owner], g.tsName(name))
+		}
+	}
+
+	var content bytes.Buffer
+	file := bufio.NewWriter(&content)
+	g.writeHeader(file)
+	g.writeImportStatements(file, references.imports, 1)
+	if section == SplitOperations && g.opts.Vue != "" {
+		g.writeVueImports(file)
+	}
+	if section == SplitInputs && g.opts.Zod {
+		file.WriteString(zodImport)
+	}
+	for _, owner := range sortedKeys(valueImports) {
+		file.WriteString(fmt.Sprintf("import { %s } from '%s';\n", strings.Join(valueImports[owner], ", "), g.moduleSpecifier("./"+owner)))
+	}
+	for _, owner := range sortedKeys(imports) {
+		file.WriteString(fmt.Sprintf("import type { %s } from '%s';\n", strings.Join(imports[owner], ", "), g.moduleSpecifier("./"+owner)))
+	}
+	if len(imports) > 0 || len(valueImports) > 0 {
+		file.WriteString("\n")
+	}
+	if bytes.Contains(body, []byte("Nullable<")) {
+		file.WriteString(g.nullableHelper())
+	}
+	if bytes.Contains(body, []byte("InputMaybe<")) && !g.codegen() {
+		file.WriteString(inputMaybeHelper)
+	}
+	file.Write(body)
+	if len(body) == 0 {
+		// Keep the file a module, so that index.ts can re-export it
+		file.WriteString("export {};\n")
+	}
+	if err := file.Flush(); err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
+
+// JavaScript extension of the module emitted for each TypeScript extension, used in ESM import specifiers
+var javascriptExtensions = map[string]string{
+	".ts":  ".js",
+	".mts": ".mjs",
+	".cts": ".cjs",
+}
+
+// Return the extension of split files
+func (g *Generator) extension() string {
+	if g.opts.Extension == "" {
+		return ".ts"
+	}
+	return g.opts.Extension
+}
+
+// Return a relative module specifier, with the JavaScript extension that ESM resolution requires in ESM mode
+func (g *Generator) moduleSpecifier(module string) string {
+	if !g.opts.ESM || !strings.HasPrefix(module, ".") {
+		return module
+	}
+	switch path.Ext(module) {
+	case ".js", ".mjs", ".cjs", ".json":
+		return module
+	}
+	return module + javascriptExtensions[g.extension()]
+}
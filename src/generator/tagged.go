@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"strings"
+)
+
+// ExtractTaggedSDL returns the type system documents of the tagged template literals of a
+// JavaScript or TypeScript source, such as gql`type User { id: ID! }`. Everything else,
+// including the ${} interpolations, is replaced with whitespace, so that the result parses
+// as SDL and error positions match the source. Templates holding operations or fragments are left out.
+func ExtractTaggedSDL(source string, tags []string) string {
+	s := &templateScanner{src: source, tags: tags, out: []byte(source)}
+	for i := range s.out {
+		if s.out[i] != '\n' {
+			s.out[i] = ' '
+		}
+	}
+	s.scanCode(0, false)
+	return string(s.out)
+}
+
+type templateScanner struct {
+	src  string
+	tags []string
+	out  []byte
+}
+
+// Scan code from i to the end of the source, or to the closing brace of an interpolation
+func (s *templateScanner) scanCode(i int, interpolation bool) int {
+	depth := 0
+	for i < len(s.src) {
+		switch c := s.src[i]; {
+		case strings.HasPrefix(s.src[i:], "//"):
+			i = indexFrom(s.src, i, "\n")
+		case strings.HasPrefix(s.src[i:], "/*"):
+			i = indexFrom(s.src, i+2, "*/") + 2
+		case c == '\'' || c == '"':
+			i = s.skipString(i+1, c)
+		case c == '`':
+			i = s.scanTemplate(i+1, s.hasTag(i))
+		case c == '{':
+			depth++
+			i++
+		case c == '}':
+			if depth == 0 && interpolation {
+				return i + 1
+			}
+			depth--
+			i++
+		default:
+			i++
+		}
+	}
+	return len(s.src)
+}
+
+// Skip a string literal, returning the index after its closing quote
+func (s *templateScanner) skipString(i int, quote byte) int {
+	for i < len(s.src) {
+		switch s.src[i] {
+		case '\\':
+			i += 2
+		case quote, '\n':
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return len(s.src)
+}
+
+// Scan a template literal, copying its text to the output when it is a tagged type system document.
+// Return the index after the closing backtick.
+func (s *templateScanner) scanTemplate(i int, tagged bool) int {
+	type segment struct{ start, end int }
+	var segments []segment
+	start := i
+	for i < len(s.src) {
+		switch {
+		case s.src[i] == '\\':
+			i += 2
+			continue
+		case strings.HasPrefix(s.src[i:], "${"):
+			segments = append(segments, segment{start, i})
+			i = s.scanCode(i+2, true)
+			start = i
+			continue
+		case s.src[i] != '`':
+			i++
+			continue
+		}
+		break
+	}
+	end := min(i, len(s.src))
+	segments = append(segments, segment{start, end})
+
+	if tagged {
+		var text strings.Builder
+		for _, seg := range segments {
+			text.WriteString(s.src[seg.start:seg.end])
+		}
+		if isTypeSystemDocument(text.String()) {
+			for _, seg := range segments {
+				copy(s.out[seg.start:seg.end], s.src[seg.start:seg.end])
+			}
+		}
+	}
+	return end + 1
+}
+
+// Whether the identifier before the backtick at i is one of the tags
+func (s *templateScanner) hasTag(i int) bool {
+	end := len(strings.TrimRight(s.src[:i], " \t\r\n"))
+	start := end
+	for start > 0 && isIdentifierByte(s.src[start-1]) {
+		start--
+	}
+	for _, tag := range s.tags {
+		if s.src[start:end] == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// Return the index of substr in s at or after i, or the end of s
+func indexFrom(s string, i int, substr string) int {
+	if i >= len(s) {
+		return len(s)
+	}
+	if index := strings.Index(s[i:], substr); index >= 0 {
+		return i + index
+	}
+	return len(s)
+}
+
+// Whether a GraphQL document defines types rather than operations or fragments,
+// judging from its first keyword after the comments and descriptions
+func isTypeSystemDocument(document string) bool {
+	i := 0
+	for i < len(document) {
+		switch c := document[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == ',':
+			i++
+		case c == '#':
+			i = indexFrom(document, i, "\n")
+		case strings.HasPrefix(document[i:], `"""`):
+			i = indexFrom(document, i+3, `"""`) + 3
+		case c == '"':
+			i = indexFrom(document, i+1, `"`) + 1
+		default:
+			end := i
+			for end < len(document) && isIdentifierByte(document[end]) {
+				end++
+			}
+			switch document[i:end] {
+			case "", "query", "mutation", "subscription", "fragment":
+				return false
+			}
+			return true
+		}
+	}
+	return false
+}
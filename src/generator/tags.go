@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Check whether directives include a @tag with one of the selected names
+func (g *Generator) hasSelectedTag(directives ast.DirectiveList) bool {
+	for _, tag := range directives.ForNames("tag") {
+		arg := tag.Arguments.ForName("name")
+		if arg == nil || arg.Value == nil {
+			continue
+		}
+		for _, name := range g.opts.Tags {
+			if arg.Value.Raw == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Check whether a definition is kept by tag filtering. Types and interfaces are kept when
+// they or one of their fields carry a selected tag, other kinds are left to pruning.
+func (g *Generator) isTagged(def *ast.Definition) bool {
+	if len(g.opts.Tags) == 0 || (def.Kind != ast.Object && def.Kind != ast.Interface) {
+		return true
+	}
+	if isRootName(def.Name) || g.hasSelectedTag(def.Directives) {
+		return true
+	}
+	for _, field := range def.Fields {
+		if g.hasSelectedTag(field.Directives) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the definition with only its tagged fields, unless the definition itself is tagged
+func (g *Generator) withoutUntaggedFields(def *ast.Definition) *ast.Definition {
+	if len(g.opts.Tags) == 0 || (def.Kind != ast.Object && def.Kind != ast.Interface) || g.hasSelectedTag(def.Directives) {
+		return def
+	}
+
+	var fields ast.FieldList
+	for _, field := range def.Fields {
+		if g.hasSelectedTag(field.Directives) {
+			fields = append(fields, field)
+		}
+	}
+	copied := *def
+	copied.Fields = fields
+	return &copied
+}
+
+// Check whether a name is one of the root operation types
+func isRootName(name string) bool {
+	for _, root := range rootNames {
+		if root == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,744 @@
+package generator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Emit streams the declarations of the schema for the configured target section by section to the writer.
+// It stops with the context error if ctx is cancelled before the output is complete.
+func (g *Generator) Emit(ctx context.Context, w io.Writer) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.checkOutput(); err != nil {
+		return err
+	}
+
+	switch g.opts.Target {
+	case "", TargetTypescript:
+		return g.emitTypescript(ctx, w)
+	case TargetFlow:
+		return g.emitFlow(ctx, w)
+	case TargetGo:
+		return g.emitGo(ctx, w)
+	case TargetSDL:
+		return g.emitSDL(ctx, w)
+	case TargetDocs:
+		return g.emitDocs(ctx, w)
+	case TargetHTML:
+		return g.emitHTMLDocs(ctx, w)
+	case TargetDOT:
+		return g.emitDOT(ctx, w)
+	case TargetMermaid:
+		return g.emitMermaid(ctx, w)
+	case TargetFixtures:
+		return g.emitFixtures(ctx, w)
+	default:
+		return fmt.Errorf("unknown target %q", g.opts.Target)
+	}
+}
+
+// Check the schema for problems that make the output incomplete
+func (g *Generator) checkOutput() error {
+	for _, name := range g.opts.LintSuppressions {
+		if _, found := lintSuppressions[name]; !found {
+			return fmt.Errorf("unknown lint suppression %q", name)
+		}
+	}
+	if _, found := vueClients[g.opts.Vue]; !found && g.opts.Vue != "" {
+		return fmt.Errorf("unknown Vue client %q, expected urql or villus", g.opts.Vue)
+	}
+	for _, name := range sortedKeys(g.schema.Enums) {
+		if enum := g.schema.Enums[name]; g.isUnionEnum(enum) && g.isNumericEnum(enum) {
+			return fmt.Errorf("enum %s is both numeric and a union of strings, remove one of the styles", name)
+		}
+	}
+	switch g.opts.ArraySyntax {
+	case "", ArrayGeneric, ArrayType:
+	default:
+		return fmt.Errorf("unknown array syntax %q, expected generic or array", g.opts.ArraySyntax)
+	}
+	if err := g.checkPaginationFields(); err != nil {
+		return err
+	}
+	switch g.opts.Preset {
+	case "":
+	case PresetCodegen:
+		if g.opts.InlineNull {
+			return fmt.Errorf("the codegen preset declares Maybe and InputMaybe, it cannot be combined with inline nulls")
+		}
+		if err := g.checkCodegenEnums(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown preset %q, expected codegen", g.opts.Preset)
+	}
+	switch g.opts.ReservedFields {
+	case "", ReservedQuote, ReservedRename, ReservedKeep:
+	default:
+		return fmt.Errorf("unknown reserved fields mode %q, expected quote, rename or keep", g.opts.ReservedFields)
+	}
+	if g.opts.StrictScalars {
+		if warnings := g.unmappedScalarWarnings(); len(warnings) > 0 {
+			messages := make([]string, len(warnings))
+			for i, warning := range warnings {
+				messages[i] = warning.Message
+			}
+			return fmt.Errorf("strict scalars: %s", strings.Join(messages, "; "))
+		}
+	}
+	if g.opts.Target == "" || g.opts.Target == TargetTypescript {
+		if err := g.checkTypeNames(); err != nil {
+			return err
+		}
+	}
+	return g.checkExcludedReferences()
+}
+
+// Generate the header of a TypeScript file: the banner and the lint suppression comments
+func (g *Generator) writeHeader(file *bufio.Writer) {
+	g.writeBanner(file)
+	g.writeLintSuppressions(file, []string{SuppressTslint, SuppressEslint})
+}
+
+// Generate the banner of a TypeScript file, with the configured metadata
+func (g *Generator) writeBanner(file *bufio.Writer) {
+	file.WriteString(`/*
+ * -------------------------------------------------------
+ * THIS FILE WAS AUTOMATICALLY GENERATED (DO NOT MODIFY)
+ * -------------------------------------------------------
+`)
+	for _, line := range g.headerMetadata() {
+		file.WriteString(" * " + line + "\n")
+	}
+	file.WriteString(" */\n\n")
+}
+
+// Generate the TypeScript declarations. The sections are independent of each other,
+// so they are written concurrently and then copied in order.
+func (g *Generator) emitTypescript(ctx context.Context, w io.Writer) error {
+	selected := g.selectedTypes()
+	sections, err := g.emitSections([]emitSection{
+		func(g *Generator, file *bufio.Writer) error {
+			return g.writeEnums(ctx, file, selected)
+		},
+		func(g *Generator, file *bufio.Writer) error {
+			return g.writeObjects(ctx, file, selected)
+		},
+		func(g *Generator, file *bufio.Writer) error {
+			return g.writeInputs(ctx, file, selected)
+		},
+		func(g *Generator, file *bufio.Writer) error {
+			return g.writeObjectHelpers(file, selected)
+		},
+		func(g *Generator, file *bufio.Writer) error {
+			g.writeOperations(file, selected)
+			return nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	file := bufio.NewWriter(w)
+	g.writeHeader(file)
+	g.writeImports(file)
+	if g.opts.Vue != "" {
+		g.writeVueImports(file)
+	}
+	if g.opts.Zod {
+		file.WriteString(zodImport)
+	}
+	if g.codegen() {
+		g.writeCodegenHelpers(file)
+	} else if !g.opts.InlineNull {
+		file.WriteString(g.nullableHelper())
+	}
+	if g.opts.InputMaybe && !g.opts.InlineNull && !g.codegen() {
+		file.WriteString(inputMaybeHelper)
+	}
+	g.writeScalars(file)
+	for _, section := range sections {
+		file.Write(section.content)
+	}
+	return file.Flush()
+}
+
+// Generate enums in "mirror" style
+func (g *Generator) writeEnums(ctx context.Context, file *bufio.Writer, selected map[string]bool) error {
+	for _, name := range orderedKeys(g, g.schema.Enums, "") {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if selected != nil && !selected[name] {
+			continue
+		}
+		enum := g.schema.Enums[name]
+		writeDocComment(file, "", g.definitionDocLines(enum))
+		if g.isUnionEnum(enum) {
+			g.writeUnionEnum(file, enum)
+		} else {
+			file.WriteString(fmt.Sprintf("export enum %s {\n", g.tsName(enum.Name)))
+			for i, value := range enum.EnumValues {
+				writeDocComment(file, "  ", g.docLines(value.Description, value.Position, value.Directives))
+				file.WriteString(fmt.Sprintf("  %s = %s,\n", g.enumMemberName(value.Name), g.enumValue(enum, value, i)))
+			}
+			file.WriteString("}\n\n")
+		}
+		if g.opts.EnumValues {
+			g.writeEnumValues(file, enum)
+		}
+		if g.opts.EnumHelpers {
+			g.writeEnumHelpers(file, enum)
+		}
+		if g.opts.Exhaustive {
+			g.writeEnumMatch(file, enum)
+		}
+	}
+	return nil
+}
+
+// Generate interfaces of the object types and interfaces
+func (g *Generator) writeObjects(ctx context.Context, file *bufio.Writer, selected map[string]bool) error {
+	for _, name := range orderedKeys(g, g.schema.Types, "") {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if selected != nil && !selected[name] {
+			continue
+		}
+		typeInfo := g.schema.Types[name]
+		writeDocComment(file, "", g.definitionDocLines(typeInfo.Definition))
+		file.WriteString(g.openDeclaration(g.tsName(typeInfo.Name)))
+		if typeInfo.Definition.Kind == ast.Object {
+			g.writeTypename(file, typeInfo.Name)
+		}
+		for _, field := range g.objectFields(typeInfo.Definition) {
+			g.writeField(file, typeInfo.Name, field)
+		}
+		file.WriteString(g.closeDeclaration())
+		if g.codegen() {
+			for _, field := range g.objectFields(typeInfo.Definition) {
+				if len(field.Arguments) > 0 {
+					g.writeArgsInterface(file, argsTypeName(g.tsName(typeInfo.Name), field), field.Arguments)
+				}
+			}
+		}
+	}
+	g.writeUnions(file, selected)
+	return nil
+}
+
+// Generate interfaces of the input types
+func (g *Generator) writeInputs(ctx context.Context, file *bufio.Writer, selected map[string]bool) error {
+	for _, name := range orderedKeys(g, g.schema.Inputs, "") {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if selected != nil && !selected[name] {
+			continue
+		}
+		input := g.schema.Inputs[name]
+		writeDocComment(file, "", g.definitionDocLines(input))
+		if g.opts.InputClasses {
+			if err := g.writeInputClass(file, input); err != nil {
+				return err
+			}
+			continue
+		}
+		file.WriteString(g.openDeclaration(g.tsName(input.Name)))
+		for _, field := range input.Fields {
+			g.writeField(file, input.Name, field)
+		}
+		file.WriteString(g.closeDeclaration())
+	}
+	if g.opts.InputDefaults {
+		g.writeInputDefaults(file, selected)
+	}
+	if g.opts.PartialInputs {
+		g.writePartialInputs(file, selected)
+	}
+	if g.opts.TypeMetadata {
+		g.writeTypeMetadata(file, g.metadataDefinitions(selected, true))
+	}
+	if g.opts.InputCoercion {
+		g.writeCoercions(file, selected)
+	}
+	if g.opts.Zod {
+		g.writeZodSchemas(file, selected)
+	}
+	return nil
+}
+
+// Generate the optional exports derived from the object types
+func (g *Generator) writeObjectHelpers(file *bufio.Writer, selected map[string]bool) error {
+	if g.opts.Federation {
+		if err := g.writeFederationTypes(file, selected); err != nil {
+			return err
+		}
+	}
+	if g.opts.Dates {
+		g.writeDateHelpers(file, selected)
+	}
+	if g.opts.TypeMetadata {
+		g.writeTypeMetadata(file, g.metadataDefinitions(selected, false))
+	}
+	g.writeHelpers(file, selected)
+	return nil
+}
+
+// Generate the root interfaces and the optional exports derived from them
+func (g *Generator) writeOperations(file *bufio.Writer, selected map[string]bool) {
+	for i, fields := range g.schema.roots() {
+		g.writeRootInterface(file, rootNames[i], fields)
+	}
+	if g.opts.ResultAliases {
+		g.writeResultAliases(file, "")
+	}
+	if g.opts.Resolvers {
+		g.writeResolvers(file)
+	}
+	if g.opts.OperationNames {
+		g.writeOperationNames(file)
+	}
+	if g.opts.ArgumentDefaults {
+		g.writeArgumentDefaults(file, g.argumentDefaults(selected))
+	}
+	if g.opts.Vue != "" {
+		g.writeVueComposables(file)
+	}
+}
+
+// Generate the declarations shared by the root types, which the SplitRoots option keeps in the operations file
+func (g *Generator) writeSharedOperations(file *bufio.Writer, selected map[string]bool) {
+	if g.opts.Resolvers {
+		g.writeResolverTypes(file)
+	}
+	if g.opts.OperationNames {
+		g.writeOperationNames(file)
+	}
+	if g.opts.ArgumentDefaults {
+		g.writeArgumentDefaults(file, g.rootArgumentDefaults(selected, ""))
+	}
+	if g.opts.Vue != "" {
+		g.writeVueComposables(file)
+	}
+}
+
+// Generate the declarations of one root type, written to its own file with the SplitRoots option
+func (g *Generator) writeRootOperations(file *bufio.Writer, root string, fields map[string]*ast.FieldDefinition, selected map[string]bool) {
+	g.writeRootInterface(file, root, fields)
+	if g.opts.ResultAliases {
+		g.writeResultAliases(file, root)
+	}
+	if g.opts.Resolvers {
+		g.writeRootResolvers(file, root, fields)
+	}
+	if g.opts.ArgumentDefaults {
+		g.writeArgumentDefaults(file, g.rootArgumentDefaults(selected, root))
+	}
+}
+
+// Check whether an enum is emitted with numeric values, by @tsNumeric or the NumericEnums option
+func (g *Generator) isNumericEnum(enum *ast.Definition) bool {
+	if enum.Directives.ForName("tsNumeric") != nil {
+		return true
+	}
+	for _, name := range g.opts.NumericEnums {
+		if name == enum.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the runtime value of an enum value: its name, or its ordinal for numeric enums
+func (g *Generator) enumValue(enum *ast.Definition, value *ast.EnumValueDefinition, index int) string {
+	if g.isNumericEnum(enum) {
+		return enumOrdinal(value, index)
+	}
+	return fmt.Sprintf("'%s'", value.Name)
+}
+
+// Check whether an enum is emitted as a union of string literals, by @tsUnion or the UnionEnums option
+func (g *Generator) isUnionEnum(enum *ast.Definition) bool {
+	if enum.Directives.ForName("tsUnion") != nil {
+		return true
+	}
+	for _, name := range g.opts.UnionEnums {
+		if name == enum.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Generate an enum as a union of the string literals of its values
+func (g *Generator) writeUnionEnum(file *bufio.Writer, enum *ast.Definition) {
+	if len(enum.EnumValues) == 0 {
+		file.WriteString(fmt.Sprintf("export type %s = never;\n\n", g.tsName(enum.Name)))
+		return
+	}
+	values := make([]string, len(enum.EnumValues))
+	for i, value := range enum.EnumValues {
+		values[i] = quoteString(value.Name)
+	}
+	file.WriteString(fmt.Sprintf("export type %s = %s;\n\n", g.tsName(enum.Name), strings.Join(values, " | ")))
+}
+
+// Return the numeric value of an enum value: its @tsValue, or its position in the enum
+func enumOrdinal(value *ast.EnumValueDefinition, index int) string {
+	if directive := value.Directives.ForName("tsValue"); directive != nil {
+		if arg := directive.Arguments.ForName("value"); arg != nil && arg.Value != nil {
+			return arg.Value.Raw
+		}
+	}
+	return strconv.Itoa(index)
+}
+
+// Return the names of the selected fields of a root operation type, in output order
+func (g *Generator) selectedRootFields(root string, fields map[string]*ast.FieldDefinition) []string {
+	var fieldNames []string
+	for _, fieldName := range orderedKeys(g, fields, root) {
+		if g.rootFieldSelected(root, fieldName) {
+			fieldNames = append(fieldNames, fieldName)
+		}
+	}
+	return fieldNames
+}
+
+// Generate the interface of a root operation type, skipped if it has no fields
+func (g *Generator) writeRootInterface(file *bufio.Writer, name string, fields map[string]*ast.FieldDefinition) {
+	fieldNames := g.selectedRootFields(name, fields)
+	if len(fieldNames) == 0 {
+		return
+	}
+
+	file.WriteString(g.openDeclaration(g.tsName(name)))
+	g.writeTypename(file, name)
+	for _, fieldName := range fieldNames {
+		g.writeField(file, name, fields[fieldName])
+	}
+	file.WriteString(g.closeDeclaration())
+	if g.codegen() {
+		for _, fieldName := range fieldNames {
+			if field := fields[fieldName]; len(field.Arguments) > 0 {
+				g.writeArgsInterface(file, argsTypeName(name, field), field.Arguments)
+			}
+		}
+	}
+}
+
+// Declaration of the wrapper of nullable input values with the InputMaybe option
+const inputMaybeHelper = "type InputMaybe<T> = T | null | undefined;\n\n"
+
+// Return the declaration of the Nullable helper. With the StrictTS option, nullable properties also accept
+// an explicit undefined, which exactOptionalPropertyTypes no longer allows for optional properties.
+func (g *Generator) nullableHelper() string {
+	if g.opts.StrictTS {
+		return "type Nullable<T> = T | null | undefined;\n\n"
+	}
+	return "type Nullable<T> = T | null;\n\n"
+}
+
+// Return a nullable type: Nullable<T>, or T | null with the InlineNull option
+func (g *Generator) nullable(tsType string) string {
+	if g.opts.InlineNull && g.opts.StrictTS {
+		return unionMember(tsType) + " | null | undefined"
+	} else if g.opts.InlineNull {
+		return unionMember(tsType) + " | null"
+	} else if g.codegen() {
+		g.useHelper("Maybe")
+		return "Maybe<" + tsType + ">"
+	}
+	return "Nullable<" + tsType + ">"
+}
+
+// Return the type of nullable input fields and arguments: InputMaybe<T> with the InputMaybe option,
+// or Nullable<T>, written inline with the InlineNull option
+func (g *Generator) inputNullable(tsType string) string {
+	if g.codegen() {
+		g.useHelper("InputMaybe")
+		return "InputMaybe<" + tsType + ">"
+	}
+	if !g.opts.InputMaybe {
+		return g.nullable(tsType)
+	}
+	if g.opts.InlineNull {
+		return unionMember(tsType) + " | null | undefined"
+	}
+	return "InputMaybe<" + tsType + ">"
+}
+
+// Parenthesize function types, which would otherwise return the union
+func unionMember(tsType string) string {
+	if strings.Contains(tsType, "=>") {
+		return "(" + tsType + ")"
+	}
+	return tsType
+}
+
+// Generate a single interface property. Nullable fields are optional and wrapped in Nullable,
+// or in InputMaybe for the fields of input types with the InputMaybe option.
+func (g *Generator) writeField(file *bufio.Writer, owner string, field *ast.FieldDefinition) {
+	if g.isUploadOutput(owner, field) {
+		return
+	}
+	writeDocComment(file, "  ", g.fieldDocLines(field))
+	isOptional := !strings.HasSuffix(field.Type.String(), "!")
+	fieldType := g.fieldType(owner, field)
+	if _, isInput := g.schema.Inputs[owner]; isOptional && isInput {
+		file.WriteString(fmt.Sprintf("  %s?: %s;\n", g.propertyName(field), g.inputNullable(fieldType)))
+	} else if isOptional {
+		file.WriteString(fmt.Sprintf("  %s?: %s;\n", g.propertyName(field), g.nullable(fieldType)))
+	} else {
+		file.WriteString(fmt.Sprintf("  %s: %s;\n", g.propertyName(field), fieldType))
+	}
+}
+
+// Return the TypeScript type of a field without its nullability, honoring field type overrides
+func (g *Generator) fieldType(owner string, field *ast.FieldDefinition) string {
+	if override, found := g.fieldTypeOverride(owner, field.Name); found {
+		return override.tsType
+	}
+	return g.convertGraphqlTypeToTs(field.Type.String())
+}
+
+// Return the type of an array of items in the configured syntax
+func (g *Generator) arrayType(item string) string {
+	if g.opts.ArraySyntax != ArrayType {
+		return "Array<" + item + ">"
+	}
+	if hasTopLevelOperator(item) {
+		return "(" + item + ")[]"
+	}
+	return item + "[]"
+}
+
+// Check whether a type has a union, intersection or function arrow outside of brackets,
+// so that it must be parenthesized before []
+func hasTopLevelOperator(tsType string) bool {
+	depth := 0
+	for i, r := range tsType {
+		switch r {
+		case '<', '{', '(', '[':
+			depth++
+		case '>':
+			if i == 0 || tsType[i-1] != '=' {
+				depth--
+			} else if depth == 0 {
+				return true
+			}
+		case '}', ')', ']':
+			depth--
+		case '|', '&':
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Convert GraphQL types to TypeScript types
+func (g *Generator) convertGraphqlTypeToTs(graphqlType string) string {
+	// Remove '!' at the end, as this represents non-nullable type in GraphQL
+	cleanType := strings.TrimSuffix(graphqlType, "!")
+
+	// Check if this is an array
+	if strings.HasPrefix(cleanType, "[") && strings.HasSuffix(cleanType, "]") {
+		// This is an array, extract the inner type
+		innerType := cleanType[1 : len(cleanType)-1]
+		// Recursively call convertGraphqlTypeToTs for the inner type
+		item := g.convertGraphqlTypeToTs(innerType)
+		if g.codegen() && !strings.HasSuffix(innerType, "!") {
+			// codegen keeps the nullability of list items
+			item = g.nullable(item)
+		}
+		return g.arrayType(item)
+	}
+
+	if tsType, found := g.scalarType(cleanType); found {
+		if flowType, found := flowScalars[tsType]; found && g.opts.Target == TargetFlow {
+			return flowType
+		}
+		if g.scalarsRecord() {
+			// Scalar fields follow the emitted record, so that it is the single declaration of each scalar type
+			if g.references != nil {
+				g.references.types["Scalars"] = true
+			}
+			return fmt.Sprintf("Scalars['%s']", cleanType)
+		}
+		return tsType
+	}
+
+	if !g.isResolved(cleanType) {
+		return g.unresolvedType(cleanType)
+	}
+
+	// Keep custom types as they are, unless renamed
+	if g.references != nil {
+		g.references.types[cleanType] = true
+	}
+	return g.tsName(cleanType)
+}
+
+// Return the TypeScript name of a GraphQL type: its configured rename, its @tsName, or the GraphQL name
+func (g *Generator) tsName(name string) string {
+	if tsName, found := g.opts.Renames[name]; found {
+		return tsName
+	}
+	if def := g.schema.definition(name); def != nil {
+		if tsName, found := tsNameDirective(def.Directives); found {
+			return tsName
+		}
+	}
+	return name
+}
+
+// Return the name given by a @tsName directive
+func tsNameDirective(directives ast.DirectiveList) (string, bool) {
+	directive := directives.ForName("tsName")
+	if directive == nil {
+		return "", false
+	}
+	if arg := directive.Arguments.ForName("name"); arg != nil && arg.Value != nil {
+		return arg.Value.Raw, true
+	}
+	return "", false
+}
+
+// Built-in GraphQL scalar -> TypeScript type mappings
+var defaultScalars = map[string]string{
+	"String":     "string",
+	"Int":        "number",
+	"Float":      "number",
+	"Boolean":    "boolean",
+	"ID":         "string", // In TypeScript, IDs can be represented as strings
+	"DateTime":   "string",
+	"JSONObject": "Record<string, unknown>",
+	"Upload":     "File | Blob", // Multipart uploads, only valid in inputs and arguments
+}
+
+// Check whether a field is a response field of the Upload scalar, which can only be sent
+func (g *Generator) isUploadOutput(owner string, field *ast.FieldDefinition) bool {
+	if field.Type.Name() != "Upload" {
+		return false
+	}
+	if _, input := g.schema.Inputs[owner]; input {
+		return false
+	}
+	_, overridden := g.fieldTypeOverride(owner, field.Name)
+	return !overridden
+}
+
+// TypeScript types of the JSON scalar modes
+var jsonTypes = map[string]string{
+	JSONUnknown: "unknown",
+	JSONAny:     "any",
+	JSONRecord:  "Record<string, unknown>",
+}
+
+// Return the TypeScript type of the JSON scalar for the configured mode
+func (g *Generator) jsonType() string {
+	if tsType, found := jsonTypes[g.opts.JSON]; found {
+		return tsType
+	}
+	return jsonTypes[JSONUnknown]
+}
+
+// Return the TypeScript type of the JSONObject scalar for the configured mode
+func (g *Generator) jsonObjectType() string {
+	if tsType, found := jsonTypes[g.opts.JSONObject]; found {
+		return tsType
+	}
+	return jsonTypes[JSONRecord]
+}
+
+// Return the configured mapping of a scalar: its own, or else the longest glob pattern matching a custom scalar,
+// so that a policy such as *=unknown applies to every custom scalar while the built-in scalars keep their types
+func (g *Generator) configuredScalar(name string) (string, bool) {
+	if value, found := g.opts.Scalars[name]; found {
+		return value, true
+	}
+	if slices.Contains(builtInScalars, name) {
+		return "", false
+	}
+	pattern := ""
+	for _, candidate := range sortedKeys(g.opts.Scalars) {
+		if strings.ContainsAny(candidate, "*?[") && len(candidate) > len(pattern) && matchPattern(candidate, name) {
+			pattern = candidate
+		}
+	}
+	if pattern == "" {
+		return "", false
+	}
+	return g.opts.Scalars[pattern], true
+}
+
+// Built-in GraphQL scalars, in the order of the Scalars record
+var builtInScalars = []string{"ID", "String", "Boolean", "Int", "Float"}
+
+// Generate the Scalars record of the TypeScript type of every built-in and mapped custom scalar
+func (g *Generator) writeScalars(file *bufio.Writer) {
+	if g.codegen() {
+		g.writeCodegenScalars(file)
+		return
+	}
+	if !g.opts.ScalarsRecord {
+		return
+	}
+	file.WriteString("export interface Scalars {\n")
+	for _, name := range builtInScalars {
+		tsType, _ := g.scalarType(name)
+		file.WriteString(fmt.Sprintf("  %s: %s;\n", name, tsType))
+	}
+	for _, name := range orderedKeys(g, g.schema.Scalars, "") {
+		if tsType, found := g.scalarType(name); found {
+			file.WriteString(fmt.Sprintf("  %s: %s;\n", name, tsType))
+		}
+	}
+	file.WriteString("}\n\n")
+}
+
+// Report whether scalar fields reference the Scalars record, emitted by the TypeScript target with the
+// ScalarsRecord option. The codegen preset inlines scalar types, as its record splits them into input and output.
+func (g *Generator) scalarsRecord() bool {
+	return g.opts.ScalarsRecord && !g.codegen() && (g.opts.Target == "" || g.opts.Target == TargetTypescript)
+}
+
+// Look up the TypeScript type of a scalar. Custom scalar mappings take precedence.
+func (g *Generator) scalarType(name string) (string, bool) {
+	if value, found := g.configuredScalar(name); found {
+		imported := parseImportedType(value)
+		g.useImport(imported)
+		return imported.tsType, true
+	}
+	if name == "DateTime" && g.opts.Dates {
+		return "Date", true
+	}
+	if name == "JSON" {
+		return g.jsonType(), true
+	}
+	if name == "JSONObject" {
+		return g.jsonObjectType(), true
+	}
+	if name == "BigInt" || name == "Long" {
+		if g.opts.BigInt != "" {
+			return g.opts.BigInt, true
+		}
+		return "bigint", true
+	}
+	tsType, found := defaultScalars[name]
+	return tsType, found
+}
@@ -0,0 +1,198 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// FieldUsage lists the client operations selecting a field of an object type or interface
+type FieldUsage struct {
+	Type  string
+	Field string
+	// Names of the operations selecting the field, directly or through fragments, sorted.
+	// Anonymous operations are named after their document.
+	Operations []string
+}
+
+// FieldUsages reports, for every field of the object types and interfaces, the operations of the documents
+// selecting it. Fields without operations are not used by the clients. A field selected through an interface
+// is counted as used in the interface and in each of its implementations, as any of them can provide the value.
+// The documents must be valid: the validation problems are returned as the error otherwise.
+func (g *Generator) FieldUsages(ctx context.Context, sources []OperationSource) ([]FieldUsage, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	document, problems, err := g.loadOperations(ctx, sources)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid operations: %w", errors.Join(problems...))
+	}
+
+	used := make(map[string]map[string]bool)
+	for _, operation := range document.Operations {
+		name := operation.Name
+		if name == "" {
+			name = operation.Position.Src.Name
+		}
+		visitor := usageVisitor{g: g, operation: name, used: used, fragments: make(map[string]bool)}
+		visitor.selectionSet(operation.SelectionSet)
+	}
+
+	var usages []FieldUsage
+	for i, fields := range g.schema.roots() {
+		for _, field := range sortedKeys(fields) {
+			usages = append(usages, newFieldUsage(rootNames[i], field, used))
+		}
+	}
+	for _, name := range sortedKeys(g.schema.Types) {
+		for _, field := range g.schema.Types[name].Definition.Fields {
+			usages = append(usages, newFieldUsage(name, field.Name, used))
+		}
+	}
+	return usages, nil
+}
+
+func newFieldUsage(typeName string, field string, used map[string]map[string]bool) FieldUsage {
+	return FieldUsage{Type: typeName, Field: field, Operations: sortedKeys(used[typeName+"."+field])}
+}
+
+// Collects the fields selected by one operation, following its fragment spreads once
+type usageVisitor struct {
+	g         *Generator
+	operation string
+	// Operations by Type.field
+	used      map[string]map[string]bool
+	fragments map[string]bool
+}
+
+func (v *usageVisitor) selectionSet(set ast.SelectionSet) {
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.ObjectDefinition != nil {
+				v.use(selection.ObjectDefinition.Name, selection.Name)
+				if selection.ObjectDefinition.Kind == ast.Interface {
+					for _, implementer := range v.g.schema.implementers(selection.ObjectDefinition.Name) {
+						v.use(implementer, selection.Name)
+					}
+				}
+			}
+			v.selectionSet(selection.SelectionSet)
+		case *ast.InlineFragment:
+			v.selectionSet(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if selection.Definition != nil && !v.fragments[selection.Name] {
+				v.fragments[selection.Name] = true
+				v.selectionSet(selection.Definition.SelectionSet)
+			}
+		}
+	}
+}
+
+func (v *usageVisitor) use(typeName string, field string) {
+	key := typeName + "." + field
+	if v.used[key] == nil {
+		v.used[key] = make(map[string]bool)
+	}
+	v.used[key][v.operation] = true
+}
+
+// DeprecatedUsage is a selection of a deprecated field, or a deprecated argument passed to a field,
+// in a client operation document
+type DeprecatedUsage struct {
+	Type  string
+	Field string
+	// Name of the deprecated argument, empty for the field itself
+	Argument string
+	Reason   string
+	// Operation or fragment containing the selection, e.g. query User or fragment UserFields
+	Definition string
+	File       string
+	Line       int
+	Column     int
+}
+
+// DeprecatedUsages returns every use of a deprecated field or argument in the operation documents,
+// sorted by document and position, so that deprecations can be retired once no client uses them.
+// The documents must be valid: the validation problems are returned as the error otherwise.
+func (g *Generator) DeprecatedUsages(ctx context.Context, sources []OperationSource) ([]DeprecatedUsage, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	document, problems, err := g.loadOperations(ctx, sources)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid operations: %w", errors.Join(problems...))
+	}
+
+	var usages []DeprecatedUsage
+	for _, operation := range document.Operations {
+		definition := string(operation.Operation)
+		if operation.Name != "" {
+			definition += " " + operation.Name
+		}
+		usages = appendDeprecatedUsages(usages, definition, operation.SelectionSet)
+	}
+	for _, fragment := range document.Fragments {
+		usages = appendDeprecatedUsages(usages, "fragment "+fragment.Name, fragment.SelectionSet)
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		a, b := usages[i], usages[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return usages, nil
+}
+
+// Append the deprecated fields and arguments of a selection set, without following fragment spreads,
+// as fragments are reported where they are defined
+func appendDeprecatedUsages(usages []DeprecatedUsage, definition string, set ast.SelectionSet) []DeprecatedUsage {
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Definition != nil && selection.ObjectDefinition != nil {
+				usage := DeprecatedUsage{
+					Type:       selection.ObjectDefinition.Name,
+					Field:      selection.Name,
+					Definition: definition,
+					File:       selection.Position.Src.Name,
+					Line:       selection.Position.Line,
+					Column:     selection.Position.Column,
+				}
+				if reason, deprecated := deprecationReason(selection.Definition.Directives); deprecated {
+					usage.Reason = reason
+					usages = append(usages, usage)
+				}
+				for _, arg := range selection.Arguments {
+					argDef := selection.Definition.Arguments.ForName(arg.Name)
+					if argDef == nil {
+						continue
+					}
+					if reason, deprecated := deprecationReason(argDef.Directives); deprecated {
+						argUsage := usage
+						argUsage.Argument, argUsage.Reason = arg.Name, reason
+						argUsage.Line, argUsage.Column = arg.Position.Line, arg.Position.Column
+						usages = append(usages, argUsage)
+					}
+				}
+			}
+			usages = appendDeprecatedUsages(usages, definition, selection.SelectionSet)
+		case *ast.InlineFragment:
+			usages = appendDeprecatedUsages(usages, definition, selection.SelectionSet)
+		}
+	}
+	return usages
+}
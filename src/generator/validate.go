@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+	"github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+// OperationSource is a client document of operations and fragments
+type OperationSource struct {
+	Name    string
+	Content string
+}
+
+// Rules of the GraphQL specification checked on the operations of all the documents together.
+// An anonymous operation must be the only operation of its own document instead.
+var operationRules = []validator.Rule{
+	rules.FieldsOnCorrectTypeRule,
+	rules.FragmentsOnCompositeTypesRule,
+	rules.KnownArgumentNamesRule,
+	rules.KnownDirectivesRule,
+	rules.KnownFragmentNamesRule,
+	rules.KnownRootTypeRule,
+	rules.KnownTypeNamesRule,
+	rules.NoFragmentCyclesRule,
+	rules.NoUndefinedVariablesRule,
+	rules.NoUnusedFragmentsRule,
+	rules.NoUnusedVariablesRule,
+	rules.OverlappingFieldsCanBeMergedRule,
+	rules.PossibleFragmentSpreadsRule,
+	rules.ProvidedRequiredArgumentsRule,
+	rules.ScalarLeafsRule,
+	rules.SingleFieldSubscriptionsRule,
+	rules.UniqueArgumentNamesRule,
+	rules.UniqueDirectivesPerLocationRule,
+	rules.UniqueFragmentNamesRule,
+	rules.UniqueInputFieldNamesRule,
+	rules.UniqueOperationNamesRule,
+	rules.UniqueVariableNamesRule,
+	rules.ValuesOfCorrectTypeRule,
+	rules.VariablesAreInputTypesRule,
+	rules.VariablesInAllowedPositionRule,
+}
+
+// ValidateOperations checks client operation documents against the merged schema, following the validation
+// rules of the GraphQL specification: unknown types and fields, arguments of the wrong type, undefined
+// or unused variables, and so on. The documents are validated together, so fragments can be defined in
+// another document than the operations spreading them, and operation names must be unique across documents. Each problem is returned as a PositionError
+// in its document; the returned error reports a schema that could not be loaded.
+func (g *Generator) ValidateOperations(ctx context.Context, sources []OperationSource) ([]error, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, problems, err := g.loadOperations(ctx, sources)
+	return problems, err
+}
+
+// Parse and validate operation documents into a single document, whose fields are bound to their definitions
+func (g *Generator) loadOperations(ctx context.Context, sources []OperationSource) (*ast.QueryDocument, []error, error) {
+	schema, err := g.validationSchema()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var problems []error
+	document := &ast.QueryDocument{}
+	for _, source := range sources {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		parsed, err := parser.ParseQuery(&ast.Source{Name: source.Name, Input: source.Content})
+		if err != nil {
+			problems = append(problems, operationError(source.Name, err))
+			continue
+		}
+		for _, err := range validator.Validate(schema, parsed, rules.LoneAnonymousOperationRule) {
+			problems = append(problems, operationError(source.Name, err))
+		}
+		document.Operations = append(document.Operations, parsed.Operations...)
+		document.Fragments = append(document.Fragments, parsed.Fragments...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	for _, err := range validator.Validate(schema, document, operationRules...) {
+		file, _ := err.Extensions["file"].(string)
+		problems = append(problems, operationError(file, err))
+	}
+	return document, problems, nil
+}
+
+// Load the merged schema with the types and interfaces indexed as required by the validation rules
+func (g *Generator) validationSchema() (*ast.Schema, error) {
+	var sdl bytes.Buffer
+	formatter.NewFormatter(&sdl).FormatSchema(g.mergedSchema())
+	schema, err := gqlparser.LoadSchema(g.directivesSource(sdl.String()), &ast.Source{
+		Name:  "merged schema",
+		Input: sdl.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load the merged schema: %v", err)
+	}
+	return schema, nil
+}
+
+// Convert an error of an operation document to a PositionError at its first location
+func operationError(file string, err error) error {
+	var gqlErr *gqlerror.Error
+	if !errors.As(err, &gqlErr) || len(gqlErr.Locations) == 0 || file == "" {
+		return err
+	}
+	return &PositionError{
+		File:    file,
+		Line:    gqlErr.Locations[0].Line,
+		Column:  gqlErr.Locations[0].Column,
+		Message: gqlErr.Message,
+	}
+}
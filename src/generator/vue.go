@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// Vue 3 GraphQL clients of the generated composables
+const (
+	VueUrql   = "urql"   // @urql/vue
+	VueVillus = "villus" // villus
+)
+
+// Module and query options type of each Vue client
+var vueClients = map[string]struct {
+	module       string
+	queryOptions string
+}{
+	VueUrql:   {"@urql/vue", "UseQueryArgs"},
+	VueVillus: {"villus", "QueryCompositeOptions"},
+}
+
+// Return the depth of the documents of the composables
+func (g *Generator) vueDepth() int {
+	if g.opts.VueDepth > 0 {
+		return g.opts.VueDepth
+	}
+	return DefaultOperationDepth
+}
+
+// Return the name of the types and document of an operation, e.g. ProjectsQuery
+func vueOperationName(op operationField) string {
+	return operationName(op.field) + op.root
+}
+
+// Check whether the selected root fields include queries and mutations
+func (g *Generator) vueOperationKinds() (queries bool, mutations bool) {
+	for _, op := range g.operations() {
+		queries = queries || op.root == "Query"
+		mutations = mutations || op.root == "Mutation"
+	}
+	return queries, mutations
+}
+
+// Generate the import of the composables of the Vue client
+func (g *Generator) writeVueImports(file *bufio.Writer) {
+	queries, mutations := g.vueOperationKinds()
+	var functions []string
+	if mutations {
+		functions = append(functions, "useMutation")
+	}
+	if queries {
+		functions = append(functions, "useQuery")
+	}
+	if len(functions) > 0 {
+		file.WriteString(fmt.Sprintf("import { %s } from '%s';\n\n", strings.Join(functions, ", "), vueClients[g.opts.Vue].module))
+	}
+}
+
+// Generate a composable for each Query and Mutation field, with the operation document selecting the field
+// and the types of its result and variables, and of its response with the ResponseTypes option.
+// Queries take the options of the client query, without the query.
+func (g *Generator) writeVueComposables(file *bufio.Writer) {
+	client := vueClients[g.opts.Vue]
+	for _, op := range g.operations() {
+		name := vueOperationName(op)
+		built := g.buildOperation(op, g.vueDepth())
+		file.WriteString(fmt.Sprintf("export const %sDocument = `%s`;\n\n", name, built.document))
+		file.WriteString(fmt.Sprintf("export interface %s {\n%s}\n\n", name, built.result))
+		if g.opts.ResponseTypes {
+			g.useHelper("GraphQLResponse")
+			file.WriteString(fmt.Sprintf("export type %sResponse = GraphQLResponse<%s>;\n\n", name, name))
+		}
+
+		required := false
+		if len(built.variables) == 0 {
+			file.WriteString(fmt.Sprintf("export type %sVariables = Record<string, never>;\n\n", name))
+		} else {
+			g.writeArgsInterface(file, name+"Variables", built.variables)
+			for _, variable := range built.variables {
+				required = required || variable.Type.NonNull
+			}
+		}
+
+		types := fmt.Sprintf("%s, %sVariables", name, name)
+		if op.root == "Mutation" {
+			file.WriteString(fmt.Sprintf("export function use%s() {\n  return useMutation<%s>(%sDocument);\n}\n\n", name, types, name))
+			continue
+		}
+		g.useImport(importedType{tsType: client.queryOptions, module: client.module})
+		optionsType := fmt.Sprintf("%s<%s>", client.queryOptions, types)
+		defaultOptions := " = {}"
+		if required {
+			defaultOptions = ""
+		}
+		file.WriteString(fmt.Sprintf("export function use%s(options: Omit<%s, 'query'>%s) {\n", name, optionsType, defaultOptions))
+		file.WriteString(fmt.Sprintf("  return useQuery<%s>({ ...options, query: %sDocument } as %s);\n}\n\n", types, name, optionsType))
+	}
+}
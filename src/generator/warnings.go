@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Warning codes
+const (
+	WarnUnmappedScalar = "unmapped-scalar"
+	WarnUploadOutput   = "upload-output"
+	WarnUnresolvedType = "unresolved-type"
+)
+
+// Warning is a non-fatal problem found in the schema
+type Warning struct {
+	Code    string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("warning [%s]: %s", w.Code, w.Message)
+}
+
+// Warnings analyzes the merged schema and returns all non-fatal problems, sorted by code and subject
+func (g *Generator) Warnings() []Warning {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	warnings := append(g.unmappedScalarWarnings(), g.uploadOutputWarnings()...)
+	return append(warnings, g.unresolvedTypeWarnings()...)
+}
+
+// Report every custom scalar without a TypeScript mapping, together with the fields using it
+func (g *Generator) unmappedScalarWarnings() []Warning {
+	usages := make(map[string][]string)
+	g.forEachField(func(owner string, field *ast.FieldDefinition) {
+		name := field.Type.Name()
+		if _, declared := g.schema.Scalars[name]; !declared {
+			return
+		}
+		if _, mapped := g.scalarType(name); mapped {
+			return
+		}
+		if _, overridden := g.fieldTypeOverride(owner, field.Name); overridden {
+			return
+		}
+		usages[name] = append(usages[name], fmt.Sprintf("%s.%s (%s)", owner, field.Name, formatPosition(field.Position)))
+	})
+
+	var warnings []Warning
+	for _, name := range sortedKeys(usages) {
+		warnings = append(warnings, Warning{
+			Code:    WarnUnmappedScalar,
+			Message: fmt.Sprintf("scalar %s has no TypeScript mapping and is emitted as-is; used by %s", name, strings.Join(usages[name], ", ")),
+		})
+	}
+	return warnings
+}
+
+// Call fn for every emitted field, in output order
+func (g *Generator) forEachField(fn func(owner string, field *ast.FieldDefinition)) {
+	selected := g.selectedTypes()
+	for _, name := range sortedKeys(g.schema.Types) {
+		if selected != nil && !selected[name] {
+			continue
+		}
+		for _, field := range g.schema.Types[name].Definition.Fields {
+			fn(name, field)
+		}
+	}
+	for i, fields := range g.schema.roots() {
+		for _, name := range sortedKeys(fields) {
+			if g.rootFieldSelected(rootNames[i], name) {
+				fn(rootNames[i], fields[name])
+			}
+		}
+	}
+}
+
+// Report every response field of the Upload scalar, which are left out of the output
+func (g *Generator) uploadOutputWarnings() []Warning {
+	var warnings []Warning
+	g.forEachField(func(owner string, field *ast.FieldDefinition) {
+		if g.isUploadOutput(owner, field) {
+			warnings = append(warnings, Warning{
+				Code:    WarnUploadOutput,
+				Message: fmt.Sprintf("%s.%s (%s) returns Upload, which only exists in inputs and arguments, and is omitted", owner, field.Name, formatPosition(field.Position)),
+			})
+		}
+	})
+	return warnings
+}
+
+// Report every emitted field referencing a type that is not generated, which is emitted as unknown
+func (g *Generator) unresolvedTypeWarnings() []Warning {
+	usages := make(map[string][]string)
+	g.forEachField(func(owner string, field *ast.FieldDefinition) {
+		name := field.Type.Name()
+		if _, scalar := g.scalarType(name); scalar || g.isResolved(name) {
+			return
+		}
+		if _, overridden := g.fieldTypeOverride(owner, field.Name); overridden {
+			return
+		}
+		usages[name] = append(usages[name], fmt.Sprintf("%s.%s (%s)", owner, field.Name, formatPosition(field.Position)))
+	})
+
+	var warnings []Warning
+	for _, name := range sortedKeys(usages) {
+		warnings = append(warnings, Warning{
+			Code:    WarnUnresolvedType,
+			Message: fmt.Sprintf("type %s is not generated and is emitted as unknown; used by %s", name, strings.Join(usages[name], ", ")),
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,172 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Import of the Zod library, used by the input schemas
+const zodImport = "import { z } from 'zod';\n\n"
+
+// Zod schemas of the TypeScript types of scalars, other types are checked by the compiler only with z.custom
+var zodScalars = map[string]string{
+	"string":  "z.string()",
+	"number":  "z.number()",
+	"boolean": "z.boolean()",
+	"bigint":  "z.bigint()",
+	"Date":    "z.date()",
+	"unknown": "z.unknown()",
+	"any":     "z.any()",
+}
+
+// Zod methods of the @constraint arguments of strings and numbers, in the order of the refinements
+var (
+	zodStringConstraints = []struct{ argument, method string }{
+		{"minLength", "min"},
+		{"maxLength", "max"},
+		{"startsWith", "startsWith"},
+		{"endsWith", "endsWith"},
+		{"contains", "includes"},
+	}
+	zodNumberConstraints = []struct{ argument, method string }{
+		{"min", "gte"},
+		{"max", "lte"},
+		{"exclusiveMin", "gt"},
+		{"exclusiveMax", "lt"},
+		{"multipleOf", "multipleOf"},
+	}
+)
+
+// Zod refinements of the string formats of @constraint
+var zodFormats = map[string]string{
+	"email":     ".email()",
+	"uri":       ".url()",
+	"uuid":      ".uuid()",
+	"date-time": ".datetime({ offset: true })",
+	"ipv4":      ".ip({ version: 'v4' })",
+	"ipv6":      ".ip({ version: 'v6' })",
+}
+
+// Return the name of the Zod schema of an input type
+func (g *Generator) zodSchemaName(name string) string {
+	return g.tsName(name) + "Schema"
+}
+
+// Generate a Zod schema validating each input type, e.g. `export const CreateUserInputSchema: z.ZodType<CreateUserInput>`.
+// The @constraint arguments of the fields become refinements, such as minLength to .min() and pattern to .regex().
+func (g *Generator) writeZodSchemas(file *bufio.Writer, selected map[string]bool) {
+	for _, name := range orderedKeys(g, g.schema.Inputs, "") {
+		if selected != nil && !selected[name] {
+			continue
+		}
+		input := g.schema.Inputs[name]
+		file.WriteString(fmt.Sprintf("export const %s: z.ZodType<%s> = z.object({\n", g.zodSchemaName(name), g.tsName(name)))
+		for _, field := range input.Fields {
+			file.WriteString(fmt.Sprintf("  %s: %s,\n", g.propertyName(field), g.zodField(name, field)))
+		}
+		file.WriteString("});\n\n")
+	}
+}
+
+// Return the Zod schema of an input field: its type with the refinements of its @constraint directive,
+// minItems and maxItems applying to the outermost list, accepting null and undefined when the field is nullable
+func (g *Generator) zodField(owner string, field *ast.FieldDefinition) string {
+	constraint := field.Directives.ForName("constraint")
+	var schema string
+	if override, found := g.fieldTypeOverride(owner, field.Name); found {
+		schema = "z.custom<" + override.tsType + ">()"
+	} else {
+		schema = g.zodType(field.Type, constraint)
+		if constraint != nil && field.Type.Elem != nil {
+			schema += zodRefinement(constraint, "minItems", "min") + zodRefinement(constraint, "maxItems", "max")
+		}
+	}
+	if !field.Type.NonNull {
+		schema += ".nullish()"
+	}
+	return schema
+}
+
+// Return the Zod schema of a type without its nullability, with the string and number constraints refining
+// the items of lists. List items are nullable with the codegen preset only, like their TypeScript type.
+func (g *Generator) zodType(typ *ast.Type, constraint *ast.Directive) string {
+	if typ.Elem != nil {
+		item := g.zodType(typ.Elem, constraint)
+		if g.codegen() && !typ.Elem.NonNull {
+			item += ".nullable()"
+		}
+		return "z.array(" + item + ")"
+	}
+
+	name := typ.Name()
+	if enum, found := g.schema.Enums[name]; found {
+		if !g.isUnionEnum(enum) {
+			g.useValue(name)
+			return "z.nativeEnum(" + g.tsName(name) + ")"
+		}
+		if len(enum.EnumValues) == 0 {
+			return "z.never()"
+		}
+		values := make([]string, len(enum.EnumValues))
+		for i, value := range enum.EnumValues {
+			values[i] = quoteString(value.Name)
+		}
+		return "z.enum([" + strings.Join(values, ", ") + "])"
+	}
+	if _, found := g.schema.Inputs[name]; found {
+		if !g.isResolved(name) {
+			return "z.unknown()"
+		}
+		return "z.lazy(() => " + g.zodSchemaName(name) + ")"
+	}
+
+	tsType, found := g.scalarType(name)
+	if !found {
+		return "z.unknown()"
+	}
+	schema, found := zodScalars[tsType]
+	if !found {
+		return "z.custom<" + tsType + ">()"
+	}
+	if name == "Int" {
+		schema += ".int()"
+	}
+	if constraint == nil {
+		return schema
+	}
+	switch tsType {
+	case "string":
+		for _, c := range zodStringConstraints {
+			schema += zodRefinement(constraint, c.argument, c.method)
+		}
+		if arg := constraint.Arguments.ForName("notContains"); arg != nil {
+			schema += ".refine((value) => !value.includes(" + quoteString(arg.Value.Raw) + "))"
+		}
+		if arg := constraint.Arguments.ForName("pattern"); arg != nil {
+			schema += ".regex(new RegExp(" + quoteString(arg.Value.Raw) + "))"
+		}
+		if arg := constraint.Arguments.ForName("format"); arg != nil {
+			schema += zodFormats[arg.Value.Raw]
+		}
+	case "number":
+		for _, c := range zodNumberConstraints {
+			schema += zodRefinement(constraint, c.argument, c.method)
+		}
+	}
+	return schema
+}
+
+// Return the call of a Zod method with the value of a @constraint argument, nothing if the argument is not set
+func zodRefinement(constraint *ast.Directive, argument string, method string) string {
+	arg := constraint.Arguments.ForName(argument)
+	if arg == nil {
+		return ""
+	}
+	if arg.Value.Kind == ast.StringValue || arg.Value.Kind == ast.BlockValue {
+		return "." + method + "(" + quoteString(arg.Value.Raw) + ")"
+	}
+	return "." + method + "(" + arg.Value.Raw + ")"
+}
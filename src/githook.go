@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Marker identifying the pre-commit hooks written by install-hook, which may be overwritten
+const hookMarker = "# graphql-ts-generator pre-commit hook"
+
+func registerInstallHookFlags(flags *flag.FlagSet) (*bool, *string) {
+	return flags.Bool("force", false, "Overwrite an existing pre-commit hook not written by install-hook"),
+		flags.String("command", "npx generate-types", "Command running the generator in the hook")
+}
+
+// Write a git pre-commit hook checking that the generated output is up to date when schema files
+// are staged. The arguments after the install-hook flags are passed to the generator.
+func runInstallHook(args []string) {
+	flags := flag.NewFlagSet("install-hook", flag.ExitOnError)
+	force, command := registerInstallHookFlags(flags)
+	flags.Parse(args)
+	generateArgs := flags.Args()
+
+	generateFlags := flag.NewFlagSet("generate", flag.ExitOnError)
+	schemaOpts := registerSchemaFlags(generateFlags)
+	registerGenerateFlags(generateFlags)
+	if err := parseFlags(generateFlags, generateArgs); err != nil {
+		log.Fatal(err)
+	}
+
+	path, err := installHook(*command, generateArgs, hookPathspecs(schemaOpts, configPath(generateArgs)), *force)
+	if err != nil {
+		log.Fatalf("Error installing pre-commit hook: %v", err)
+	}
+	schemaOpts.console.printSuccess("Pre-commit hook installed: %s", path)
+}
+
+// Return the git pathspecs of the schema files and the config file, relative to the working directory.
+// Schemas downloaded from a URL or a registry have none, so the hook always runs.
+func hookPathspecs(schemaOpts *schemaFlags, config string) []string {
+	if schemaOpts.registry != "" || isURL(schemaOpts.inputDir) {
+		return nil
+	}
+	extensions := append([]string{".graphql"}, splitList(schemaOpts.sourceExtensions)...)
+	var pathspecs []string
+	for _, extension := range extensions {
+		pathspecs = append(pathspecs, filepath.ToSlash(filepath.Join(schemaOpts.inputDir, "*"+extension)))
+	}
+	if config != "" {
+		pathspecs = append(pathspecs, filepath.ToSlash(config))
+	}
+	return pathspecs
+}
+
+// Write the pre-commit hook of the repository of the working directory and return its path
+func installHook(command string, generateArgs []string, pathspecs []string, force bool) (string, error) {
+	hooksDir, err := gitOutput("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	prefix, err := gitOutput("rev-parse", "--show-prefix")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(hooksDir, "pre-commit")
+	if existing, err := os.ReadFile(path); err == nil && !bytes.Contains(existing, []byte(hookMarker)) && !force {
+		return "", fmt.Errorf("%s already exists, run with -force to overwrite it", path)
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, []byte(preCommitHook(command, generateArgs, pathspecs, prefix)), 0755)
+}
+
+// Return the hook script. It runs from the directory install-hook was run from,
+// so that the pathspecs and the generator arguments keep their meaning.
+func preCommitHook(command string, generateArgs []string, pathspecs []string, dir string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n" + hookMarker + ", written by generate-types install-hook.\n")
+	b.WriteString("# Fails the commit when the generated output is out of date with the schema changes.\n")
+	if dir != "" {
+		fmt.Fprintf(&b, "cd %s || exit 1\n", shellQuote(dir))
+	}
+	if len(pathspecs) > 0 {
+		quoted := make([]string, len(pathspecs))
+		for i, pathspec := range pathspecs {
+			quoted[i] = shellQuote(pathspec)
+		}
+		fmt.Fprintf(&b, "if git diff --cached --quiet -- %s; then\n  exit 0\nfi\n", strings.Join(quoted, " "))
+	}
+	quoted := make([]string, len(generateArgs))
+	for i, arg := range generateArgs {
+		quoted[i] = shellQuote(arg)
+	}
+	fmt.Fprintf(&b, "%s %s || {\n", command, strings.Join(append([]string{"-check"}, quoted...), " "))
+	b.WriteString("  echo \"Generated output is out of date: run the generator and stage the result\" >&2\n  exit 1\n}\n")
+	return b.String()
+}
+
+// Run a git command in the working directory and return its trimmed output
+func gitOutput(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %v %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
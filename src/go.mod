@@ -2,7 +2,6 @@ module graphql-ts-generator
 
 go 1.21.0
 
-require (
-	github.com/agnivade/levenshtein v1.1.1 // indirect
-	github.com/vektah/gqlparser/v2 v2.5.17 // indirect
-)
+require github.com/vektah/gqlparser/v2 v2.5.17
+
+require github.com/agnivade/levenshtein v1.1.1 // indirect
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Run a shell command with the given paths appended as arguments
+func runHook(command string, paths []string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		quoted := make([]string, len(paths))
+		for i, path := range paths {
+			quoted[i] = `"` + path + `"`
+		}
+		cmd = exec.Command("cmd", "/C", command+" "+strings.Join(quoted, " "))
+	} else {
+		cmd = exec.Command("sh", append([]string{"-c", command + ` "$@"`, "sh"}, paths...)...)
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook %q failed: %v", command, err)
+	}
+	return nil
+}
+
+// Update the content hash of an output file changed by a hook, such as a formatter,
+// so that the change is not reported as a manual edit on the next run.
+// The file is written back with the configured encoding.
+func restampContentHash(path string, opts outputOptions) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	generated, regions, err := splitCustomRegions(path, decodeOutput(data))
+	if err != nil {
+		return err
+	}
+	firstLine, rest, _ := bytes.Cut(generated, []byte("\n"))
+	if !strings.HasPrefix(string(firstLine), contentHashPrefix) {
+		return nil
+	}
+
+	content := append([]byte(contentHashLine(rest)), rest...)
+	content = encodeOutput(append(content, regions...), opts)
+	if bytes.Equal(content, data) {
+		return nil
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// Run the post-generation hook on a generated file moved next to the output under a name with the same extension,
+// so that formatters pick the same parser and configuration, and return the hash of the resulting content
+func hookedOutputHash(outputPath string, generatedPath string, opts outputOptions) (string, error) {
+	ext := filepath.Ext(outputPath)
+	copy, err := os.CreateTemp(filepath.Dir(outputPath), strings.TrimSuffix(filepath.Base(outputPath), ext)+".check*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("could not create file: %v", err)
+	}
+	copy.Close()
+	defer os.Remove(copy.Name())
+	if err := os.Rename(generatedPath, copy.Name()); err != nil {
+		return "", fmt.Errorf("could not create file: %v", err)
+	}
+
+	if err := runHook(opts.postHook, []string{copy.Name()}); err != nil {
+		return "", err
+	}
+	if err := restampContentHash(copy.Name(), opts); err != nil {
+		return "", fmt.Errorf("could not update content hash: %v", err)
+	}
+	data, err := os.ReadFile(copy.Name())
+	if err != nil {
+		return "", err
+	}
+	return hashContent(data), nil
+}
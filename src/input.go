@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"graphql-ts-generator/generator"
+)
+
+// Flags shared by every command that loads schema files
+type schemaFlags struct {
+	inputDir          string
+	skipChecks        bool
+	skipChecksTypes   string
+	conflicts         string
+	typeConflicts     mapFlag
+	debug             bool
+	scalars           mapFlag
+	renames           mapFlag
+	fieldTypes        mapFlag
+	strictScalars     bool
+	lintRules         mapFlag
+	forbiddenPrefixes string
+	internalDirective string
+	tags              string
+	bigInt            string
+	json              string
+	jsonObject        string
+	timeout           time.Duration
+	logFile           string
+	sourceExtensions  string
+	gqlTags           string
+	inputChecksum     string
+	registry          string
+	registryGraph     string
+	registryVersion   string
+	registryKey       string
+	registryEndpoint  string
+	remoteCacheDir    string
+	remoteCacheTTL    time.Duration
+	offline           bool
+	failOnWarn        optionalListFlag
+	// HTTP client settings of the schema downloads
+	http httpSettings
+	// Schemas loaded from URLs and registries, downloaded once per run
+	sources map[string][]byte
+	// Console of the command, receiving the messages and the debug output
+	console *console
+}
+
+func registerSchemaFlags(flags *flag.FlagSet) *schemaFlags {
+	f := &schemaFlags{
+		scalars: mapFlag{}, renames: mapFlag{}, fieldTypes: mapFlag{}, lintRules: mapFlag{}, typeConflicts: mapFlag{},
+		sources: make(map[string][]byte), console: &console{},
+	}
+	flags.String("config", "", "Path to a JSON config file with flag names as keys; command-line flags take precedence")
+	flags.String("profile", "", "Name of the config file profile applied on top of the base options")
+	flags.String(projectKey, "", "Name of the config file project to generate, applied on top of the base options")
+	flags.Bool(allProjectsFlag, false, "Generate every project of the config file")
+	flags.StringVar(&f.inputDir, "input", "./schemas", "Directory with GraphQL schemas, or the http(s) URL of a schema file")
+	flags.StringVar(&f.inputChecksum, "input-checksum", "", "Expected sha256 checksum of the schema downloaded from an -input URL, as sha256:<hex>")
+	flags.StringVar(&f.registry, "registry", "", "Load the schema from a registry instead of -input: apollo or hive")
+	flags.StringVar(&f.registryGraph, "registry-graph", "", "Apollo graph ref, as graph@variant, or Hive target id of the -registry schema")
+	flags.StringVar(&f.registryVersion, "registry-version", "", "Pinned -registry schema: Apollo schema hash or Hive version id, the latest version if empty")
+	flags.StringVar(&f.registryKey, "registry-key", "", "API key of the -registry, APOLLO_KEY or HIVE_CDN_KEY if empty")
+	flags.StringVar(&f.registryEndpoint, "registry-endpoint", "", "URL of the -registry API, e.g. for a self-hosted Hive CDN")
+	flags.DurationVar(&f.http.timeout, "http-timeout", downloadTimeout, "Time limit of each request downloading an -input URL or -registry schema")
+	flags.IntVar(&f.http.retries, "http-retries", 2, "Retries of a schema download after a network error, a 429 or a 5xx response")
+	flags.DurationVar(&f.http.backoff, "http-retry-backoff", 500*time.Millisecond, "Delay before the first retry of a schema download, doubled for each of the next ones")
+	flags.StringVar(&f.http.proxy, "http-proxy", "", "Proxy URL of schema downloads, HTTP_PROXY, HTTPS_PROXY and NO_PROXY if empty")
+	flags.StringVar(&f.http.caCert, "http-ca-cert", "", "PEM file of certificate authorities trusted by schema downloads, in addition to the system ones")
+	flags.BoolVar(&f.http.insecure, "http-insecure", false, "Skip the verification of TLS certificates of schema downloads, e.g. for a staging endpoint")
+	flags.StringVar(&f.remoteCacheDir, "remote-cache", "", "Directory caching the schemas downloaded from an -input URL or a -registry (disabled if empty)")
+	flags.DurationVar(&f.remoteCacheTTL, "remote-cache-ttl", time.Hour, "How long a schema in the -remote-cache is used before it is downloaded again")
+	flags.BoolVar(&f.offline, "offline", false, "Load the -input URL or -registry schema from the -remote-cache whatever its age, without network access")
+	flags.StringVar(&f.sourceExtensions, "source-extensions", "", "Comma-separated extensions of JavaScript or TypeScript sources in the input directory whose gql tagged templates are added to the schema, e.g. .ts,.tsx")
+	flags.StringVar(&f.gqlTags, "gql-tags", "gql", "Comma-separated template tags holding SDL in the sources scanned with -source-extensions")
+	flags.BoolVar(&f.skipChecks, "skipChecks", false, "Skip type mismatch checks")
+	flags.StringVar(&f.skipChecksTypes, "skip-checks-types", "", "Comma-separated type names whose mismatching definitions are ignored, keeping the first one")
+	flags.StringVar(&f.conflicts, "conflicts", "", "Strategy for types declared differently in several files: error, first-wins, last-wins or merge-fields (error, or first-wins with -skipChecks)")
+	flags.Var(f.typeConflicts, "type-conflict", "Set the conflict strategy of a type, as Type=strategy (repeatable)")
+	flags.BoolVar(&f.debug, "debug", false, "Print debug log")
+	flags.String("cpuprofile", "", "Write a CPU profile of the run to this file")
+	flags.String("memprofile", "", "Write a heap profile to this file at the end of the run")
+	flags.StringVar(&f.logFile, "log-file", "", "Write the debug output to this file instead of the console")
+	flags.BoolVar(&f.console.noColor, "no-color", false, "Disable colored output, also disabled by the NO_COLOR environment variable or when not writing to a terminal")
+	flags.Var(f.scalars, "scalar", "Map a GraphQL scalar to a TypeScript type, as Name=Type or Name=./module#Type; Name may be a pattern such as * for the other custom scalars (repeatable)")
+	flags.Var(f.renames, "rename", "Rename a GraphQL type in the output, as GraphQLName=TsName (repeatable)")
+	flags.Var(f.fieldTypes, "field-type", "Override the TypeScript type of a field, as Type.field=TsType or Type.field=./module#TsType (repeatable)")
+	flags.BoolVar(&f.strictScalars, "strict-scalars", false, "Fail generation when a scalar has no TypeScript mapping")
+	flags.Var(&f.failOnWarn, "fail-on-warn", "Fail on warnings and lint warnings, or only on those of the given comma-separated codes, e.g. -fail-on-warn=unmapped-scalar,orphan-types")
+	flags.Var(f.lintRules, "lint-rule", "Set the severity of a lint rule, as rule=off|warn|error (repeatable)")
+	flags.StringVar(&f.forbiddenPrefixes, "forbidden-prefixes", "", "Comma-separated type name prefixes reported by the forbidden-prefixes lint rule")
+	flags.StringVar(&f.internalDirective, "internal-directive", "internal", "Directive hiding types and fields from the output")
+	flags.StringVar(&f.tags, "tags", "", "Comma-separated @tag names; only tagged types and fields, and the types they reference, are kept")
+	flags.StringVar(&f.bigInt, "bigint", "bigint", "TypeScript type of the BigInt and Long scalars: bigint or string")
+	flags.StringVar(&f.json, "json", generator.JSONUnknown, "TypeScript type of the JSON scalar: unknown, any or record (Record<string, unknown>)")
+	flags.StringVar(&f.jsonObject, "json-object", generator.JSONRecord, "TypeScript type of the JSONObject scalar: record (Record<string, unknown>), unknown or any")
+	flags.DurationVar(&f.timeout, "timeout", 0, "Abort generation after this duration (e.g. 30s), 0 disables the limit")
+	return f
+}
+
+// Parse the command flags, using the config file values as defaults.
+// Overrides are applied on top of the config file, such as the options of one output of a multi-output config.
+func parseFlags(flags *flag.FlagSet, args []string, overrides ...map[string]any) error {
+	if err := applyConfigFile(flags, args); err != nil {
+		return fmt.Errorf("Error loading config: %v", err)
+	}
+	for _, values := range overrides {
+		if err := applyConfigValues(flags, values); err != nil {
+			return fmt.Errorf("Error loading config: %v", err)
+		}
+	}
+	return flags.Parse(args)
+}
+
+// Create the command context, cancelled on Ctrl+C or when the timeout expires
+func (f *schemaFlags) context() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if f.timeout <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// Collect all .graphql files from the input directory, and the sources scanned for tagged templates.
+// An input URL or registry schema is downloaded and checked against the checksum, if any.
+func (f *schemaFlags) collectFiles(ctx context.Context) ([]string, error) {
+	if f.registry != "" || isURL(f.inputDir) {
+		path, err := f.downloadSchema(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading schema: %v", err)
+		}
+		return []string{path}, nil
+	}
+	if f.inputChecksum != "" {
+		return nil, errors.New("-input-checksum requires an -input URL or a -registry")
+	}
+
+	// Check if input directory exists
+	if _, err := os.Stat(f.inputDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("Input directory does not exist: %s", f.inputDir)
+	}
+
+	var files []string
+	err := filepath.Walk(f.inputDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && info.Name() == "node_modules" && f.sourceExtensions != "" {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && (strings.HasSuffix(info.Name(), ".graphql") || f.isSourceFile(path)) {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Error processing schema files: %v", err)
+	}
+	return files, nil
+}
+
+// Create a generator configured from the flags, followed by command specific options
+func (f *schemaFlags) newGenerator(extra ...generator.Option) (*generator.Generator, error) {
+	if err := f.openLogFile(); err != nil {
+		return nil, err
+	}
+	if f.bigInt != "bigint" && f.bigInt != "string" {
+		return nil, fmt.Errorf("Invalid -bigint value %q: expected bigint or string", f.bigInt)
+	}
+	if f.json != generator.JSONUnknown && f.json != generator.JSONAny && f.json != generator.JSONRecord {
+		return nil, fmt.Errorf("Invalid -json value %q: expected unknown, any or record", f.json)
+	}
+	if f.jsonObject != generator.JSONUnknown && f.jsonObject != generator.JSONAny && f.jsonObject != generator.JSONRecord {
+		return nil, fmt.Errorf("Invalid -json-object value %q: expected record, unknown or any", f.jsonObject)
+	}
+	opts := []generator.Option{
+		generator.WithSkipChecks(f.skipChecks),
+		generator.WithSkipChecksTypes(splitList(f.skipChecksTypes)...),
+		generator.WithConflicts(f.conflicts),
+		generator.WithStrictScalars(f.strictScalars),
+		generator.WithInternalDirective(f.internalDirective),
+		generator.WithTags(splitList(f.tags)...),
+		generator.WithBigInt(f.bigInt),
+		generator.WithJSON(f.json),
+		generator.WithJSONObject(f.jsonObject),
+	}
+	for name, tsType := range f.scalars {
+		opts = append(opts, generator.WithScalar(name, tsType))
+	}
+	for name, strategy := range f.typeConflicts {
+		opts = append(opts, generator.WithTypeConflict(name, strategy))
+	}
+	for name, tsName := range f.renames {
+		opts = append(opts, generator.WithRename(name, tsName))
+	}
+	for field, tsType := range f.fieldTypes {
+		opts = append(opts, generator.WithFieldType(field, tsType))
+	}
+	for rule, name := range f.lintRules {
+		if !generator.IsLintRule(rule) {
+			return nil, fmt.Errorf("Unknown lint rule: %s", rule)
+		}
+		severity, err := generator.ParseSeverity(name)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid lint rule %s: %v", rule, err)
+		}
+		opts = append(opts, generator.WithLintRule(rule, severity))
+	}
+	if prefixes := splitList(f.forbiddenPrefixes); len(prefixes) > 0 {
+		opts = append(opts, generator.WithForbiddenPrefixes(prefixes...))
+	}
+	if w := f.debugOutput(); w != nil {
+		opts = append(opts, generator.WithDebugLog(w))
+	}
+	return generator.NewGenerator(append(opts, extra...)...), nil
+}
+
+// Create the generator and load the schema files into it, for the commands reading the schema
+func (f *schemaFlags) loadSchema(ctx context.Context) (*generator.Generator, error) {
+	gen, err := f.newGenerator()
+	if err != nil {
+		return nil, err
+	}
+	files, err := f.collectFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return gen, f.loadSchemaFiles(ctx, gen, files, nil)
+}
+
+// Whether the file is a JavaScript or TypeScript source scanned for tagged templates
+func (f *schemaFlags) isSourceFile(path string) bool {
+	for _, extension := range splitList(f.sourceExtensions) {
+		if strings.HasSuffix(path, extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add all schema files to the generator.
+// Every file is processed even if some fail, and every conflicting definition of a file is reported,
+// so all errors are reported in a single run.
+// Sources only add the SDL of their tagged templates, and are skipped when they have none.
+func (f *schemaFlags) loadSchemaFiles(ctx context.Context, gen *generator.Generator, files []string, hashes map[string]string) error {
+	var errs []error
+	for _, path := range files {
+		fmt.Fprintf(f.console.progressOutput(), "Processing file: %s\n", path)
+		data, err := f.readSource(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		content := string(data)
+		if f.isSourceFile(path) {
+			if content = generator.ExtractTaggedSDL(content, splitList(f.gqlTags)); strings.TrimSpace(content) == "" {
+				continue
+			}
+		}
+		if err := gen.AddSource(ctx, path, content, hashes[path]); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("Error processing schema files: %v", err)
+			}
+			errs = append(errs, unjoin(err)...)
+		}
+	}
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			f.console.printError(err)
+		}
+		return fmt.Errorf("Error processing schema files: %d error(s) found", len(errs))
+	}
+	return nil
+}
+
+// Split an error joining several errors, such as the conflicts of a schema file, into its errors
+func unjoin(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// Print lint issues and return the number of errors among them, and of the warnings failing the run with -fail-on-warn.
+// The code of a lint warning is the name of its rule.
+func (f *schemaFlags) reportLint(gen *generator.Generator) (int, int) {
+	errorCount, failingCount := 0, 0
+	for _, issue := range gen.Lint() {
+		f.console.printLintIssue(issue)
+		if issue.Severity == generator.SeverityError {
+			errorCount++
+		} else if issue.Severity == generator.SeverityWarning && f.failOnWarn.includes(issue.Rule) {
+			failingCount++
+		}
+	}
+	return errorCount, failingCount
+}
+
+// Print the schema warnings and return the number of those failing the run with -fail-on-warn
+func (f *schemaFlags) reportWarnings(gen *generator.Generator) int {
+	failingCount := 0
+	for _, warning := range gen.Warnings() {
+		f.console.printWarning(warning)
+		if f.failOnWarn.includes(warning.Code) {
+			failingCount++
+		}
+	}
+	return failingCount
+}
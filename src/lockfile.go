@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Bump when the lockfile layout changes
+const lockfileVersion = 1
+
+// Lockfile pinning the schema sources and the output files generated from them.
+// Unlike the cache it is meant to be committed, so that a change of the schema
+// without regenerating can be caught in review or CI.
+type Lockfile struct {
+	Version int               `json:"version"`
+	Sources map[string]string `json:"sources"` // schema file path -> sha256 of its content
+	Outputs map[string]string `json:"outputs"` // output file path -> sha256 of its content
+}
+
+// Create the lockfile of a generation run from the schema file hashes and the written output files
+func newLockfile(hashes map[string]string, outputPaths []string) (*Lockfile, error) {
+	lock := &Lockfile{Version: lockfileVersion, Sources: make(map[string]string), Outputs: make(map[string]string)}
+	for path, hash := range hashes {
+		lock.Sources[filepath.ToSlash(path)] = "sha256:" + hash
+	}
+	outputs, err := hashOutputs(outputPaths)
+	if err != nil {
+		return nil, err
+	}
+	for path, hash := range outputs {
+		lock.Outputs[filepath.ToSlash(path)] = "sha256:" + hash
+	}
+	return lock, nil
+}
+
+func loadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read lockfile %s: %v", path, err)
+	}
+	var lock Lockfile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("could not parse lockfile %s: %v", path, err)
+	}
+	if lock.Version != lockfileVersion {
+		return nil, fmt.Errorf("lockfile %s has unsupported version %d", path, lock.Version)
+	}
+	return &lock, nil
+}
+
+func (l *Lockfile) save(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("could not write lockfile %s: %v", path, err)
+	}
+	return nil
+}
+
+// Check that the schema files still match the lockfile and that the locked outputs were
+// not changed since. Returns an error listing every difference.
+func (l *Lockfile) verify(hashes map[string]string) error {
+	current, err := newLockfile(hashes, nil)
+	if err != nil {
+		return err
+	}
+	for path := range l.Outputs {
+		if data, err := os.ReadFile(filepath.FromSlash(path)); err == nil {
+			current.Outputs[path] = "sha256:" + hashContent(data)
+		}
+	}
+
+	var diffs []string
+	diffs = append(diffs, diffHashes("schema file", l.Sources, current.Sources)...)
+	diffs = append(diffs, diffHashes("output file", l.Outputs, current.Outputs)...)
+	if len(diffs) > 0 {
+		return fmt.Errorf("sources changed without regenerating:\n  %s", strings.Join(diffs, "\n  "))
+	}
+	return nil
+}
+
+// Describe the entries that were added, removed or changed between two hash maps
+func diffHashes(kind string, locked, current map[string]string) []string {
+	var diffs []string
+	for path, hash := range current {
+		switch locked[path] {
+		case "":
+			diffs = append(diffs, fmt.Sprintf("%s %s is not in the lockfile", kind, path))
+		case hash:
+		default:
+			diffs = append(diffs, fmt.Sprintf("%s %s changed", kind, path))
+		}
+	}
+	for path := range locked {
+		if _, ok := current[path]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s %s is missing", kind, path))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
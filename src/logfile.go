@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Open the log file, if any. Fatal errors, warnings and messages are copied to it,
+// so that the log shows why a run failed. Fatal errors are colored like the other messages of the console.
+func (f *schemaFlags) openLogFile() error {
+	if f.logFile == "" {
+		log.SetOutput(errorWriter{os.Stderr, f.console})
+		return nil
+	}
+	if f.console.logFile != nil {
+		return nil
+	}
+	file, err := os.Create(f.logFile)
+	if err != nil {
+		return fmt.Errorf("Error opening log file: %v", err)
+	}
+	f.console.logFile = file
+	log.SetOutput(io.MultiWriter(errorWriter{os.Stderr, f.console}, file))
+	fmt.Fprintf(file, "%s %s\n", time.Now().Format(time.RFC3339), strings.Join(os.Args, " "))
+	return nil
+}
+
+// Return the writer of the debug output: the log file, or the console with -debug
+func (f *schemaFlags) debugOutput() io.Writer {
+	if f.console.logFile != nil {
+		return f.console.logFile
+	}
+	if f.debug {
+		return os.Stdout
+	}
+	return nil
+}
+
+// Return the writer of the progress messages, which go to the log file instead of the console when set
+func (c *console) progressOutput() io.Writer {
+	if c.logFile != nil {
+		return c.logFile
+	}
+	if c.progressOnStderr {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// Copy a console message to the log file
+func (c *console) logMessage(message string) {
+	if c.logFile != nil {
+		fmt.Fprintln(c.logFile, message)
+	}
+}
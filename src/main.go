@@ -1,307 +1,759 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/vektah/gqlparser/v2"
-	"github.com/vektah/gqlparser/v2/ast"
+	"graphql-ts-generator/generator"
 )
 
-// Struct to store type and interface information
-type TypeInfo struct {
-	Name       string
-	Definition *ast.Definition
+func main() {
+	log.SetOutput(errorWriter{os.Stderr, &console{}})
+	args := os.Args[1:]
+	command := "generate"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	stopProfiling := startProfiling(args)
+	switch command {
+	case "generate":
+		runGenerate(args, nil)
+	case "sdl":
+		runGenerate(args, map[string]string{"target": generator.TargetSDL, "output": "./schema.graphql"})
+	case "docs":
+		runGenerate(args, map[string]string{"target": generator.TargetDocs, "output": "./schema.md"})
+	case "graph":
+		runGenerate(args, map[string]string{"target": generator.TargetDOT, "output": "./schema.dot"})
+	case "changelog":
+		runGenerate(args, map[string]string{"changelog": "-"})
+	case "reverse":
+		runReverse(args)
+	case "validate":
+		runValidate(args)
+	case "validate-operations":
+		runValidateOperations(args)
+	case "coverage":
+		runCoverage(args)
+	case "deprecations":
+		runDeprecations(args)
+	case "test":
+		runSnapshotTest(args)
+	case "complexity":
+		runComplexity(args)
+	case "operations":
+		runOperations(args)
+	case "serve":
+		runServe(args)
+	case "install-hook":
+		runInstallHook(args)
+	case "completion":
+		runCompletion(args)
+	default:
+		log.Fatalf("Unknown command: %s", command)
+	}
+	stopProfiling()
 }
 
-var (
-	types      = make(map[string]*TypeInfo)
-	enums      = make(map[string]*ast.Definition)
-	queries    = make(map[string]*ast.FieldDefinition) // Для Query
-	mutations  = make(map[string]*ast.FieldDefinition) // Для Mutation
-	skipChecks bool
-	debug      bool
-)
+// Generate the TypeScript file from the schema files, or every output listed in the config file,
+// for the selected project of the config file, or for each of its projects with -all.
+// Defaults replace the default values of the given flags, for commands built on top of generate.
+// The outputs of a config share the parsed schema files, so each file is parsed once per run.
+func runGenerate(args []string, defaults map[string]string) {
+	run := &generateRun{args: args, defaults: defaults, parseCache: generator.NewParseCache(), console: &console{}}
+	if err := run.generate(); err != nil {
+		log.Fatal(err)
+	}
+}
 
-func main() {
-	// Get command-line parameters
-	inputDir := flag.String("input", "./schemas", "Directory with GraphQL schemas")
-	outputPath := flag.String("output", "./generated-types.ts", "Path for the output TypeScript file")
-	flag.BoolVar(&skipChecks, "skipChecks", false, "Skip type mismatch checks")
-	flag.BoolVar(&debug, "debug", false, "Print debug log")
-	flag.Parse()
+// A run of the generate command, that the watch mode runs again in process on every change.
+// Failures are returned rather than exiting, and the state of the run is held here, not in package variables.
+type generateRun struct {
+	args     []string
+	defaults map[string]string
+	// Schema files parsed by the outputs of the run and by the rebuilds of the watch mode
+	parseCache *generator.ParseCache
+	// Console shared by the outputs of the run, so that the log file is opened once
+	console *console
+	// Schemas downloaded from URLs and registries by the outputs of the run, downloaded again by each rebuild
+	sources map[string][]byte
+}
 
-	// Check if input directory exists
-	if _, err := os.Stat(*inputDir); os.IsNotExist(err) {
-		log.Fatalf("Input directory does not exist: %s", *inputDir)
+// Generate the outputs of the selected project, or of every project with -all
+func (run *generateRun) generate() error {
+	run.sources = make(map[string][]byte)
+	if !argBool(run.args, allProjectsFlag) {
+		return run.generateProject(run.args)
 	}
 
-	// Read all .graphql files from the specified directory
-	err := filepath.Walk(*inputDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
+	if argValue(run.args, projectKey) != "" {
+		return errors.New("-all and -project cannot be combined")
+	}
+	projects, err := configProjects(run.args)
+	if err != nil {
+		return fmt.Errorf("Error loading config: %v", err)
+	}
+	if len(projects) == 0 {
+		return fmt.Errorf("-all requires a config file with %q", projectsKey)
+	}
+	for _, name := range projects {
+		fmt.Fprintf(run.console.progressOutput(), "Generating project: %s\n", name)
+		if err := run.generateProject(append([]string{"-" + projectKey, name}, run.args...)); err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
-		if !info.IsDir() && strings.HasSuffix(info.Name(), ".graphql") {
-			fmt.Printf("Processing file: %s\n", path)
-			if err := processSchemaFile(path); err != nil {
-				return err
-			}
-		}
-
-		return nil
-	})
-
+// Generate the outputs of the project selected by the arguments
+func (run *generateRun) generateProject(args []string) error {
+	outputs, err := configOutputs(args)
 	if err != nil {
-		log.Fatalf("Error processing schema files: %v", err)
+		return fmt.Errorf("Error loading config: %v", err)
 	}
-
-	// Generate TypeScript file
-	if err := generateTypescriptFile(*outputPath); err != nil {
-		log.Fatalf("Error generating TypeScript file: %v", err)
+	if len(outputs) == 0 {
+		return run.generateOutput(args, nil)
 	}
+	for _, output := range outputs {
+		if err := run.generateOutput(args, output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	fmt.Printf("TypeScript file generation completed. File saved at: %s\n", *outputPath)
+// Flags of the generate command and of the commands built on top of it
+type generateFlags struct {
+	outputPath             *string
+	cachePath              *string
+	split                  *bool
+	splitRoots             *bool
+	extension              *string
+	esm                    *bool
+	lintSuppressions       *string
+	contentHash            *bool
+	failOnEdit             *bool
+	lineEndings            *string
+	bom                    *bool
+	headerSchemaHash       *bool
+	headerVersion          *bool
+	headerTimestamp        *bool
+	postHook               *string
+	watch                  *bool
+	watchInterval          *time.Duration
+	watchDebounce          *time.Duration
+	onSuccess              *string
+	onFailure              *string
+	pprofAddr              *string
+	lockfilePath           *string
+	verifyLockfile         *bool
+	changelog              *string
+	changelogBase          *string
+	check                  *bool
+	lint                   *bool
+	target                 *string
+	goPackage              *string
+	unionEnums             *string
+	numericEnums           *string
+	typeNameUnion          *bool
+	enumValues             *bool
+	enumHelpers            *bool
+	typeMetadata           *bool
+	operationNames         *bool
+	resultAliases          *bool
+	resolvers              *bool
+	federation             *bool
+	dates                  *bool
+	declarationOrder       *bool
+	inputDefaults          *bool
+	partialInputs          *bool
+	inputMaybe             *bool
+	scalarsRecord          *bool
+	exhaustive             *bool
+	argumentDefaults       *bool
+	inputClasses           *bool
+	zod                    *bool
+	inputCoercion          *bool
+	implementers           *bool
+	jsdoc                  *bool
+	vue                    *string
+	vueDepth               *int
+	reservedFields         *string
+	arraySyntax            *string
+	inlineNull             *bool
+	strictTS               *bool
+	preset                 *string
+	responseTypes          *bool
+	pagination             *bool
+	paginationFields       *string
+	inheritInterfaceFields *bool
+	typeMap                *bool
+	prune                  *bool
+	only                   *string
+	exclude                *string
 }
 
-func debugPrint(format string, a ...any) {
-	if debug {
-		fmt.Printf(format, a...)
+func registerGenerateFlags(flags *flag.FlagSet) *generateFlags {
+	return &generateFlags{
+		outputPath:             flags.String("output", "./generated-types.ts", "Path for the output file"),
+		cachePath:              flags.String("cache", "", "Path to the incremental generation cache file (disabled if empty)"),
+		split:                  flags.Bool("split", false, "Write enums.ts, inputs.ts, objects.ts, operations.ts and index.ts into the -output directory"),
+		splitRoots:             flags.Bool("split-roots", false, "With -split, write the declarations of each root type to queries.ts, mutations.ts and subscriptions.ts"),
+		extension:              flags.String("extension", "", "Extension of split files: .ts, .mts or .cts (defaults to the extension of -output, or .ts)"),
+		esm:                    flags.Bool("esm", false, "Add JavaScript extensions (.js, .mjs, .cjs) to relative import specifiers for ESM and NodeNext resolution"),
+		lintSuppressions:       flags.String("lint-suppressions", "", "Comma-separated linters disabled in the file header (tslint, eslint, biome), or none; defaults to the target's"),
+		contentHash:            flags.Bool("content-hash", true, "Embed the hash of the generated content in the first line, to detect manual edits on the next run"),
+		failOnEdit:             flags.Bool("fail-on-edit", false, "Fail instead of warning when the existing output was edited by hand"),
+		lineEndings:            flags.String("line-endings", lineEndingsLF, "Line endings of the output files: lf or crlf"),
+		bom:                    flags.Bool("bom", false, "Start the output files with a UTF-8 byte order mark"),
+		headerSchemaHash:       flags.Bool("header-schema-hash", false, "Show the hash of the schema files in the file header"),
+		headerVersion:          flags.Bool("header-version", false, "Show the generator version in the file header"),
+		headerTimestamp:        flags.Bool("header-timestamp", false, "Show the generation time in the file header, taken from SOURCE_DATE_EPOCH if set"),
+		postHook:               flags.String("post-hook", "", "Shell command run after successful generation with the output files as arguments, e.g. \"prettier --write\""),
+		watch:                  flags.Bool("watch", false, "Regenerate whenever the schema files or the config file change"),
+		watchInterval:          flags.Duration("watch-interval", 500*time.Millisecond, "How often the watched files are checked for changes"),
+		watchDebounce:          flags.Duration("watch-debounce", 300*time.Millisecond, "How long the watched files must stay unchanged before regenerating"),
+		onSuccess:              flags.String("on-success", "", "Shell command run after each successful generation in watch mode"),
+		onFailure:              flags.String("on-failure", "", "Shell command run after each failed generation in watch mode"),
+		pprofAddr:              flags.String("pprof", "", "Address serving the pprof endpoints in watch mode, e.g. localhost:6060"),
+		lockfilePath:           flags.String("lockfile", "", "Path to a lockfile pinning the hashes of the schema files and outputs, written after generation"),
+		verifyLockfile:         flags.Bool("verify-lockfile", false, "Fail if the schema files or outputs differ from the -lockfile instead of generating"),
+		changelog:              flags.String("changelog", "", "Write a Markdown changelog of the types, fields and enum values changed since the previous output to this file, - for stdout"),
+		changelogBase:          flags.String("changelog-base", "", "Previous TypeScript output compared by -changelog instead of the file being replaced, e.g. the output of the last release"),
+		check:                  flags.Bool("check", false, "Fail if the output is not up to date instead of writing it, e.g. in CI"),
+		lint:                   flags.Bool("lint", false, "Run schema lint rules and fail on lint errors"),
+		target:                 flags.String("target", generator.TargetTypescript, "Output language: typescript, flow, go, sdl, docs, html, dot, mermaid or fixtures"),
+		goPackage:              flags.String("go-package", "generated", "Package name of the generated Go file (go target)"),
+		numericEnums:           flags.String("numeric-enums", "", "Comma-separated enums emitted with numeric values (like @tsNumeric)"),
+		unionEnums:             flags.String("union-enums", "", "Comma-separated enums emitted as unions of string literals instead of TypeScript enums (like @tsUnion)"),
+		typeNameUnion:          flags.Bool("type-names", false, "Emit a TypeName union of all object type names"),
+		enumValues:             flags.Bool("enum-values", false, "Emit a const array of the values of each enum"),
+		typeMetadata:           flags.Bool("type-metadata", false, "Emit an as const metadata object of the fields of each type, e.g. UserMetadata, with their GraphQL types and nullability"),
+		enumHelpers:            flags.Bool("enum-helpers", false, "Emit an isUserRole type guard and a parseUserRole(value: string) function of each enum"),
+		operationNames:         flags.Bool("operation-names", false, "Emit a const object of all Query, Mutation and Subscription field names"),
+		resultAliases:          flags.Bool("result-aliases", false, "Emit an alias of the result type of each Query and Mutation field, e.g. GetProjectsResult = Array<Project>"),
+		resolvers:              flags.Bool("resolvers", false, "Emit resolver signature types for Query, Mutation and Subscription"),
+		federation:             flags.Bool("federation", false, "Emit the Apollo Federation _Entity, _Any and entity key types"),
+		dates:                  flags.Bool("dates", false, "Map DateTime to Date and emit parseDates/serializeDates helpers"),
+		declarationOrder:       flags.Bool("declaration-order", false, "Emit types and root fields in schema declaration order instead of sorted by name"),
+		inputDefaults:          flags.Bool("input-defaults", false, "Emit a default<Input>() factory for each input type, filled from schema default values and zero values"),
+		partialInputs:          flags.Bool("partial-inputs", false, "Emit a DeepPartial helper and a Partial<Input> alias of each input type"),
+		vue:                    flags.String("vue", "", "Emit typed Vue 3 composables for each Query and Mutation field, for the urql or villus client"),
+		vueDepth:               flags.Int("vue-depth", generator.DefaultOperationDepth, "Levels of object fields selected by the documents of the -vue composables"),
+		jsdoc:                  flags.Bool("jsdoc", false, "Emit descriptions as JSDoc comments, using the # comments above elements without a description"),
+		implementers:           flags.Bool("implementers", false, "Emit a union of the object types implementing each interface, e.g. NodeTypes = User | Project"),
+		inputClasses:           flags.Bool("input-classes", false, "Emit input types as classes with a constructor taking a partial value and applying the schema defaults"),
+		inputCoercion:          flags.Bool("input-coercion", false, "Emit a coerce<Input>(raw: unknown) function of each input type, converting form data and throwing InputCoercionError"),
+		zod:                    flags.Bool("zod", false, "Emit a Zod schema of each input type, with the @constraint arguments of its fields as refinements"),
+		argumentDefaults:       flags.Bool("argument-defaults", false, "Emit a constant of the argument default values of each field, e.g. GET_PROJECTS_DEFAULTS"),
+		exhaustive:             flags.Bool("exhaustive", false, "Emit assertNever and a match function of each enum and union, requiring a case for every value or member"),
+		inputMaybe:             flags.Bool("input-maybe", false, "Type nullable input fields and arguments as InputMaybe<T> = T | null | undefined, outputs as Nullable<T> = T | null"),
+		scalarsRecord:          flags.Bool("scalars-record", false, "Emit the exported Scalars interface listing the TypeScript type of every built-in and mapped scalar, referenced by scalar fields"),
+		pagination:             flags.Bool("pagination", false, "Emit PageInfo, Paginated<T> and pagination variables types for the connections and paginated fields of the schema"),
+		paginationFields:       flags.String("pagination-fields", "", "Comma-separated pagination field names, as role=name, e.g. edges=items,offset=skip"),
+		responseTypes:          flags.Bool("response-types", false, "Emit GraphQLError and GraphQLResponse<T> execution result types, used by the Vue composables"),
+		preset:                 flags.String("preset", "", "Follow the naming and shape conventions of another generator: codegen (graphql-codegen's typescript plugin)"),
+		strictTS:               flags.Bool("strict-ts", false, "Emit types for strict, exactOptionalPropertyTypes and noUncheckedIndexedAccess"),
+		inlineNull:             flags.Bool("inline-null", false, "Write nullable types as T | null instead of the Nullable<T> alias"),
+		arraySyntax:            flags.String("array-syntax", generator.ArrayGeneric, "Syntax of list types: generic (Array<T>) or array (T[])"),
+		reservedFields:         flags.String("reserved-fields", generator.ReservedQuote, "Fields named after reserved words such as delete: quote ('delete'), rename (delete_) or keep"),
+		inheritInterfaceFields: flags.Bool("inherit-interface-fields", false, "Copy the fields of implemented interfaces missing from object types into their output"),
+		typeMap:                flags.Bool("type-map", false, "Emit a TypeMap interface from type name to generated interface"),
+		prune:                  flags.Bool("prune", false, "Only emit types reachable from Query, Mutation and Subscription fields"),
+		only:                   flags.String("only", "", "Comma-separated type names and Root.field patterns to emit, e.g. User,Project,Query.*"),
+		exclude:                flags.String("exclude", "", "Comma-separated type names and Root.field patterns to omit, e.g. Admin*,Query.internal*"),
 	}
 }
 
-// Function to process a single GraphQL schema file
-func processSchemaFile(path string) error {
-	// Read the schema file
-	fileContent, err := os.ReadFile(path)
-	if err != nil {
-		return fmt.Errorf("could not read file %s: %v", path, err)
+// Generate one output file. Overrides are the options of the output in a multi-output config.
+func (run *generateRun) generateOutput(args []string, overrides map[string]any) error {
+	// Get command-line parameters
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	schemaOpts := registerSchemaFlags(flags)
+	f := registerGenerateFlags(flags)
+	for name, value := range run.defaults {
+		flags.Set(name, value)
+	}
+	if err := parseFlags(flags, args, overrides); err != nil {
+		return err
+	}
+	schemaOpts.sources = run.sources
+	run.console.noColor = schemaOpts.console.noColor
+	schemaOpts.console = run.console
+	if *f.splitRoots && !*f.split {
+		return errors.New("-split-roots requires -split")
+	}
+	if err := checkLineEndings(*f.lineEndings); err != nil {
+		return fmt.Errorf("Invalid -line-endings: %v", err)
+	}
+	if *f.changelog != "" && *f.target != generator.TargetTypescript {
+		return errors.New("-changelog requires the typescript target")
+	}
+	run.console.progressOnStderr = run.console.progressOnStderr || *f.changelog == "-"
+
+	ctx, cancel := schemaOpts.context()
+	defer cancel()
+
+	// Every output of the config is regenerated by the watch, so it starts from the first one
+	if *f.watch {
+		runWatch(ctx, watchOptions{
+			interval:  *f.watchInterval,
+			debounce:  *f.watchDebounce,
+			onSuccess: *f.onSuccess,
+			onFailure: *f.onFailure,
+			pprofAddr: *f.pprofAddr,
+		}, schemaOpts, args, run)
+		os.Exit(0)
 	}
 
-	debugPrint("Parsing file: %s\n", path)
+	cache := loadCache(*f.cachePath)
+	files, err := schemaOpts.collectFiles(ctx)
+	if err != nil {
+		return err
+	}
 
-	// Parse the schema
-	schema, err := gqlparser.LoadSchema(&ast.Source{
-		Input: string(fileContent),
-	})
+	// Hash the inputs and skip the run entirely if nothing changed since the cached one
+	hashes, err := schemaOpts.hashFiles(files)
 	if err != nil {
-		return fmt.Errorf("error parsing schema in file %s: %v", path, err)
-	}
-
-	// Process types and interfaces
-	for _, typ := range schema.Types {
-		debugPrint("Processing type: %s from file %s\n", typ.Name, path)
-		if typ.Kind == ast.Object || typ.Kind == ast.Interface {
-			if typ.Name == "Query" {
-				// Добавляем все поля Query
-				for _, field := range typ.Fields {
-					debugPrint("Adding Query field: %s\n", field.Name)
-					queries[field.Name] = field
-				}
-			} else if typ.Name == "Mutation" {
-				// Добавляем все поля Mutation
-				for _, field := range typ.Fields {
-					debugPrint("Adding Mutation field: %s\n", field.Name)
-					mutations[field.Name] = field
-				}
-			} else {
-				if err := addTypeOrInterface(typ); err != nil {
-					return err
-				}
-				debugPrint("Added type/interface: %s\n", typ.Name)
-			}
+		return fmt.Errorf("Error processing schema files: %v", err)
+	}
+	if *f.verifyLockfile {
+		if *f.lockfilePath == "" {
+			return errors.New("-verify-lockfile requires -lockfile")
 		}
-
-		// Process enums
-		if typ.Kind == ast.Enum {
-			debugPrint("Processing enum: %s from file %s\n", typ.Name, path)
-			if err := addEnum(typ); err != nil {
-				return err
-			}
-			debugPrint("Added enum: %s\n", typ.Name)
+		lock, err := loadLockfile(*f.lockfilePath)
+		if err != nil {
+			return fmt.Errorf("Error verifying lockfile: %v", err)
 		}
+		if err := lock.verify(hashes); err != nil {
+			return fmt.Errorf("Lockfile %s is out of date: %v", *f.lockfilePath, err)
+		}
+		run.console.printSuccess("Lockfile is up to date: %s", *f.lockfilePath)
+		return nil
+	}
+	options := optionsHash(flags)
+	output := outputName(*f.target, *f.split)
+	if !*f.check && *f.changelog == "" && cache.upToDate(hashes, options) {
+		run.console.printSuccess("%s is up to date. File saved at: %s", output, *f.outputPath)
+		return nil
 	}
 
-	return nil
-}
+	// A single file follows the extension of the output path, split files default to .ts
+	if *f.extension == "" {
+		*f.extension = ".ts"
+		if ext := filepath.Ext(*f.outputPath); !*f.split && (ext == ".mts" || ext == ".cts") {
+			*f.extension = ext
+		}
+	}
 
-// Add type or interface to the global list
-func addTypeOrInterface(def *ast.Definition) error {
-	existing, found := types[def.Name]
-	if found {
-		// Compare type or interface structure if skipChecks is not enabled
-		if !skipChecks && !compareDefinitions(existing.Definition, def) {
-			return fmt.Errorf("error: type or interface %s has conflicting definitions", def.Name)
+	genOpts := []generator.Option{
+		generator.WithExtension(*f.extension),
+		generator.WithESM(*f.esm),
+		generator.WithHeaderMetadata(*f.headerSchemaHash, *f.headerVersion),
+	}
+	if *f.headerTimestamp {
+		timestamp, err := generationTime()
+		if err != nil {
+			return err
 		}
-	} else {
-		// Add new type or interface
-		types[def.Name] = &TypeInfo{
-			Name:       def.Name,
-			Definition: def,
+		genOpts = append(genOpts, generator.WithHeaderTimestamp(timestamp))
+	}
+	if run.parseCache != nil {
+		genOpts = append(genOpts, generator.WithParseCache(run.parseCache))
+	}
+	for _, field := range splitList(*f.paginationFields) {
+		role, name, found := strings.Cut(field, "=")
+		if !found || role == "" || name == "" {
+			return fmt.Errorf("Invalid -pagination-fields entry %q: expected role=name", field)
 		}
+		genOpts = append(genOpts, generator.WithPaginationField(role, name))
+	}
+	if *f.lintSuppressions == "none" {
+		genOpts = append(genOpts, generator.WithLintSuppressions())
+	} else if *f.lintSuppressions != "" {
+		genOpts = append(genOpts, generator.WithLintSuppressions(splitList(*f.lintSuppressions)...))
+	}
+
+	gen, err := schemaOpts.newGenerator(append(genOpts,
+		generator.WithTarget(*f.target),
+		generator.WithGoPackage(*f.goPackage),
+		generator.WithNumericEnums(splitList(*f.numericEnums)...),
+		generator.WithUnionEnums(splitList(*f.unionEnums)...),
+		generator.WithTypeNameUnion(*f.typeNameUnion),
+		generator.WithTypeMap(*f.typeMap),
+		generator.WithInheritInterfaceFields(*f.inheritInterfaceFields),
+		generator.WithReservedFields(*f.reservedFields),
+		generator.WithArraySyntax(*f.arraySyntax),
+		generator.WithInlineNull(*f.inlineNull),
+		generator.WithStrictTS(*f.strictTS),
+		generator.WithPreset(*f.preset),
+		generator.WithResponseTypes(*f.responseTypes),
+		generator.WithPagination(*f.pagination),
+		generator.WithSplitRoots(*f.splitRoots),
+		generator.WithDeclarationOrder(*f.declarationOrder),
+		generator.WithInputDefaults(*f.inputDefaults),
+		generator.WithPartialInputs(*f.partialInputs),
+		generator.WithInputMaybe(*f.inputMaybe),
+		generator.WithScalarsRecord(*f.scalarsRecord),
+		generator.WithExhaustive(*f.exhaustive),
+		generator.WithArgumentDefaults(*f.argumentDefaults),
+		generator.WithInputClasses(*f.inputClasses),
+		generator.WithZod(*f.zod),
+		generator.WithInputCoercion(*f.inputCoercion),
+		generator.WithImplementerUnions(*f.implementers),
+		generator.WithJSDoc(*f.jsdoc),
+		generator.WithVue(*f.vue, *f.vueDepth),
+		generator.WithEnumValues(*f.enumValues),
+		generator.WithEnumHelpers(*f.enumHelpers),
+		generator.WithTypeMetadata(*f.typeMetadata),
+		generator.WithOperationNames(*f.operationNames),
+		generator.WithResultAliases(*f.resultAliases),
+		generator.WithResolvers(*f.resolvers),
+		generator.WithFederation(*f.federation),
+		generator.WithDates(*f.dates),
+		generator.WithPrune(*f.prune),
+		generator.WithOnly(splitList(*f.only)...),
+		generator.WithExclude(splitList(*f.exclude)...),
+	)...)
+	if err != nil {
+		return err
+	}
+	if err := schemaOpts.loadSchemaFiles(ctx, gen, files, hashes); err != nil {
+		return err
+	}
+
+	// Warnings are reported before writing, so that -fail-on-warn leaves the previous output in place
+	failingCount := schemaOpts.reportWarnings(gen)
+	if *f.lint {
+		errorCount, failingLintCount := schemaOpts.reportLint(gen)
+		if errorCount > 0 {
+			return fmt.Errorf("Schema lint failed: %d lint error(s) found", errorCount)
+		}
+		failingCount += failingLintCount
+	}
+	if failingCount > 0 {
+		return fmt.Errorf("Generation failed: %d warning(s) found with -fail-on-warn", failingCount)
+	}
+
+	// The hash comment is only valid in languages with // comments
+	outputOpts := outputOptions{
+		contentHash: *f.contentHash && !nonCodeTargets[*f.target],
+		failOnEdit:  *f.failOnEdit,
+		lineEndings: *f.lineEndings,
+		bom:         *f.bom,
+		check:       *f.check,
+		postHook:    *f.postHook,
+		debug:       schemaOpts.debugOutput(),
+		console:     run.console,
 	}
-	return nil
-}
 
-// Add enum to the global list
-func addEnum(enum *ast.Definition) error {
-	existingEnum, found := enums[enum.Name]
-	if found {
-		// Compare enums if skipChecks is not enabled
-		if !skipChecks && !compareEnums(existingEnum, enum) {
-			return fmt.Errorf("error: enum %s has conflicting definitions", enum.Name)
+	// Read the types of the output about to be replaced, or of the given base, to describe what changed
+	var previous map[string]*tsDeclaration
+	if *f.changelog != "" {
+		basePaths := []string{*f.outputPath}
+		if *f.changelogBase != "" {
+			basePaths = []string{*f.changelogBase}
+		} else if *f.split {
+			basePaths = splitOutputFiles(*f.outputPath, *f.extension)
 		}
+		if previous, err = readTypescriptModel(basePaths); err != nil {
+			return fmt.Errorf("Error reading previous output: %v", err)
+		}
+	}
+
+	// Generate TypeScript file, or one file per kind of definition
+	outputPaths := []string{*f.outputPath}
+	var outputHashes map[string]string
+	if *f.split {
+		outputPaths, outputHashes, err = generateSplitFiles(ctx, gen, *f.outputPath, outputOpts)
 	} else {
-		enums[enum.Name] = enum
+		var hash string
+		hash, err = generateTypescriptFile(ctx, gen, *f.outputPath, outputOpts)
+		outputHashes = map[string]string{*f.outputPath: hash}
+	}
+	if err != nil {
+		return fmt.Errorf("Error generating %s: %v", output, err)
 	}
-	return nil
-}
 
-// Compare the structures of two type or interface definitions
-func compareDefinitions(a, b *ast.Definition) bool {
-	if len(a.Fields) != len(b.Fields) {
-		return false
+	// The cache stores the hashes of the files once formatted, as the next run finds them on disk
+	if *f.postHook != "" && !*f.check {
+		if err := runHook(*f.postHook, outputPaths); err != nil {
+			return fmt.Errorf("Error running post-generation hook: %v", err)
+		}
+		for _, path := range outputPaths {
+			if err := restampContentHash(path, outputOpts); err != nil {
+				return fmt.Errorf("Error updating content hash: %v", err)
+			}
+		}
+		if outputHashes, err = hashOutputs(outputPaths); err != nil {
+			return fmt.Errorf("Error reading output file: %v", err)
+		}
 	}
-	for i := range a.Fields {
-		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type.String() != b.Fields[i].Type.String() {
-			return false
+
+	if *f.changelog != "" {
+		current, err := readTypescriptModel(outputPaths)
+		if err != nil {
+			return fmt.Errorf("Error reading output: %v", err)
+		}
+		if err := writeChangelog(*f.changelog, typescriptChangelog(previous, current)); err != nil {
+			return fmt.Errorf("Error writing changelog: %v", err)
 		}
 	}
-	return true
-}
 
-// Compare the structures of two enums
-func compareEnums(a, b *ast.Definition) bool {
-	if len(a.EnumValues) != len(b.EnumValues) {
-		return false
+	if *f.check {
+		run.console.printSuccess("%s is up to date: %s", output, *f.outputPath)
+		return nil
 	}
-	for i := range a.EnumValues {
-		if a.EnumValues[i].Name != b.EnumValues[i].Name {
-			return false
+
+	if *f.lockfilePath != "" {
+		lock, err := newLockfile(hashes, outputPaths)
+		if err == nil {
+			err = lock.save(*f.lockfilePath)
+		}
+		if err != nil {
+			return fmt.Errorf("Error writing lockfile: %v", err)
 		}
 	}
-	return true
+
+	cache.Files = hashes
+	cache.Options = options
+	cache.Output = outputHashes
+	if err := cache.save(*f.cachePath); err != nil {
+		return fmt.Errorf("Error writing cache file: %v", err)
+	}
+
+	run.console.printSuccess("%s generation completed. File saved at: %s", output, *f.outputPath)
+	return nil
 }
 
-// Generate the final TypeScript file
-func generateTypescriptFile(outputPath string) error {
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("could not create file: %v", err)
+// Targets whose output has no // comments, so no content hash line
+var nonCodeTargets = map[string]bool{
+	generator.TargetSDL:      true,
+	generator.TargetDocs:     true,
+	generator.TargetHTML:     true,
+	generator.TargetDOT:      true,
+	generator.TargetMermaid:  true,
+	generator.TargetFixtures: true,
+}
+
+// Names of the output of each target in the messages of the generate command
+var targetOutputNames = map[string]string{
+	generator.TargetTypescript: "TypeScript file",
+	generator.TargetFlow:       "Flow file",
+	generator.TargetGo:         "Go file",
+	generator.TargetSDL:        "SDL file",
+	generator.TargetDocs:       "Markdown documentation",
+	generator.TargetHTML:       "HTML documentation",
+	generator.TargetDOT:        "Graphviz file",
+	generator.TargetMermaid:    "Mermaid file",
+	generator.TargetFixtures:   "Fixtures file",
+}
+
+// Return the name of the output of a target in messages, such as "SDL file"
+func outputName(target string, split bool) string {
+	if split {
+		return "Split TypeScript output"
 	}
-	defer file.Close()
+	if name, found := targetOutputNames[target]; found {
+		return name
+	}
+	return "Output file"
+}
 
-	// Header
-	file.WriteString(`/*
- * -------------------------------------------------------
- * THIS FILE WAS AUTOMATICALLY GENERATED (DO NOT MODIFY)
- * -------------------------------------------------------
- */
+// Settings of the written output files
+type outputOptions struct {
+	// Embed the hash of the generated content in a first-line comment, to detect manual edits
+	contentHash bool
+	// Fail instead of warning when the existing output was edited by hand
+	failOnEdit bool
+	// Line endings of the files, lf or crlf
+	lineEndings string
+	// Start the files with a UTF-8 byte order mark
+	bom bool
+	// Only compare the generated content with the existing file, without writing it
+	check bool
+	// Post-generation hook run on a copy of the generated content before comparing it in check mode
+	postHook string
+	// Destination of the debug messages, none if nil
+	debug io.Writer
+	// Console receiving the warnings
+	console *console
+}
 
-/* tslint:disable */
-/* eslint-disable */
+// Generate the final TypeScript file and return the hash of its content.
+func generateTypescriptFile(ctx context.Context, gen *generator.Generator, outputPath string, opts outputOptions) (string, error) {
+	return writeOutputFile(outputPath, opts, func(w io.Writer) error {
+		return gen.Emit(ctx, w)
+	})
+}
 
-`)
-	file.WriteString("type Nullable<T> = T | null;\n\n")
+// Generate the split TypeScript files into the output directory and return their paths and the hash of each file
+func generateSplitFiles(ctx context.Context, gen *generator.Generator, outputDir string, opts outputOptions) ([]string, map[string]string, error) {
+	files, err := gen.EmitSplit(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not write files: %v", err)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("could not create directory: %v", err)
+	}
 
-	// Generate enums in "mirror" style
-	for _, enum := range enums {
-		file.WriteString(fmt.Sprintf("export enum %s {\n", enum.Name))
-		for _, value := range enum.EnumValues {
-			file.WriteString(fmt.Sprintf("  %s = '%s',\n", value.Name, value.Name))
+	var paths []string
+	hashes := make(map[string]string, len(files))
+	for _, file := range files {
+		path := filepath.Join(outputDir, file.Name)
+		hash, err := writeOutputFile(path, opts, func(w io.Writer) error {
+			_, err := w.Write(file.Content)
+			return err
+		})
+		if err != nil {
+			return nil, nil, err
 		}
-		file.WriteString("}\n\n")
+		paths = append(paths, path)
+		hashes[path] = hash
 	}
+	return paths, hashes, nil
+}
 
-	// Generate interfaces and types
-	for _, typeInfo := range types {
-		if typeInfo.Definition.Kind == ast.Object {
-			file.WriteString(fmt.Sprintf("export interface %s {\n", typeInfo.Name))
-		} else if typeInfo.Definition.Kind == ast.Interface {
-			file.WriteString(fmt.Sprintf("export interface %s {\n", typeInfo.Name))
+// Write a file through emit and return the hash of its content, once converted to the configured encoding.
+// The custom regions of the previous file are appended to the new content, and the previous file
+// is checked for manual edits outside of them. Output is streamed to a temporary file which replaces
+// the target only once generation succeeded, and only when the content differs from what is already on disk.
+func writeOutputFile(outputPath string, opts outputOptions, emit func(w io.Writer) error) (string, error) {
+	existing, readErr := os.ReadFile(outputPath)
+	var regions []byte
+	if readErr == nil {
+		generated, customRegions, err := splitCustomRegions(outputPath, decodeOutput(existing))
+		if err != nil {
+			return "", err
+		}
+		regions = customRegions
+		if opts.contentHash && manuallyEdited(generated) {
+			if opts.failOnEdit {
+				return "", fmt.Errorf("%s was modified by hand since it was generated; move the edits into // <custom> regions or remove them", outputPath)
+			}
+			opts.console.printWarning(fmt.Sprintf("warning: %s was modified by hand since it was generated; edits outside // <custom> regions are overwritten", outputPath))
 		}
+	}
 
-		for _, field := range typeInfo.Definition.Fields {
-			isOptional := !strings.HasSuffix(field.Type.String(), "!")
-			fieldType := convertGraphqlTypeToTs(field.Type.String())
-			if isOptional {
-				file.WriteString(fmt.Sprintf("  %s?: Nullable<%s>;\n", field.Name, fieldType))
-			} else {
-				file.WriteString(fmt.Sprintf("  %s: %s;\n", field.Name, fieldType))
+	// The content hash line comes before the content it hashes, so the content is first staged in a temporary file
+	if opts.contentHash {
+		staged, contentHash, err := stageOutput(outputPath, emit)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(staged.Name())
+		defer staged.Close()
+		emit = func(w io.Writer) error {
+			if _, err := io.WriteString(w, contentHashPrefix+contentHash+"\n"); err != nil {
+				return err
 			}
+			_, err := io.Copy(w, staged)
+			return err
 		}
+	}
 
-		file.WriteString("}\n\n")
+	tmp, err := os.CreateTemp(filepath.Dir(outputPath), filepath.Base(outputPath)+".tmp*")
+	if err != nil {
+		return "", fmt.Errorf("could not create file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hasher := sha256.New()
+	buffered := bufio.NewWriter(io.MultiWriter(tmp, hasher))
+	if opts.bom {
+		buffered.Write(utf8BOM)
+	}
+	encoded := newLineEndingsWriter(buffered, opts.lineEndings)
+	err = emit(encoded)
+	if err == nil {
+		_, err = encoded.Write(regions)
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("could not write file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("could not write file: %v", err)
 	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
 
-	// Generate Query interface
-	if len(queries) > 0 {
-		file.WriteString("export interface Query {\n")
-		for _, query := range queries {
-			isOptional := !strings.HasSuffix(query.Type.String(), "!")
-			fieldType := convertGraphqlTypeToTs(query.Type.String())
-			if isOptional {
-				file.WriteString(fmt.Sprintf("  %s?: Nullable<%s>;\n", query.Name, fieldType))
-			} else {
-				file.WriteString(fmt.Sprintf("  %s: %s;\n", query.Name, fieldType))
+	if opts.check {
+		// The existing file went through the hook, such as a formatter, so the new content is compared once formatted too
+		if opts.postHook != "" {
+			if hash, err = hookedOutputHash(outputPath, tmp.Name(), opts); err != nil {
+				return "", err
 			}
 		}
-		file.WriteString("}\n\n")
-	}
-
-	// Generate Mutation interface
-	if len(mutations) > 0 {
-		file.WriteString("export interface Mutation {\n")
-		for _, mutation := range mutations {
-			isOptional := !strings.HasSuffix(mutation.Type.String(), "!")
-			fieldType := convertGraphqlTypeToTs(mutation.Type.String())
-			if isOptional {
-				file.WriteString(fmt.Sprintf("  %s?: Nullable<%s>;\n", mutation.Name, fieldType))
-			} else {
-				file.WriteString(fmt.Sprintf("  %s: %s;\n", mutation.Name, fieldType))
-			}
+		if readErr != nil || hashContent(existing) != hash {
+			return "", fmt.Errorf("%s is out of date, run the generator to update it", outputPath)
 		}
-		file.WriteString("}\n\n")
+		return hash, nil
 	}
 
-	return nil
+	// Skip writing if the effective output did not change
+	if readErr == nil && hashContent(existing) == hash {
+		if opts.debug != nil {
+			fmt.Fprintf(opts.debug, "Output unchanged, skipping write: %s\n", outputPath)
+		}
+		return hash, nil
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return "", fmt.Errorf("could not create file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), outputPath); err != nil {
+		return "", fmt.Errorf("could not create file: %v", err)
+	}
+	return hash, nil
 }
 
-// Convert GraphQL types to TypeScript types
-func convertGraphqlTypeToTs(graphqlType string) string {
-	// Remove '!' at the end, as this represents non-nullable type in GraphQL
-	cleanType := strings.TrimSuffix(graphqlType, "!")
-
-	// Check if this is an array
-	if strings.HasPrefix(cleanType, "[") && strings.HasSuffix(cleanType, "]") {
-		// This is an array, extract the inner type
-		innerType := cleanType[1 : len(cleanType)-1]
-		// Recursively call convertGraphqlTypeToTs for the inner type
-		return "Array<" + convertGraphqlTypeToTs(innerType) + ">"
-	}
-
-	// Convert standard GraphQL types to TypeScript types
-	switch cleanType {
-	case "String":
-		return "string"
-	case "Int":
-		return "number"
-	case "Float":
-		return "number"
-	case "Boolean":
-		return "boolean"
-	case "ID":
-		return "string" // In TypeScript, IDs can be represented as strings
-	case "DateTime":
-		return "string"
-	case "JSONObject":
-		return "Record<string, unknown>"
-	default:
-		// Keep custom types as they are
-		return cleanType
+// Stream the generated content to a temporary file next to the output, and return the file,
+// positioned at its start, with the hash of the content
+func stageOutput(outputPath string, emit func(w io.Writer) error) (*os.File, string, error) {
+	staged, err := os.CreateTemp(filepath.Dir(outputPath), filepath.Base(outputPath)+".tmp*")
+	if err != nil {
+		return nil, "", fmt.Errorf("could not create file: %v", err)
+	}
+	hasher := sha256.New()
+	buffered := bufio.NewWriter(io.MultiWriter(staged, hasher))
+	err = emit(buffered)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err == nil {
+		_, err = staged.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		staged.Close()
+		os.Remove(staged.Name())
+		return nil, "", fmt.Errorf("could not write file: %v", err)
+	}
+	return staged, hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// Return the generation time shown in headers: SOURCE_DATE_EPOCH for reproducible builds, or the current time
+func generationTime() (time.Time, error) {
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Invalid SOURCE_DATE_EPOCH: %v", err)
+		}
+		return time.Unix(seconds, 0), nil
 	}
+	return time.Now(), nil
 }
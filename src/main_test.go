@@ -2,11 +2,26 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
 	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"graphql-ts-generator/generator"
 )
 
 // Helper function to check if a string is present in the generated file
@@ -85,3 +100,1554 @@ func TestMainFunction(t *testing.T) {
 		t.Fatalf("Failed to clean up output directory: %v", err)
 	}
 }
+
+func TestCacheUpToDate(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "out.ts")
+	cacheFile := filepath.Join(dir, "cache.json")
+
+	if err := os.WriteFile(outputFile, []byte("export interface A {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write output file: %v", err)
+	}
+
+	hashes := map[string]string{"a.graphql": "1", "b.graphql": "2"}
+	cache := loadCache(cacheFile)
+	cache.Files = hashes
+	cache.Options = "options"
+	cache.Output = map[string]string{outputFile: hashContent([]byte("export interface A {}\n"))}
+	if err := cache.save(cacheFile); err != nil {
+		t.Fatalf("Failed to save cache: %v", err)
+	}
+
+	loaded := loadCache(cacheFile)
+	if !loaded.upToDate(hashes, "options") {
+		t.Errorf("Expected cache to be up to date")
+	}
+	if loaded.upToDate(map[string]string{"a.graphql": "1", "b.graphql": "3"}, "options") {
+		t.Errorf("Expected changed schema hash to invalidate the cache")
+	}
+	if loaded.upToDate(hashes, "other options") {
+		t.Errorf("Expected changed options to invalidate the cache")
+	}
+
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	registerGenerateFlags(flags)
+	before := optionsHash(flags)
+	flags.Set("type-names", "true")
+	if optionsHash(flags) == before {
+		t.Errorf("Expected -type-names to change the options hash")
+	}
+
+	if err := os.WriteFile(outputFile, []byte("// edited\n"), 0644); err != nil {
+		t.Fatalf("Failed to write output file: %v", err)
+	}
+	if loaded.upToDate(hashes, "options") {
+		t.Errorf("Expected modified output file to invalidate the cache")
+	}
+}
+
+// Run generate several times on a schema and return how many runs the cache skipped
+func cachedRuns(t *testing.T, runs int, schema string, args ...string) int {
+	t.Helper()
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schemas", "schema.graphql")
+	if err := os.MkdirAll(filepath.Dir(schemaFile), 0755); err != nil {
+		t.Fatalf("Failed to create input directory: %v", err)
+	}
+	if err := os.WriteFile(schemaFile, []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	stdout := os.Stdout
+	defer func() { os.Stdout = stdout }()
+	messages, err := os.Create(filepath.Join(dir, "stdout.txt"))
+	if err != nil {
+		t.Fatalf("Failed to create output capture: %v", err)
+	}
+	defer messages.Close()
+	os.Stdout = messages
+
+	run := &generateRun{console: &console{}, args: append([]string{
+		"-input", filepath.Dir(schemaFile), "-output", filepath.Join(dir, "types"), "-cache", filepath.Join(dir, "cache.json"),
+	}, args...)}
+	for i := 0; i < runs; i++ {
+		if err := run.generate(); err != nil {
+			t.Fatalf("Failed to generate: %v", err)
+		}
+	}
+	data, _ := os.ReadFile(messages.Name())
+	return strings.Count(string(data), "is up to date")
+}
+
+func TestTargetMessages(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.graphql")
+	if err := os.WriteFile(schemaFile, []byte("type Query { id: ID }"), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	stdout := os.Stdout
+	defer func() { os.Stdout = stdout }()
+	messages, err := os.Create(filepath.Join(dir, "stdout.txt"))
+	if err != nil {
+		t.Fatalf("Failed to create output capture: %v", err)
+	}
+	defer messages.Close()
+	os.Stdout = messages
+
+	// The messages name the output of the selected target, also when the cache skips the run
+	for i := 0; i < 2; i++ {
+		run := &generateRun{console: &console{}, args: []string{
+			"-input", schemaFile, "-target", "sdl", "-output", filepath.Join(dir, "schema.graphql.out"), "-cache", filepath.Join(dir, "cache.json"),
+		}}
+		if err := run.generate(); err != nil {
+			t.Fatalf("Failed to generate: %v", err)
+		}
+	}
+	data, _ := os.ReadFile(messages.Name())
+	for _, expected := range []string{"SDL file generation completed", "SDL file is up to date"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %q in the messages, got: %s", expected, data)
+		}
+	}
+	if strings.Contains(string(data), "TypeScript") {
+		t.Errorf("Expected no TypeScript file in the messages of the sdl target, got: %s", data)
+	}
+}
+
+func TestCacheMapFlags(t *testing.T) {
+	// Repeated map flags hash the same on every run, whatever the iteration order of the map
+	schema := "scalar Money\nscalar Cursor\ntype Query { total: Money next: Cursor }"
+	skipped := cachedRuns(t, 6, schema,
+		"-scalar", "Money=number", "-scalar", "Cursor=string", "-scalar", "JSON=unknown", "-rename", "Query=RootQuery", "-rename", "User=Account",
+	)
+	if skipped != 5 {
+		t.Errorf("Expected the 5 runs after the first one to be skipped by the cache, got %d", skipped)
+	}
+}
+
+func TestCacheSplit(t *testing.T) {
+	schema := "enum Role { ADMIN }\ninput UserInput { role: Role }\ntype User { id: ID! }\ntype Query { me(input: UserInput): User }"
+	if skipped := cachedRuns(t, 3, schema, "-split"); skipped != 2 {
+		t.Errorf("Expected the split runs after the first one to be skipped by the cache, got %d", skipped)
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses a POSIX shell")
+	}
+	// The cache holds the hashes of the files once formatted by the hook
+	for _, args := range [][]string{{"-split"}, nil} {
+		if skipped := cachedRuns(t, 3, schema, append(args, "-post-hook", "sed -i 's/  id: string;/  id: string; /'")...); skipped != 2 {
+			t.Errorf("Expected the formatted runs %v after the first one to be skipped by the cache, got %d", args, skipped)
+		}
+	}
+}
+
+func TestApplyConfigFile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	config := `{"input": "./from-config", "prune": true, "rename": {"Event": "ApiEvent"}, "only": ["User", "Query.*"]}`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	schemaOpts := registerSchemaFlags(flags)
+	prune := flags.Bool("prune", false, "")
+	only := flags.String("only", "", "")
+
+	args := []string{"-config", configFile, "-input", "./from-args"}
+	if err := applyConfigFile(flags, args); err != nil {
+		t.Fatalf("Failed to apply config file: %v", err)
+	}
+	if err := flags.Parse(args); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	if schemaOpts.inputDir != "./from-args" {
+		t.Errorf("Expected command-line flag to take precedence, got %s", schemaOpts.inputDir)
+	}
+	if !*prune || *only != "User,Query.*" || schemaOpts.renames["Event"] != "ApiEvent" {
+		t.Errorf("Config values not applied: prune=%v only=%q renames=%v", *prune, *only, schemaOpts.renames)
+	}
+
+	if err := os.WriteFile(configFile, []byte(`{"unknown": true}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if err := applyConfigFile(flag.NewFlagSet("test", flag.ContinueOnError), args); err == nil {
+		t.Errorf("Expected unknown config option error")
+	}
+}
+
+func TestConfigEnvExpansion(t *testing.T) {
+	t.Setenv("GTG_SCHEMAS", "./schemas/staging")
+	t.Setenv("GTG_EMPTY", "")
+
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	config := `{"input": "${GTG_SCHEMAS}", "rename": {"Event": "${GTG_EMPTY:-Api}Event"}}`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	schemaOpts := registerSchemaFlags(flags)
+	if err := applyConfigFile(flags, []string{"-config", configFile}); err != nil {
+		t.Fatalf("Failed to apply config file: %v", err)
+	}
+	if schemaOpts.inputDir != "./schemas/staging" || schemaOpts.renames["Event"] != "ApiEvent" {
+		t.Errorf("Environment variables not expanded: input=%s renames=%v", schemaOpts.inputDir, schemaOpts.renames)
+	}
+
+	if err := os.WriteFile(configFile, []byte(`{"input": "${GTG_UNSET_VARIABLE}"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	flags = flag.NewFlagSet("test", flag.ContinueOnError)
+	registerSchemaFlags(flags)
+	err := applyConfigFile(flags, []string{"-config", configFile})
+	if err == nil || !strings.Contains(err.Error(), "GTG_UNSET_VARIABLE is not set") {
+		t.Errorf("Expected unset variable error, got: %v", err)
+	}
+}
+
+func TestConfigProfiles(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	config := `{"input": "./schemas", "prune": true, "profile": "local",
+		"profiles": {"local": {"input": "./local-schemas"}, "ci": {"input": "./ci-schemas", "prune": false}}}`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	tests := []struct {
+		args  []string
+		input string
+		prune bool
+	}{
+		{[]string{"-config", configFile}, "./local-schemas", true},
+		{[]string{"-config", configFile, "-profile", "ci"}, "./ci-schemas", false},
+	}
+	for _, tt := range tests {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		schemaOpts := registerSchemaFlags(flags)
+		prune := flags.Bool("prune", false, "")
+		if err := applyConfigFile(flags, tt.args); err != nil {
+			t.Fatalf("Failed to apply config file: %v", err)
+		}
+		if schemaOpts.inputDir != tt.input || *prune != tt.prune {
+			t.Errorf("%v: expected input=%s prune=%v, got input=%s prune=%v", tt.args, tt.input, tt.prune, schemaOpts.inputDir, *prune)
+		}
+	}
+
+	if _, err := loadConfig([]string{"-config", configFile, "-profile=staging"}); err == nil || !strings.Contains(err.Error(), `unknown profile "staging"`) {
+		t.Errorf("Expected unknown profile error, got: %v", err)
+	}
+}
+
+func TestConfigOutputs(t *testing.T) {
+	dir := t.TempDir()
+	inputDir := filepath.Join(dir, "schemas")
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatalf("Failed to create input directory: %v", err)
+	}
+	schema := "type User { id: ID! }\ntype Invoice { total: Int! }\ntype Query { me: User invoices: [Invoice!]! }"
+	if err := os.WriteFile(filepath.Join(inputDir, "schema.graphql"), []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	webFile := filepath.Join(dir, "web.ts")
+	billingFile := filepath.Join(dir, "billing.ts")
+	configFile := filepath.Join(dir, "config.json")
+	config := `{
+		"input": "` + inputDir + `",
+		"outputs": [
+			{"output": "` + webFile + `", "only": ["Query.me"], "prune": true},
+			{"output": "` + billingFile + `", "only": ["Invoice"], "rename": {"Invoice": "BillingInvoice"}}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout = stdout }()
+	runGenerate([]string{"-config", configFile}, nil)
+
+	fileContains(t, webFile, "export interface User {")
+	fileContains(t, billingFile, "export interface BillingInvoice {")
+	if data, _ := os.ReadFile(webFile); strings.Contains(string(data), "Invoice") {
+		t.Errorf("Expected web output without Invoice, got:\n%s", data)
+	}
+}
+
+func TestConfigProjects(t *testing.T) {
+	dir := t.TempDir()
+	for name, schema := range map[string]string{"web": "type User { id: ID! }\ntype Query { me: User }", "billing": "type Invoice { total: Int! }\ntype Query { invoices: [Invoice!]! }"} {
+		inputDir := filepath.Join(dir, name)
+		if err := os.MkdirAll(inputDir, 0755); err != nil {
+			t.Fatalf("Failed to create input directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(inputDir, "schema.graphql"), []byte(schema), 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+
+	webFile := filepath.Join(dir, "web.ts")
+	billingFile := filepath.Join(dir, "billing.ts")
+	billingDocs := filepath.Join(dir, "billing.md")
+	configFile := filepath.Join(dir, "config.json")
+	config := `{
+		"rename": {"Invoice": "BillingInvoice"},
+		"projects": {
+			"web": {"input": "` + filepath.Join(dir, "web") + `", "output": "` + webFile + `"},
+			"billing": {"input": "` + filepath.Join(dir, "billing") + `", "outputs": [
+				{"output": "` + billingFile + `"},
+				{"output": "` + billingDocs + `", "target": "docs"}
+			]}
+		}
+	}`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout = stdout }()
+
+	runGenerate([]string{"-config", configFile, "-project", "billing"}, nil)
+	fileContains(t, billingFile, "export interface BillingInvoice {")
+	fileContains(t, billingDocs, "Invoice")
+	if _, err := os.Stat(webFile); !os.IsNotExist(err) {
+		t.Errorf("Expected only the billing project to be generated")
+	}
+
+	runGenerate([]string{"-config", configFile, "-all"}, nil)
+	fileContains(t, webFile, "export interface User {")
+
+	if projects, err := configProjects([]string{"-config", configFile}); err != nil || strings.Join(projects, ",") != "billing,web" {
+		t.Errorf("Expected the sorted projects, got %v, %v", projects, err)
+	}
+	if _, err := loadConfig([]string{"-config", configFile, "-project", "admin"}); err == nil || !strings.Contains(err.Error(), `unknown project "admin"`) {
+		t.Errorf("Expected unknown project error, got: %v", err)
+	}
+}
+
+func TestConfigOutputTargets(t *testing.T) {
+	dir := t.TempDir()
+	inputDir := filepath.Join(dir, "schemas")
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatalf("Failed to create input directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(inputDir, "schema.graphql"), []byte("type User { id: ID! }\ntype Query { me: User }"), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	typesFile := filepath.Join(dir, "types.ts")
+	docsFile := filepath.Join(dir, "schema.md")
+	sdlFile := filepath.Join(dir, "schema.graphql")
+	logPath := filepath.Join(dir, "debug.log")
+	configFile := filepath.Join(dir, "config.json")
+	config := `{
+		"input": "` + inputDir + `",
+		"log-file": "` + logPath + `",
+		"outputs": [
+			{"output": "` + typesFile + `"},
+			{"output": "` + docsFile + `", "target": "docs"},
+			{"output": "` + sdlFile + `", "target": "sdl"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() {
+		os.Stdout = stdout
+		log.SetOutput(errorWriter{os.Stderr, &console{}})
+	}()
+	runGenerate([]string{"-config", configFile}, nil)
+
+	fileContains(t, typesFile, "export interface User {")
+	fileContains(t, docsFile, "## User")
+	fileContains(t, sdlFile, "type User {")
+	data, _ := os.ReadFile(logPath)
+	if parsed := strings.Count(string(data), "Parsing file: "); parsed != 1 {
+		t.Errorf("Expected the schema to be parsed once, got %d times:\n%s", parsed, data)
+	}
+	if reused := strings.Count(string(data), "Reusing parsed file: "); reused != 2 {
+		t.Errorf("Expected the parsed schema to be reused by 2 outputs, got %d:\n%s", reused, data)
+	}
+}
+
+func TestCustomRegions(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+	emit := func(content string) func(w io.Writer) error {
+		return func(w io.Writer) error {
+			_, err := io.WriteString(w, content)
+			return err
+		}
+	}
+
+	if _, err := writeOutputFile(outputFile, outputOptions{}, emit("export interface User {}\n\n")); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+	data, _ := os.ReadFile(outputFile)
+	custom := "// <custom>\nexport type UserId = User['id'];\n// </custom>\n"
+	if err := os.WriteFile(outputFile, append(data, []byte(custom)...), 0644); err != nil {
+		t.Fatalf("Failed to edit output: %v", err)
+	}
+
+	if _, err := writeOutputFile(outputFile, outputOptions{}, emit("export interface User { id: string }\n\n")); err != nil {
+		t.Fatalf("Failed to regenerate output: %v", err)
+	}
+	fileContains(t, outputFile, "export interface User { id: string }\n\n"+custom)
+
+	if err := os.WriteFile(outputFile, []byte("// <custom>\nexport type Broken = 1;\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit output: %v", err)
+	}
+	if _, err := writeOutputFile(outputFile, outputOptions{}, emit("")); err == nil || !strings.Contains(err.Error(), "unterminated") {
+		t.Errorf("Expected unterminated region error, got: %v", err)
+	}
+	fileContains(t, outputFile, "export type Broken = 1;")
+}
+
+func TestManualEditDetection(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+	opts := outputOptions{contentHash: true, failOnEdit: true}
+	emit := func(w io.Writer) error {
+		_, err := io.WriteString(w, "export interface User {}\n\n")
+		return err
+	}
+
+	if _, err := writeOutputFile(outputFile, opts, emit); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+	fileContains(t, outputFile, contentHashPrefix)
+
+	// Custom regions are not manual edits
+	data, _ := os.ReadFile(outputFile)
+	data = append(data, []byte("// <custom>\nexport type Id = string;\n// </custom>\n")...)
+	if err := os.WriteFile(outputFile, data, 0644); err != nil {
+		t.Fatalf("Failed to edit output: %v", err)
+	}
+	if _, err := writeOutputFile(outputFile, opts, emit); err != nil {
+		t.Fatalf("Expected custom regions to be accepted, got: %v", err)
+	}
+
+	data, _ = os.ReadFile(outputFile)
+	edited := strings.Replace(string(data), "User {}", "User { id: string }", 1)
+	if err := os.WriteFile(outputFile, []byte(edited), 0644); err != nil {
+		t.Fatalf("Failed to edit output: %v", err)
+	}
+	if _, err := writeOutputFile(outputFile, opts, emit); err == nil || !strings.Contains(err.Error(), "modified by hand") {
+		t.Errorf("Expected manual edit error, got: %v", err)
+	}
+	fileContains(t, outputFile, "User { id: string }")
+}
+
+func TestCheckMode(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+	emit := func(w io.Writer) error {
+		_, err := io.WriteString(w, "export interface User {}\n")
+		return err
+	}
+
+	if _, err := writeOutputFile(outputFile, outputOptions{check: true}, emit); err == nil {
+		t.Errorf("Expected a missing output to be out of date")
+	}
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		t.Errorf("Expected check mode not to write the output")
+	}
+
+	if _, err := writeOutputFile(outputFile, outputOptions{}, emit); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+	if _, err := writeOutputFile(outputFile, outputOptions{check: true}, emit); err != nil {
+		t.Errorf("Expected output to be up to date, got: %v", err)
+	}
+}
+
+func TestStreamedOutput(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "types.ts")
+	expectNoTemporaryFiles := func() {
+		t.Helper()
+		if matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp*")); len(matches) > 0 {
+			t.Errorf("Expected no temporary file left, got: %v", matches)
+		}
+	}
+
+	// A failed generation leaves no partial file
+	failing := func(w io.Writer) error {
+		io.WriteString(w, "export interface Partial {}\n")
+		return errors.New("generation failed")
+	}
+	for _, opts := range []outputOptions{{}, {contentHash: true}} {
+		if _, err := writeOutputFile(outputFile, opts, failing); err == nil || !strings.Contains(err.Error(), "generation failed") {
+			t.Errorf("Expected the generation error, got: %v", err)
+		}
+		if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+			t.Errorf("Expected no output file after a failed generation, got: %v", err)
+		}
+		expectNoTemporaryFiles()
+	}
+
+	// The file is renamed into place, with the hash of the streamed content
+	content := strings.Repeat("export interface User {}\n", 10000)
+	hash, err := writeOutputFile(outputFile, outputOptions{}, func(w io.Writer) error {
+		for _, line := range strings.SplitAfter(content, "\n") {
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+	data, _ := os.ReadFile(outputFile)
+	if string(data) != content || hash != hashContent(data) {
+		t.Errorf("Expected the streamed content and its hash, got %d bytes and hash %s", len(data), hash)
+	}
+	expectNoTemporaryFiles()
+
+	// A failed regeneration keeps the previous file
+	if _, err := writeOutputFile(outputFile, outputOptions{contentHash: true}, failing); err == nil {
+		t.Error("Expected the generation error")
+	}
+	fileContains(t, outputFile, "export interface User {}")
+	expectNoTemporaryFiles()
+}
+
+func TestLineEndings(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+	opts := outputOptions{contentHash: true, failOnEdit: true, lineEndings: lineEndingsCRLF, bom: true}
+	emit := func(w io.Writer) error {
+		_, err := io.WriteString(w, "export interface User {}\n\nexport type Id = string;\n")
+		return err
+	}
+
+	if _, err := writeOutputFile(outputFile, opts, emit); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+	data, _ := os.ReadFile(outputFile)
+	if !bytes.HasPrefix(data, utf8BOM) || !bytes.Contains(data, []byte("User {}\r\n\r\nexport")) || bytes.Contains(data, []byte("\n\n")) {
+		t.Errorf("Expected a byte order mark and CRLF line endings, got: %q", data)
+	}
+
+	// The content hash ignores the encoding, and the check compares the encoded content
+	check := opts
+	check.check = true
+	if _, err := writeOutputFile(outputFile, check, emit); err != nil {
+		t.Errorf("Expected output to be up to date, got: %v", err)
+	}
+	check.lineEndings, check.bom = lineEndingsLF, false
+	if _, err := writeOutputFile(outputFile, check, emit); err == nil {
+		t.Error("Expected output with other line endings to be out of date")
+	}
+	if _, err := writeOutputFile(outputFile, outputOptions{contentHash: true, failOnEdit: true}, emit); err != nil {
+		t.Errorf("Expected the encoding change not to be reported as a manual edit, got: %v", err)
+	}
+	data, _ = os.ReadFile(outputFile)
+	if bytes.HasPrefix(data, utf8BOM) || bytes.Contains(data, []byte("\r")) {
+		t.Errorf("Expected LF output without byte order mark, got: %q", data)
+	}
+}
+
+func TestPostHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses a POSIX shell")
+	}
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+	opts := outputOptions{contentHash: true, failOnEdit: true}
+	emit := func(w io.Writer) error {
+		_, err := io.WriteString(w, "export interface User {}\n")
+		return err
+	}
+	if _, err := writeOutputFile(outputFile, opts, emit); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+
+	// The hook receives the output path and may rewrite the file like a formatter
+	if err := runHook("sed -i 's/User {}/User {  }/'", []string{outputFile}); err != nil {
+		t.Fatalf("Failed to run hook: %v", err)
+	}
+	if err := restampContentHash(outputFile, opts); err != nil {
+		t.Fatalf("Failed to update content hash: %v", err)
+	}
+	fileContains(t, outputFile, "User {  }")
+
+	data, _ := os.ReadFile(outputFile)
+	generated, _, _ := splitCustomRegions(outputFile, data)
+	if manuallyEdited(generated) {
+		t.Errorf("Expected formatted output not to be reported as a manual edit")
+	}
+
+	// Check mode formats a copy of the new content with the hook before comparing it
+	if _, err := writeOutputFile(outputFile, outputOptions{contentHash: true, check: true}, emit); err == nil {
+		t.Errorf("Expected unformatted content to be out of date")
+	}
+	checkOpts := outputOptions{contentHash: true, check: true, postHook: "sed -i 's/User {}/User {  }/'"}
+	if _, err := writeOutputFile(outputFile, checkOpts, emit); err != nil {
+		t.Errorf("Expected formatted output to be up to date, got: %v", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(outputFile)); len(entries) != 1 {
+		t.Errorf("Expected the formatted copy to be removed, got %v", entries)
+	}
+	fileContains(t, outputFile, "User {  }")
+
+	if err := runHook("false", []string{outputFile}); err == nil {
+		t.Errorf("Expected failing hook to return an error")
+	}
+}
+
+func TestWatchRebuild(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schemas", "schema.graphql")
+	outputFile := filepath.Join(dir, "types.ts")
+	if err := os.MkdirAll(filepath.Dir(schemaFile), 0755); err != nil {
+		t.Fatalf("Failed to create input directory: %v", err)
+	}
+	writeSchema := func(schema string) {
+		if err := os.WriteFile(schemaFile, []byte(schema), 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+	stdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout = stdout }()
+
+	// Rebuilds run in process and a failure is returned instead of exiting
+	run := &generateRun{args: []string{"-input", filepath.Dir(schemaFile), "-output", outputFile}, parseCache: generator.NewParseCache(), console: &console{}}
+	writeSchema("type User { id: ID! }\ntype Query { me: User }")
+	if err := run.generate(); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	fileContains(t, outputFile, "export interface User {")
+	writeSchema("type User { id: ID! \ntype Query { me: User }")
+	if err := run.generate(); err == nil || !strings.Contains(err.Error(), "1 error(s) found") {
+		t.Errorf("Expected the parse error of the rebuild, got: %v", err)
+	}
+	writeSchema("type User { id: ID! name: String }\ntype Query { me: User }")
+	if err := run.generate(); err != nil {
+		t.Fatalf("Failed to generate after the fix: %v", err)
+	}
+	fileContains(t, outputFile, "name?: Nullable<string>;")
+}
+
+func TestLoadSchemaFilesErrors(t *testing.T) {
+	inputDir := t.TempDir()
+	for name, schema := range map[string]string{"a.graphql": "type User { id: ID! ", "b.graphql": "type Query { me: }", "c.graphql": "type Post { id: ID! }"} {
+		if err := os.WriteFile(filepath.Join(inputDir, name), []byte(schema), 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, _ = os.Open(os.DevNull)
+	errorOutput, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	os.Stderr = errorOutput
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	// Every broken file is reported before the run fails
+	run := &generateRun{args: []string{"-input", inputDir, "-output", filepath.Join(t.TempDir(), "types.ts")}, console: &console{}}
+	if err := run.generate(); err == nil || !strings.Contains(err.Error(), "2 error(s) found") {
+		t.Errorf("Expected both errors to be counted, got: %v", err)
+	}
+	errorOutput.Close()
+	fileContains(t, errorOutput.Name(), filepath.Join(inputDir, "a.graphql")+":1:")
+	fileContains(t, errorOutput.Name(), filepath.Join(inputDir, "b.graphql")+":1:")
+}
+
+// Wait until the file contains the content, failing the test after a few seconds
+func waitForContent(t *testing.T, path string, content string) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if data, err := os.ReadFile(path); err == nil && strings.Contains(string(data), content) {
+			return
+		}
+	}
+	t.Fatalf("Expected %s to contain %q", path, content)
+}
+
+func TestWatchLoadFailures(t *testing.T) {
+	dir := t.TempDir()
+	inputDir := filepath.Join(dir, "schemas")
+	outputFile := filepath.Join(dir, "types.ts")
+	writeSchema := func(schema string) {
+		if err := os.MkdirAll(inputDir, 0755); err != nil {
+			t.Fatalf("Failed to create input directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(inputDir, "schema.graphql"), []byte(schema), 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+	var remote string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		io.WriteString(w, remote)
+	}))
+	defer server.Close()
+	serve := func(schema string) {
+		mu.Lock()
+		defer mu.Unlock()
+		remote = schema
+	}
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, _ = os.Open(os.DevNull)
+	os.Stderr, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+	watch := func(input string) func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		run := &generateRun{args: []string{"-input", input, "-output", outputFile}, parseCache: generator.NewParseCache(), console: &console{}}
+		schemaOpts := &schemaFlags{inputDir: input, sources: make(map[string][]byte), console: run.console}
+		done := make(chan struct{})
+		go func() {
+			runWatch(ctx, watchOptions{interval: 2 * time.Millisecond, debounce: 5 * time.Millisecond}, schemaOpts, run.args, run)
+			close(done)
+		}()
+		return func() {
+			cancel()
+			<-done
+		}
+	}
+
+	// An input directory missing for a while, e.g. during a checkout, fails the rebuild but not the watch
+	writeSchema("type User { id: ID! }\ntype Query { me: User }")
+	stop := watch(inputDir)
+	waitForContent(t, outputFile, "export interface User {")
+	if err := os.RemoveAll(inputDir); err != nil {
+		t.Fatalf("Failed to remove input directory: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	writeSchema("type User { id: ID! name: String }\ntype Query { me: User }")
+	waitForContent(t, outputFile, "name?: Nullable<string>;")
+	stop()
+
+	// A watched URL schema is downloaded again to notice its changes
+	serve("type Post { id: ID! }\ntype Query { post: Post }")
+	stop = watch(server.URL + "/schema.graphql")
+	waitForContent(t, outputFile, "export interface Post {")
+	serve("type Post { id: ID! title: String }\ntype Query { post: Post }")
+	waitForContent(t, outputFile, "title?: Nullable<string>;")
+	stop()
+}
+
+func TestWatchLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The snapshot changes on every check for a while, like a burst of saves, then stays the same
+	checks := 0
+	snapshot := func() string {
+		checks++
+		if checks < 5 {
+			return strconv.Itoa(checks)
+		}
+		return "saved"
+	}
+	runs := 0
+	generate := func() {
+		runs++
+		if runs == 2 {
+			cancel()
+		}
+	}
+
+	opts := watchOptions{interval: time.Millisecond, debounce: 5 * time.Millisecond}
+	done := make(chan struct{})
+	go func() {
+		watchLoop(ctx, opts, snapshot, generate)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not regenerate after the changes settled")
+	}
+	if runs != 2 {
+		t.Errorf("Expected the initial run and one run after the burst of changes, got %d runs", runs)
+	}
+	if checks < 5 {
+		t.Errorf("Expected generation to wait until the changes settled, got %d checks", checks)
+	}
+}
+
+func TestFileSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.graphql")
+	if err := os.WriteFile(path, []byte("type Query { a: Int }"), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	before := fileSnapshot([]string{path}, nil)
+	if err := os.WriteFile(path, []byte("type Query { a: Int, b: Int }"), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	if fileSnapshot([]string{path}, nil) == before {
+		t.Errorf("Expected the snapshot to change with the file")
+	}
+
+	// Downloaded schemas change with their content
+	url := "https://example.com/schema.graphql"
+	before = fileSnapshot([]string{url}, map[string][]byte{url: []byte("type Query { a: Int }")})
+	if fileSnapshot([]string{url}, map[string][]byte{url: []byte("type Query { b: Int }")}) == before {
+		t.Errorf("Expected the snapshot to change with the downloaded schema")
+	}
+}
+
+func TestLockfile(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.graphql")
+	outputFile := filepath.Join(dir, "types.ts")
+	lockfilePath := filepath.Join(dir, "graphql-ts.lock")
+	for path, content := range map[string]string{schemaFile: "type Query { a: Int }", outputFile: "export {};\n"} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	hashes, _ := (&schemaFlags{}).hashFiles([]string{schemaFile})
+	lock, err := newLockfile(hashes, []string{outputFile})
+	if err != nil {
+		t.Fatalf("Failed to create lockfile: %v", err)
+	}
+	if err := lock.save(lockfilePath); err != nil {
+		t.Fatalf("Failed to save lockfile: %v", err)
+	}
+	fileContains(t, lockfilePath, `"sha256:`)
+
+	lock, err = loadLockfile(lockfilePath)
+	if err != nil {
+		t.Fatalf("Failed to load lockfile: %v", err)
+	}
+	if err := lock.verify(hashes); err != nil {
+		t.Errorf("Expected unchanged sources to verify, got: %v", err)
+	}
+
+	if err := os.WriteFile(schemaFile, []byte("type Query { a: Int, b: Int }"), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	hashes, _ = (&schemaFlags{}).hashFiles([]string{schemaFile})
+	err = lock.verify(hashes)
+	if err == nil || !strings.Contains(err.Error(), "schema file "+filepath.ToSlash(schemaFile)+" changed") {
+		t.Errorf("Expected changed schema error, got: %v", err)
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	model := func(sdl string) map[string]*tsDeclaration {
+		gen := generator.NewGenerator(generator.WithUnionEnums("Status"), generator.WithPreset(generator.PresetCodegen))
+		if err := gen.AddSource(context.Background(), "schema.graphql", sdl, ""); err != nil {
+			t.Fatalf("Failed to parse schema: %v", err)
+		}
+		var output bytes.Buffer
+		if err := gen.Emit(context.Background(), &output); err != nil {
+			t.Fatalf("Failed to generate: %v", err)
+		}
+		return parseTypescriptModel(output.String())
+	}
+	previous := model(`
+		"A user"
+		type User { id: ID! email: String! legacyId: String }
+		type Legacy { id: ID! }
+		enum Role { ADMIN USER }
+		enum Status { ACTIVE }
+		union SearchResult = User | Legacy
+		type Query { me: User search: [SearchResult!]! legacy: Legacy status: Status role: Role }
+	`)
+	current := model(`
+		"A user"
+		type User { id: ID! email: String avatar: String }
+		type Project { id: ID! }
+		enum Role { ADMIN USER GUEST }
+		enum Status { ACTIVE ARCHIVED }
+		union SearchResult = User | Project
+		type Query { me: User search: [SearchResult!]! project: Project status: Status role: Role }
+	`)
+
+	changelog := typescriptChangelog(previous, current)
+	for _, expected := range []string{
+		"### Added types\n- `Project` (type)\n",
+		"### Removed types\n- `Legacy` (type)\n",
+		"### Added fields\n- `Query.project?: Maybe<Project>`\n- `User.avatar?: Maybe<string>`\n",
+		"- `User.legacyId?: Maybe<string>`\n",
+		"### Changed fields\n- `User.email`: `email: string` → `email?: Maybe<string>`\n",
+		"### New enum values\n- `Role.Guest`\n- `Status.ARCHIVED`\n",
+		"### New union members\n- `SearchResult` now includes `Project`\n",
+		"### Removed union members\n- `SearchResult` no longer includes `Legacy`\n",
+	} {
+		if !strings.Contains(changelog, expected) {
+			t.Errorf("Expected the changelog to contain %q, got:\n%s", expected, changelog)
+		}
+	}
+	if changelog := typescriptChangelog(current, current); changelog != "No changes.\n" {
+		t.Errorf("Expected no changes, got:\n%s", changelog)
+	}
+}
+
+func TestFailOnWarn(t *testing.T) {
+	gen := generator.NewGenerator()
+	if err := gen.AddSource(context.Background(), "schema.graphql", "scalar Money\ntype Query { price: Money }", ""); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	for args, expected := range map[string]int{
+		"":                                  0,
+		"-fail-on-warn":                     1,
+		"-fail-on-warn=false":               0,
+		"-fail-on-warn=unmapped-scalar":     1,
+		"-fail-on-warn=upload-output,other": 0,
+	} {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		schemaOpts := registerSchemaFlags(flags)
+		if err := flags.Parse(strings.Fields(args)); err != nil {
+			t.Fatalf("Failed to parse %q: %v", args, err)
+		}
+		if count := schemaOpts.reportWarnings(gen); count != expected {
+			t.Errorf("Expected %d failing warning(s) with %q, got %d", expected, args, count)
+		}
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	if diff := lineDiff("a\nb\n", "a\nb\n"); diff != "" {
+		t.Errorf("Expected no diff for equal texts, got:\n%s", diff)
+	}
+
+	expected := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	actual := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+	want := "@@ line 2 @@\n  2\n  3\n  4\n- 5\n+ five\n  6\n  7\n  8\n"
+	if diff := lineDiff(expected, actual); diff != want {
+		t.Errorf("Unexpected diff:\n%s\nwant:\n%s", diff, want)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	for _, shell := range completionShells {
+		var b strings.Builder
+		if err := writeCompletion(&b, shell); err != nil {
+			t.Fatalf("Failed to write %s completion: %v", shell, err)
+		}
+		for _, want := range []string{"complexity", "output", "fixtures", "top"} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("Expected %s completion to contain %q", shell, want)
+			}
+		}
+	}
+
+	var b strings.Builder
+	if err := writeCompletion(&b, "powershell"); err == nil || !strings.Contains(err.Error(), `unknown shell "powershell"`) {
+		t.Errorf("Expected unknown shell error, got: %v", err)
+	}
+	if _, err := exec.LookPath("bash"); err == nil {
+		writeCompletion(&b, "bash")
+		cmd := exec.Command("bash", "-n")
+		cmd.Stdin = strings.NewReader(b.String())
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("Invalid bash completion: %v\n%s", err, output)
+		}
+	}
+}
+
+func TestColorOutput(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	out := &console{}
+	if out.useColor(w) {
+		t.Error("Expected no colors when not writing to a terminal")
+	}
+	if text := out.colorize(w, colorRed, "error"); text != "error" {
+		t.Errorf("Expected uncolored text, got %q", text)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		defer tty.Close()
+		if out.useColor(tty) {
+			t.Error("Expected NO_COLOR to disable colors on a terminal")
+		}
+	}
+}
+
+func TestLogFile(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "debug.log")
+	schemaOpts := &schemaFlags{logFile: logPath, bigInt: "bigint", json: "unknown", jsonObject: "record", console: &console{}}
+	gen, err := schemaOpts.newGenerator()
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	defer func() {
+		schemaOpts.console.logFile.Close()
+		log.SetOutput(errorWriter{os.Stderr, &console{}})
+	}()
+
+	if err := schemaOpts.loadSchemaFiles(context.Background(), gen, []string{"./schemas/schema1.graphql"}, nil); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	schemaOpts.console.printWarning("schema warning")
+	fileContains(t, logPath, "Processing file: ./schemas/schema1.graphql")
+	fileContains(t, logPath, "Parsing file: ./schemas/schema1.graphql")
+	fileContains(t, logPath, "schema warning")
+}
+
+func TestInputURL(t *testing.T) {
+	schema := "type Query { version: String }\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schema.graphql" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, schema)
+	}))
+	defer server.Close()
+
+	url := server.URL + "/schema.graphql"
+	schemaOpts := &schemaFlags{inputDir: url, sources: make(map[string][]byte)}
+	if _, err := schemaOpts.downloadSchema(context.Background()); err != nil {
+		t.Fatalf("Failed to download schema: %v", err)
+	}
+	data, err := schemaOpts.readSource(url)
+	if err != nil || string(data) != schema {
+		t.Fatalf("Expected downloaded schema, got %q, %v", data, err)
+	}
+	if err := verifyChecksum(url, data, "sha256:"+hashContent([]byte(schema))); err != nil {
+		t.Errorf("Expected matching checksum, got: %v", err)
+	}
+	if err := verifyChecksum(url, data, hashContent([]byte("type Query { a: Int }"))); err == nil || !strings.Contains(err.Error(), "checksum mismatch for "+url) {
+		t.Errorf("Expected checksum mismatch, got: %v", err)
+	}
+
+	if _, err := schemaOpts.http.download(context.Background(), server.URL+"/missing.graphql"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected download error, got: %v", err)
+	}
+
+	// Downloads stop with the command context, e.g. on Ctrl+C or -timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := schemaOpts.http.download(ctx, url); err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("Expected a cancelled download, got: %v", err)
+	}
+}
+
+func TestRemoteCache(t *testing.T) {
+	downloads := 0
+	download := func() ([]byte, error) {
+		downloads++
+		return []byte("type Query { version: String }\n"), nil
+	}
+	name := "https://example.com/schema.graphql"
+	cache := remoteCache{dir: filepath.Join(t.TempDir(), "remote"), ttl: time.Hour}
+
+	if _, err := (remoteCache{dir: cache.dir, offline: true}).fetch(name, download); err == nil || !strings.Contains(err.Error(), "not in the remote cache") {
+		t.Errorf("Expected a missing offline schema error, got: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if data, err := cache.fetch(name, download); err != nil || !strings.Contains(string(data), "version") {
+			t.Fatalf("Expected the schema, got %q, %v", data, err)
+		}
+	}
+	if downloads != 1 {
+		t.Errorf("Expected the second fetch to use the cache, got %d downloads", downloads)
+	}
+
+	// Expired schemas are downloaded again, unless offline
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(cache.path(name), old, old)
+	if _, err := (remoteCache{dir: cache.dir, ttl: time.Hour, offline: true}).fetch(name, download); err != nil || downloads != 1 {
+		t.Errorf("Expected the expired schema to be used offline, got %d downloads, %v", downloads, err)
+	}
+	if _, err := cache.fetch(name, download); err != nil || downloads != 2 {
+		t.Errorf("Expected the expired schema to be downloaded, got %d downloads, %v", downloads, err)
+	}
+}
+
+func TestHTTPSettings(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		} else if attempts < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		} else {
+			io.WriteString(w, "type Query { a: Int }")
+		}
+	}))
+	defer server.Close()
+
+	settings := httpSettings{timeout: time.Second, retries: 2, backoff: time.Millisecond}
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/schema.graphql", nil)
+	if resp, data, err := settings.do(req); err != nil || resp.StatusCode != http.StatusOK || attempts != 3 || string(data) != "type Query { a: Int }" {
+		t.Errorf("Expected success on the third attempt, got %d attempts, %v", attempts, err)
+	}
+	attempts = 0
+	settings.retries = 1
+	if resp, _, err := settings.do(req); err != nil || resp.StatusCode != http.StatusServiceUnavailable || attempts != 2 {
+		t.Errorf("Expected the last failed attempt after one retry, got %d attempts, %v", attempts, err)
+	}
+	attempts = 0
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/missing", nil)
+	if resp, _, _ := settings.do(req); resp.StatusCode != http.StatusNotFound || attempts != 1 {
+		t.Errorf("Expected client errors not to be retried, got %d attempts", attempts)
+	}
+
+	// The backoff ends with the context of the request, without another attempt
+	attempts = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/schema.graphql", nil)
+	start := time.Now()
+	if resp, _, _ := (httpSettings{timeout: time.Second, retries: 5, backoff: time.Hour}).do(req); resp.StatusCode != http.StatusServiceUnavailable || attempts != 1 || time.Since(start) > 10*time.Second {
+		t.Errorf("Expected the retries to stop with the context, got %d attempts after %v", attempts, time.Since(start))
+	}
+
+	// TLS servers with a private certificate authority
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "type Query { a: Int }")
+	}))
+	defer tlsServer.Close()
+	get := func(settings httpSettings) error {
+		req, _ := http.NewRequest(http.MethodGet, tlsServer.URL, nil)
+		_, _, err := settings.do(req)
+		return err
+	}
+	if err := get(httpSettings{timeout: time.Second}); err == nil {
+		t.Error("Expected an unknown certificate authority error")
+	}
+	if err := get(httpSettings{timeout: time.Second, insecure: true}); err != nil {
+		t.Errorf("Expected insecure TLS to skip verification, got: %v", err)
+	}
+	caCert := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw}), 0644)
+	if err := get(httpSettings{timeout: time.Second, caCert: caCert}); err != nil {
+		t.Errorf("Expected the CA certificate to be trusted, got: %v", err)
+	}
+}
+
+func TestRegistrySource(t *testing.T) {
+	schema := "type Query { version: String }\n"
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("X-Hive-CDN-Key") == "hive-key":
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			io.WriteString(w, schema)
+		case r.Header.Get("X-API-Key") == "apollo-key":
+			var request struct {
+				Query     string            `json:"query"`
+				Variables map[string]string `json:"variables"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			requests = append(requests, request.Variables["graph"]+"@"+request.Variables["variant"]+request.Variables["hash"])
+			if request.Variables["variant"] == "missing" {
+				io.WriteString(w, `{"data": {"graph": {"variant": null}}}`)
+				return
+			}
+			response, _ := json.Marshal(map[string]any{"data": map[string]any{"graph": map[string]any{
+				"variant": map[string]any{"latestPublication": map[string]any{"schema": map[string]any{"document": schema}}},
+				"doc":     map[string]any{"source": schema},
+			}}})
+			w.Write(response)
+		default:
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("APOLLO_KEY", "apollo-key")
+	flags := &schemaFlags{registry: "apollo", registryGraph: "shop@staging", registryEndpoint: server.URL}
+	source, err := flags.registrySource()
+	if err != nil {
+		t.Fatalf("Failed to configure registry: %v", err)
+	}
+	if data, err := source.fetch(context.Background()); err != nil || string(data) != schema {
+		t.Errorf("Expected latest Apollo schema, got %q, %v", data, err)
+	}
+	source.version = "abc123"
+	if data, err := source.fetch(context.Background()); err != nil || string(data) != schema {
+		t.Errorf("Expected pinned Apollo schema, got %q, %v", data, err)
+	}
+	source.graph, source.version = "shop@missing", ""
+	if _, err := source.fetch(context.Background()); err == nil || !strings.Contains(err.Error(), "variant missing not found") {
+		t.Errorf("Expected missing variant error, got: %v", err)
+	}
+
+	source = registrySource{registry: "hive", graph: "target-1", version: "v2", key: "hive-key", endpoint: server.URL}
+	if data, err := source.fetch(context.Background()); err != nil || string(data) != schema {
+		t.Errorf("Expected pinned Hive schema, got %q, %v", data, err)
+	}
+	source.key = "wrong"
+	if _, err := source.fetch(context.Background()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected unauthorized error, got: %v", err)
+	}
+
+	want := []string{"shop@staging", "shop@abc123", "shop@missing", "GET /target-1/version/v2/sdl"}
+	if strings.Join(requests, ", ") != strings.Join(want, ", ") {
+		t.Errorf("Unexpected registry requests: %v", requests)
+	}
+
+	// Registry requests stop with the command context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, registry := range []string{registryApollo, registryHive} {
+		source = registrySource{registry: registry, graph: "shop", key: "apollo-key", endpoint: server.URL}
+		if _, err := source.fetch(ctx); err == nil || !strings.Contains(err.Error(), "context canceled") {
+			t.Errorf("Expected a cancelled %s request, got: %v", registry, err)
+		}
+	}
+
+	t.Setenv("HIVE_CDN_KEY", "")
+	if _, err := (&schemaFlags{registry: "hive", registryGraph: "target-1"}).registrySource(); err == nil || !strings.Contains(err.Error(), "HIVE_CDN_KEY") {
+		t.Errorf("Expected missing API key error, got: %v", err)
+	}
+}
+
+func TestServe(t *testing.T) {
+	server := httptest.NewServer(newGenerateServer(&schemaFlags{bigInt: "bigint", json: "unknown", jsonObject: "record", console: &console{}}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/diagnostics")
+	if err != nil {
+		t.Fatalf("Failed to get diagnostics: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 before the first generation, got %s", resp.Status)
+	}
+
+	resp, err = http.Post(server.URL+"/generate", "application/graphql", strings.NewReader("scalar Money\ntype Query { price: Money }"))
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "export interface Query {") {
+		t.Errorf("Expected generated TypeScript, got %s:\n%s", resp.Status, body)
+	}
+
+	resp, err = http.Get(server.URL + "/diagnostics")
+	if err != nil {
+		t.Fatalf("Failed to get diagnostics: %v", err)
+	}
+	var diagnostics serveDiagnostics
+	json.NewDecoder(resp.Body).Decode(&diagnostics)
+	resp.Body.Close()
+	if diagnostics.Error != "" || len(diagnostics.Warnings) != 1 || diagnostics.Warnings[0].Code != "unmapped-scalar" {
+		t.Errorf("Expected an unmapped scalar warning, got %+v", diagnostics)
+	}
+
+	resp, err = http.Post(server.URL+"/generate?target=sdl", "application/graphql", strings.NewReader("type Query { id: }"))
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an invalid schema, got %s", resp.Status)
+	}
+	resp, _ = http.Get(server.URL + "/diagnostics")
+	json.NewDecoder(resp.Body).Decode(&diagnostics)
+	resp.Body.Close()
+	if diagnostics.Target != "sdl" || !strings.Contains(diagnostics.Error, "schema.graphql:1") {
+		t.Errorf("Expected the parse error in the diagnostics, got %+v", diagnostics)
+	}
+}
+
+func TestInstallHook(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil || runtime.GOOS == "windows" {
+		t.Skip("git and sh are required")
+	}
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer os.Chdir(wd)
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	git("init", "-q")
+	if err := os.MkdirAll(filepath.Join(repo, "web", "schemas"), 0755); err != nil {
+		t.Fatalf("Failed to create schemas directory: %v", err)
+	}
+	if err := os.Chdir(filepath.Join(repo, "web")); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	// The hook fails the commit through the generator command, here a stand-in failing -check
+	pathspecs := hookPathspecs(&schemaFlags{inputDir: "./schemas"}, "codegen.json")
+	path, err := installHook("false", []string{"-config", "codegen.json"}, pathspecs, false)
+	if err != nil {
+		t.Fatalf("Failed to install hook: %v", err)
+	}
+	fileContains(t, path, "cd 'web/' || exit 1")
+	fileContains(t, path, "git diff --cached --quiet -- 'schemas/*.graphql' 'codegen.json'")
+	fileContains(t, path, "false -check '-config' 'codegen.json' ||")
+
+	if err := os.WriteFile(filepath.Join(repo, "README"), []byte("readme"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	git("add", "README")
+	git("commit", "-q", "-m", "Unrelated change")
+
+	if err := os.WriteFile(filepath.Join(repo, "web", "schemas", "a.graphql"), []byte("type Query { a: Int }"), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	cmd := exec.Command("git", "add", "web/schemas/a.graphql")
+	cmd.Dir = repo
+	cmd.Run()
+	cmd = exec.Command("git", "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "Schema change")
+	cmd.Dir = repo
+	if output, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(output), "Generated output is out of date") {
+		t.Errorf("Expected the hook to reject the schema change, got: %v\n%s", err, output)
+	}
+
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nlint\n"), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+	if _, err := installHook("false", nil, pathspecs, false); err == nil || !strings.Contains(err.Error(), "-force") {
+		t.Errorf("Expected an existing hook to be kept, got: %v", err)
+	}
+}
+
+func TestInstallHookWithPostHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses a POSIX shell")
+	}
+	dir := t.TempDir()
+	inputDir := filepath.Join(dir, "schemas")
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatalf("Failed to create input directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(inputDir, "schema.graphql"), []byte("type User { id: ID! }\ntype Query { me: User }"), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	outputFile := filepath.Join(dir, "types.ts")
+	generateArgs := []string{"-input", inputDir, "-output", outputFile, "-post-hook", "sed -i 's/^export interface/export  interface/'"}
+	if hook := preCommitHook("generate-types", generateArgs, nil, ""); !strings.Contains(hook, "generate-types -check '-input' ") || !strings.Contains(hook, "'-post-hook' 'sed -i '\\''s/") {
+		t.Errorf("Expected the hook to check with the post-hook, got:\n%s", hook)
+	}
+
+	stdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout = stdout }()
+	runGenerate(generateArgs, nil)
+	fileContains(t, outputFile, "export  interface User {")
+
+	// The pre-commit hook runs the same arguments with -check, which exits if the formatted output is out of date
+	runGenerate(append([]string{"-check"}, generateArgs...), nil)
+	fileContains(t, outputFile, "export  interface User {")
+}
+
+func TestProfiling(t *testing.T) {
+	dir := t.TempDir()
+	cpuProfile, memProfile := filepath.Join(dir, "cpu.out"), filepath.Join(dir, "mem.out")
+	stop := startProfiling([]string{"-input", "./schemas", "-cpuprofile", cpuProfile, "-memprofile=" + memProfile})
+	stop()
+
+	for _, path := range []string{cpuProfile, memProfile} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("Expected profile %s to be written, got: %v", path, err)
+		}
+	}
+}
+
+func TestOperations(t *testing.T) {
+	dir := t.TempDir()
+	inputDir := filepath.Join(dir, "schemas")
+	outputDir := filepath.Join(dir, "operations")
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatalf("Failed to create input directory: %v", err)
+	}
+	schema := "type User { id: ID! name: String }\ntype Query { user(id: ID!): User users: [User!]! }"
+	if err := os.WriteFile(filepath.Join(inputDir, "schema.graphql"), []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+	edited := "query Users {\n  users {\n    id\n  }\n}\n"
+	if err := os.WriteFile(filepath.Join(outputDir, "users.query.graphql"), []byte(edited), 0644); err != nil {
+		t.Fatalf("Failed to write operation: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout = stdout }()
+	runOperations([]string{"-input", inputDir, "-output", outputDir, "-depth", "1"})
+
+	fileContains(t, filepath.Join(outputDir, "user.query.graphql"), "query User($id: ID!) {\n  user(id: $id) {\n    id\n    name\n  }\n}\n")
+	if data, _ := os.ReadFile(filepath.Join(outputDir, "users.query.graphql")); string(data) != edited {
+		t.Errorf("Expected the existing operation to be kept, got:\n%s", data)
+	}
+
+	runOperations([]string{"-input", inputDir, "-output", outputDir, "-force"})
+	fileContains(t, filepath.Join(outputDir, "users.query.graphql"), "    name\n")
+}
+
+func TestDeprecationsJSON(t *testing.T) {
+	dir := t.TempDir()
+	inputDir := filepath.Join(dir, "schemas")
+	operationsDir := filepath.Join(dir, "operations")
+	for _, path := range []string{inputDir, operationsDir} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+	schema := "type User { id: ID! login: String @deprecated(reason: \"Use email\") }\ntype Query { user(id: ID!): User }"
+	if err := os.WriteFile(filepath.Join(inputDir, "schema.graphql"), []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	operation := "query User($id: ID!) {\n  user(id: $id) {\n    login\n  }\n}\n"
+	if err := os.WriteFile(filepath.Join(operationsDir, "user.query.graphql"), []byte(operation), 0644); err != nil {
+		t.Fatalf("Failed to write operation: %v", err)
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer reader.Close()
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout = writer
+	os.Stderr, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(&buf, reader)
+		close(done)
+	}()
+	runDeprecations([]string{"-input", inputDir, "-operations", operationsDir, "-format", "json"})
+	writer.Close()
+	<-done
+
+	var report deprecationReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Expected a JSON report, got %v:\n%s", err, buf.String())
+	}
+	expected := deprecationUsage{
+		Field:      "User.login",
+		Reason:     "Use email",
+		Definition: "query User",
+		Position:   filepath.Join(operationsDir, "user.query.graphql") + ":3:5",
+	}
+	if report.Documents != 1 || len(report.Usages) != 1 || report.Usages[0] != expected {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	gen := generator.NewGenerator(generator.WithPreset(generator.PresetCodegen))
+	if err := gen.AddSource(context.Background(), "schema.graphql", `
+		type User { id: ID! name: String tags: [String]! posts(first: Int): [Post!]! }
+		type Post { id: ID! }
+		input UserFilter { name: String }
+		union SearchResult = User | Post
+		type Query { users(filter: UserFilter): [User!]! search: [SearchResult!]! }
+	`, ""); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	var output bytes.Buffer
+	if err := gen.Emit(context.Background(), &output); err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	output.WriteString(`
+/** Something with an id */
+export interface Entity {
+    readonly id: string;
+}
+
+export interface Team extends Entity {
+    /** Display name */
+    name: string;
+    members?: User[] | null;
+    createdAt: Date;
+    handler: () => void;
+}
+
+export enum Level {
+  Low = 'LOW',
+  HIGH = 10,
+}
+`)
+
+	sdl, warnings := typescriptSDL(parseTypescriptModel(output.String()))
+	for _, expected := range []string{
+		"scalar DateTime\n\nscalar JSON\n\n",
+		"type User {\n  id: ID!\n  name: String\n  tags: [String]!\n  posts(first: Float): [Post!]!\n}\n",
+		"input UserFilter {\n  name: String\n}\n",
+		"union SearchResult = User | Post\n",
+		"type Query {\n  search: [SearchResult!]!\n  users(filter: UserFilter): [User!]!\n}\n",
+		"\"Something with an id\"\ninterface Entity {\n  id: ID!\n}\n",
+		"type Team implements Entity {\n  id: ID!\n  \"Display name\"\n  name: String!\n  members: [User!]\n  createdAt: DateTime!\n  handler: JSON!\n}\n",
+		"enum Level {\n  LOW\n  HIGH\n}\n",
+	} {
+		if !strings.Contains(sdl, expected) {
+			t.Errorf("Expected the SDL to contain %q, got:\n%s", expected, sdl)
+		}
+	}
+	if strings.Contains(sdl, "Args") || strings.Contains(sdl, "Scalars") {
+		t.Errorf("Expected the arguments and helpers to be left out, got:\n%s", sdl)
+	}
+	if len(warnings) != 1 || warnings[0] != "Team.handler: unsupported type () => void, converted to JSON" {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+	if err := generator.NewGenerator().AddSource(context.Background(), "reverse.graphql", sdl, ""); err != nil {
+		t.Errorf("Expected a valid schema: %v", err)
+	}
+}
+
+func TestReverseRoundTrip(t *testing.T) {
+	schema := `
+		"A node"
+		interface Node { id: ID! }
+		type User implements Node { id: ID! name: String delete: Boolean! role: Role! }
+		type Project implements Node { id: ID! title: String! owner: User }
+		enum Role { ADMIN MEMBER }
+		union SearchResult = User | Project
+		input CreateUserInput { name: String! role: Role }
+		type Query { users: [User!]! search: [SearchResult!]! }
+		type Mutation { createUser(input: CreateUserInput!): User! }
+	`
+	options := []generator.Option{
+		generator.WithPartialInputs(true), generator.WithInputCoercion(true), generator.WithImplementerUnions(true),
+		generator.WithOperationNames(true), generator.WithResultAliases(true), generator.WithTypeNameUnion(true),
+	}
+	generate := func(sdl string) string {
+		gen := generator.NewGenerator(options...)
+		if err := gen.AddSource(context.Background(), "schema.graphql", sdl, ""); err != nil {
+			t.Fatalf("Failed to parse schema: %v\n%s", err, sdl)
+		}
+		var output bytes.Buffer
+		if err := gen.Emit(context.Background(), &output); err != nil {
+			t.Fatalf("Failed to generate: %v\n%s", err, sdl)
+		}
+		return output.String()
+	}
+
+	// The SDL of the generated code generates the same code: quoted keys are fields and the helpers are left out
+	output := generate(schema)
+	sdl, warnings := typescriptSDL(parseTypescriptModel(output))
+	if len(warnings) != 0 {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+	for _, helper := range []string{"PartialCreateUserInput", "InputCoercionError", "NodeTypes", "OperationName", "TypeName", "UsersResult", "QuerySearchResult"} {
+		if strings.Contains(sdl, helper) {
+			t.Errorf("Expected the %s helper to be left out, got:\n%s", helper, sdl)
+		}
+	}
+	if !strings.Contains(sdl, "  delete: Boolean!\n") {
+		t.Errorf("Expected the quoted delete key to be a field, got:\n%s", sdl)
+	}
+	if regenerated := generate(sdl); regenerated != output {
+		t.Errorf("Expected the round trip to generate the same code, got:\n%s\nfrom:\n%s", regenerated, sdl)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func registerOperationsFlags(flags *flag.FlagSet) (*schemaFlags, *string, *int, *bool) {
+	schemaOpts := registerSchemaFlags(flags)
+	output := flags.String("output", "./operations", "Directory of the generated operation documents")
+	depth := flags.Int("depth", 2, "Levels of object fields selected in each operation, counting the result of the root field")
+	force := flags.Bool("force", false, "Overwrite operation documents that already exist")
+	return schemaOpts, output, depth, force
+}
+
+// Write a .graphql operation document for each Query and Mutation field. Existing documents are kept,
+// as they are meant to be edited, unless -force is given.
+func runOperations(args []string) {
+	flags := flag.NewFlagSet("operations", flag.ExitOnError)
+	schemaOpts, output, depth, force := registerOperationsFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := schemaOpts.context()
+	defer cancel()
+
+	gen, err := schemaOpts.loadSchema(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	files, err := gen.OperationDocuments(ctx, *depth)
+	if err != nil {
+		log.Fatalf("Error generating operations: %v", err)
+	}
+	if err := os.MkdirAll(*output, 0755); err != nil {
+		log.Fatalf("Error generating operations: could not create directory: %v", err)
+	}
+
+	written, skipped := 0, 0
+	for _, file := range files {
+		path := filepath.Join(*output, file.Name)
+		if _, err := os.Stat(path); err == nil && !*force {
+			skipped++
+			continue
+		}
+		if err := os.WriteFile(path, file.Content, 0644); err != nil {
+			log.Fatalf("Error writing operation: %v", err)
+		}
+		written++
+	}
+	schemaOpts.console.printSuccess("Operation documents written: %d, existing kept: %d. Directory: %s", written, skipped, *output)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	runtimepprof "runtime/pprof"
+)
+
+// Start the CPU profile of the -cpuprofile argument and return the function writing the profiles,
+// including the heap profile of -memprofile. The arguments are read before the command parses its
+// flags, so that the whole run is profiled.
+func startProfiling(args []string) func() {
+	cpuProfile, memProfile := argValue(args, "cpuprofile"), argValue(args, "memprofile")
+	var cpuFile *os.File
+	if cpuProfile != "" {
+		file, err := os.Create(cpuProfile)
+		if err == nil {
+			err = runtimepprof.StartCPUProfile(file)
+		}
+		if err != nil {
+			log.Fatalf("Error starting CPU profile: %v", err)
+		}
+		cpuFile = file
+	}
+
+	return func() {
+		if cpuFile != nil {
+			runtimepprof.StopCPUProfile()
+			cpuFile.Close()
+		}
+		if memProfile != "" {
+			file, err := os.Create(memProfile)
+			if err == nil {
+				runtime.GC()
+				err = runtimepprof.WriteHeapProfile(file)
+				file.Close()
+			}
+			if err != nil {
+				log.Fatalf("Error writing memory profile: %v", err)
+			}
+		}
+	}
+}
+
+// Serve the pprof endpoints under /debug/pprof/ in the background, for the long-running commands
+func servePprof(addr string, out *console) {
+	if addr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			out.printError("pprof server stopped: " + err.Error())
+		}
+	}()
+	out.printSuccess("Serving pprof on http://%s/debug/pprof/", addr)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Markers of the hand-written regions kept when an output file is regenerated
+const (
+	customStart = "// <custom>"
+	customEnd   = "// </custom>"
+)
+
+// Split the content of an existing output file into the generated content and its custom regions, markers included.
+// The blank line following a region is part of it. An unterminated region is an error so that no hand-written code is lost.
+func splitCustomRegions(path string, data []byte) (generated []byte, regions []byte, err error) {
+	var generatedBuf, regionsBuf bytes.Buffer
+	inside, afterRegion := false, false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		switch strings.TrimSpace(text) {
+		case customStart:
+			if inside {
+				return nil, nil, fmt.Errorf("%s:%d: nested %s region", path, line, customStart)
+			}
+			inside = true
+		case customEnd:
+			if !inside {
+				return nil, nil, fmt.Errorf("%s:%d: %s without %s", path, line, customEnd, customStart)
+			}
+			regionsBuf.WriteString(text + "\n\n")
+			inside, afterRegion = false, true
+			continue
+		}
+
+		if inside {
+			regionsBuf.WriteString(text + "\n")
+		} else if !afterRegion || text != "" {
+			generatedBuf.WriteString(text + "\n")
+		}
+		afterRegion = false
+	}
+	if inside {
+		return nil, nil, fmt.Errorf("%s: unterminated %s region", path, customStart)
+	}
+	return generatedBuf.Bytes(), regionsBuf.Bytes(), nil
+}
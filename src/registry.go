@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Schema registries
+const (
+	registryApollo = "apollo"
+	registryHive   = "hive"
+)
+
+// Default endpoints: the Apollo Platform API and the GraphQL Hive CDN
+var registryEndpoints = map[string]string{
+	registryApollo: "https://api.apollographql.com/api/graphql",
+	registryHive:   "https://cdn.graphql-hive.com/artifacts/v1",
+}
+
+// Environment variables holding the API key when -registry-key is not set
+var registryKeyVariables = map[string]string{
+	registryApollo: "APOLLO_KEY",
+	registryHive:   "HIVE_CDN_KEY",
+}
+
+const (
+	apolloLatestQuery = `query LatestSchema($graph: ID!, $variant: String!) {
+  graph(id: $graph) { variant(name: $variant) { latestPublication { schema { document } } } }
+}`
+	apolloHashQuery = `query SchemaByHash($graph: ID!, $hash: SHA256!) {
+  graph(id: $graph) { doc(hash: $hash) { source } }
+}`
+)
+
+// A schema published to a registry
+type registrySource struct {
+	registry string
+	// Apollo graph ref, as graph@variant, or Hive target id
+	graph string
+	// Pinned version: Apollo schema hash or Hive version id, latest if empty
+	version  string
+	key      string
+	endpoint string
+	// HTTP client settings of the requests
+	http httpSettings
+}
+
+func (f *schemaFlags) registrySource() (registrySource, error) {
+	source := registrySource{f.registry, f.registryGraph, f.registryVersion, f.registryKey, f.registryEndpoint, f.http}
+	if _, found := registryEndpoints[source.registry]; !found {
+		return source, fmt.Errorf("unknown registry %q, expected %s or %s", source.registry, registryApollo, registryHive)
+	}
+	if source.graph == "" {
+		return source, fmt.Errorf("-registry requires -registry-graph")
+	}
+	if source.key == "" {
+		source.key = os.Getenv(registryKeyVariables[source.registry])
+	}
+	if source.key == "" {
+		return source, fmt.Errorf("%s registry requires an API key: set -registry-key or %s", source.registry, registryKeyVariables[source.registry])
+	}
+	if source.endpoint == "" {
+		source.endpoint = registryEndpoints[source.registry]
+	}
+	return source, nil
+}
+
+// Name of the schema in errors, the cache and the lockfile, such as apollo:my-graph@current
+func (r registrySource) name() string {
+	name := r.registry + ":" + r.graph
+	if r.version != "" {
+		name += "#" + r.version
+	}
+	return name
+}
+
+// Download the schema SDL, until the context is cancelled
+func (r registrySource) fetch(ctx context.Context) ([]byte, error) {
+	if r.registry == registryApollo {
+		return r.fetchApollo(ctx)
+	}
+	return r.fetchHive(ctx)
+}
+
+func (r registrySource) fetchApollo(ctx context.Context) ([]byte, error) {
+	graph, variant, found := strings.Cut(r.graph, "@")
+	if !found {
+		variant = "current"
+	}
+	request := map[string]any{
+		"query":     apolloLatestQuery,
+		"variables": map[string]string{"graph": graph, "variant": variant},
+	}
+	if r.version != "" {
+		request = map[string]any{
+			"query":     apolloHashQuery,
+			"variables": map[string]string{"graph": graph, "hash": r.version},
+		}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", r.key)
+	req.Header.Set("apollographql-client-name", "graphql-ts-generator")
+
+	data, err := r.do(req)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Data struct {
+			Graph *struct {
+				Variant *struct {
+					LatestPublication *struct {
+						Schema struct {
+							Document string `json:"document"`
+						} `json:"schema"`
+					} `json:"latestPublication"`
+				} `json:"variant"`
+				Doc *struct {
+					Source string `json:"source"`
+				} `json:"doc"`
+			} `json:"graph"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("invalid response from %s: %v", r.endpoint, err)
+	}
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("could not fetch %s: %s", r.name(), response.Errors[0].Message)
+	}
+
+	switch graphData := response.Data.Graph; {
+	case graphData == nil:
+		return nil, fmt.Errorf("could not fetch %s: graph %s not found or not accessible with the API key", r.name(), graph)
+	case r.version != "":
+		if graphData.Doc == nil {
+			return nil, fmt.Errorf("could not fetch %s: no schema with hash %s", r.name(), r.version)
+		}
+		return []byte(graphData.Doc.Source), nil
+	case graphData.Variant == nil:
+		return nil, fmt.Errorf("could not fetch %s: variant %s not found", r.name(), variant)
+	case graphData.Variant.LatestPublication == nil:
+		return nil, fmt.Errorf("could not fetch %s: no schema published to variant %s", r.name(), variant)
+	default:
+		return []byte(graphData.Variant.LatestPublication.Schema.Document), nil
+	}
+}
+
+func (r registrySource) fetchHive(ctx context.Context) ([]byte, error) {
+	url := strings.TrimSuffix(r.endpoint, "/") + "/" + r.graph
+	if r.version != "" {
+		url += "/version/" + r.version
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/sdl", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Hive-CDN-Key", r.key)
+	return r.do(req)
+}
+
+// Send a registry request and return the body of a successful response
+func (r registrySource) do(req *http.Request) ([]byte, error) {
+	resp, data, err := r.http.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch %s: %v", r.name(), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch %s: %s", r.name(), resp.Status)
+	}
+	return data, nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Time limit of a schema download
+const downloadTimeout = 30 * time.Second
+
+// Settings of the HTTP client downloading schemas from URLs and registries
+type httpSettings struct {
+	// Time limit of each attempt
+	timeout time.Duration
+	// Attempts after a network error, a 429 or a 5xx response
+	retries int
+	// Delay before the first retry, doubled for each of the next ones
+	backoff time.Duration
+	// Proxy URL, HTTP_PROXY, HTTPS_PROXY and NO_PROXY from the environment if empty
+	proxy string
+	// PEM file of additional certificate authorities, such as a corporate one
+	caCert string
+	// Skip the verification of TLS certificates
+	insecure bool
+}
+
+// Return an HTTP client with the configured timeout, proxy and certificate authorities
+func (s httpSettings) client() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if s.proxy != "" {
+		proxy, err := url.Parse(s.proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %v", s.proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if s.caCert != "" || s.insecure {
+		config := &tls.Config{InsecureSkipVerify: s.insecure}
+		if s.caCert != "" {
+			pem, err := os.ReadFile(s.caCert)
+			if err != nil {
+				return nil, fmt.Errorf("could not read CA certificate: %v", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificate found in %s", s.caCert)
+			}
+			config.RootCAs = pool
+		}
+		transport.TLSClientConfig = config
+	}
+	return &http.Client{Timeout: s.timeout, Transport: transport}, nil
+}
+
+// Send a request and return its response with the body read. Network errors, 429 and 5xx responses
+// are retried with an exponential backoff, and the last attempt is returned.
+// Retries stop when the context of the request is cancelled.
+func (s httpSettings) do(req *http.Request) (*http.Response, []byte, error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, nil, err
+	}
+	delay := s.backoff
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, nil, err
+			}
+		}
+		resp, data, err := send(client, req)
+		retryable := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retryable || attempt >= s.retries || req.Context().Err() != nil {
+			return resp, data, err
+		}
+		select {
+		case <-req.Context().Done():
+			return resp, data, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// Send one request and read the body of its response
+func send(client *http.Client, req *http.Request) (*http.Response, []byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, data, nil
+}
+
+func isURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// Read a schema file. URL and registry schemas are looked up by name, as they are downloaded
+// by collectFiles before the files are read.
+func (f *schemaFlags) readSource(path string) ([]byte, error) {
+	if data, found := f.sources[path]; found {
+		return data, nil
+	}
+	return os.ReadFile(path)
+}
+
+// Download a schema file, until the context is cancelled
+func (s httpSettings) download(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not download %s: %v", path, err)
+	}
+	resp, data, err := s.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not download %s: %v", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download %s: %s", path, resp.Status)
+	}
+	return data, nil
+}
+
+// Download the registry or URL schema, or load it from the remote cache, and check its checksum,
+// returning the name it is read with
+func (f *schemaFlags) downloadSchema(ctx context.Context) (string, error) {
+	path := f.inputDir
+	fetch := func() ([]byte, error) {
+		return f.http.download(ctx, path)
+	}
+	if f.registry != "" {
+		source, err := f.registrySource()
+		if err != nil {
+			return "", err
+		}
+		path = source.name()
+		fetch = func() ([]byte, error) {
+			return source.fetch(ctx)
+		}
+	}
+	if _, found := f.sources[path]; !found {
+		data, err := f.remoteCache().fetch(path, fetch)
+		if err != nil {
+			return "", err
+		}
+		f.sources[path] = data
+	}
+
+	data, err := f.readSource(path)
+	if err == nil && f.inputChecksum != "" {
+		err = verifyChecksum(path, data, f.inputChecksum)
+	}
+	return path, err
+}
+
+// Cache of the downloaded schemas on disk, so that runs within the TTL or offline do not download them again
+type remoteCache struct {
+	// Directory of the cached schemas, caching is disabled if empty
+	dir string
+	// Age after which a cached schema is downloaded again
+	ttl time.Duration
+	// Only use cached schemas, whatever their age
+	offline bool
+}
+
+func (f *schemaFlags) remoteCache() remoteCache {
+	return remoteCache{dir: f.remoteCacheDir, ttl: f.remoteCacheTTL, offline: f.offline}
+}
+
+// Return the cache file of a schema URL or registry name
+func (c remoteCache) path(name string) string {
+	return filepath.Join(c.dir, hashContent([]byte(name))+".graphql")
+}
+
+// Return the cached schema if it is younger than the TTL, or any cached schema offline.
+// Otherwise download it and update the cache.
+func (c remoteCache) fetch(name string, download func() ([]byte, error)) ([]byte, error) {
+	if c.dir == "" {
+		if c.offline {
+			return nil, fmt.Errorf("cannot load %s offline without a -remote-cache directory", name)
+		}
+		return download()
+	}
+
+	path := c.path(name)
+	if info, err := os.Stat(path); err == nil && (c.offline || time.Since(info.ModTime()) < c.ttl) {
+		return os.ReadFile(path)
+	}
+	if c.offline {
+		return nil, fmt.Errorf("cannot load %s offline: it is not in the remote cache %s", name, c.dir)
+	}
+
+	data, err := download()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create remote cache: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("could not write remote cache: %v", err)
+	}
+	return data, nil
+}
+
+// Check the sha256 checksum of a downloaded schema, given as sha256:<hex> or <hex>
+func verifyChecksum(path string, data []byte, checksum string) error {
+	expected := strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+	if actual := hashContent(data); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected sha256:%s, got sha256:%s", path, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,584 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"graphql-ts-generator/generator"
+)
+
+// GraphQL scalars of TypeScript types. Numbers become Float, as TypeScript does not tell integers apart,
+// and string fields named id become ID.
+var reverseScalars = map[string]string{
+	"string":  "String",
+	"number":  "Float",
+	"boolean": "Boolean",
+	"Date":    "DateTime",
+	"bigint":  "BigInt",
+	"unknown": "JSON",
+	"any":     "JSON",
+	"object":  "JSON",
+}
+
+var builtinScalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+var (
+	// Reference to the Scalars helper of the generated code, e.g. Scalars['Int'] or Scalars['Int']['output']
+	tsScalarsPattern    = regexp.MustCompile(`^Scalars\['([_A-Za-z][_0-9A-Za-z]*)'\](?:\['(?:input|output)'\])?$`)
+	graphqlNamePattern  = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+	tsIdentifierPattern = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+)
+
+func registerReverseFlags(flags *flag.FlagSet) (*string, *string) {
+	input := flags.String("input", "./generated-types.ts", "Comma-separated TypeScript files, or directories of .ts files, whose exported interfaces, types and enums are converted")
+	output := flags.String("output", "-", "Path of the written SDL file, - for stdout")
+	return input, output
+}
+
+// Write the GraphQL SDL of TypeScript declarations, such as a previous output of the generator with
+// hand-written additions, to bootstrap a schema from an existing client model. Experimental: only the layout
+// of the generated code is understood, and the result is meant to be reviewed.
+func runReverse(args []string) {
+	flags := flag.NewFlagSet("reverse", flag.ExitOnError)
+	input, output := registerReverseFlags(flags)
+	flags.Parse(args)
+	out := &console{}
+
+	var paths []string
+	for _, path := range splitList(*input) {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			paths = append(paths, splitOutputFiles(path, ".ts")...)
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			log.Fatalf("Error reading TypeScript declarations: %v", err)
+		}
+		paths = append(paths, path)
+	}
+	model, err := readTypescriptModel(paths)
+	if err != nil {
+		log.Fatalf("Error reading TypeScript declarations: %v", err)
+	}
+
+	sdl, warnings := typescriptSDL(model)
+	for _, warning := range warnings {
+		out.printWarning(warning)
+	}
+	if err := generator.NewGenerator().AddSource(context.Background(), "reverse.graphql", sdl, ""); err != nil {
+		out.printWarning(fmt.Sprintf("The generated SDL is not a valid schema: %v", err))
+	}
+
+	if *output == "-" {
+		os.Stdout.WriteString(sdl)
+		return
+	}
+	if err := os.WriteFile(*output, []byte(sdl), 0644); err != nil {
+		log.Fatalf("Error writing SDL: could not write %s: %v", *output, err)
+	}
+	out.printSuccess("SDL of %d TypeScript file(s) written to %s.", len(paths), *output)
+}
+
+// Conversion of a TypeScript model to GraphQL SDL
+type reverseSDL struct {
+	model map[string]*tsDeclaration
+	// GraphQL kind of each converted declaration: type, interface, input, enum, union or scalar
+	kinds map[string]string
+	// Declarations holding the arguments of a field, by Type.field, e.g. UserPostsArgs for User.posts
+	arguments map[string]*tsDeclaration
+	// Scalars referenced by the fields, declared before the types
+	scalars  map[string]bool
+	warnings []string
+}
+
+// Convert TypeScript declarations to SDL, in declaration order. Interfaces and object types become types,
+// or GraphQL interfaces when other declarations extend them, and input types when their name ends with Input
+// or they are used by arguments. Generic helpers, the Scalars helper and the XyzArgs declarations of the arguments
+// of fields are not converted, nor the other helpers generated with the types, such as PartialCreateUserInput
+// or OperationName, unless a converted declaration references them. The warnings describe the members that
+// could not be converted.
+func typescriptSDL(model map[string]*tsDeclaration) (string, []string) {
+	for _, decl := range model {
+		for i := range decl.members {
+			decl.members[i].name = unquoteKey(decl.members[i].name)
+		}
+	}
+	implementInterfaces(model)
+
+	// The helpers are those the generator would add to the schema of a first conversion
+	sdl, warnings := reverseTypescript(model, nil)
+	gen := generator.NewGenerator()
+	if err := gen.AddSource(context.Background(), "reverse.graphql", sdl, ""); err != nil {
+		return sdl, warnings
+	}
+	helpers := make(map[string]bool)
+	for name := range gen.HelperNames() {
+		if _, declared := model[name]; declared {
+			helpers[name] = true
+		}
+	}
+	if len(helpers) == 0 {
+		return sdl, warnings
+	}
+	return reverseTypescript(model, helpers)
+}
+
+// Convert TypeScript declarations to SDL, leaving out the given helpers
+func reverseTypescript(model map[string]*tsDeclaration, helpers map[string]bool) (string, []string) {
+	r := &reverseSDL{model: model, kinds: make(map[string]string), arguments: make(map[string]*tsDeclaration), scalars: make(map[string]bool)}
+
+	var names []string
+	for _, name := range sortedNames(model) {
+		if decl := model[name]; !decl.generic && name != "Scalars" {
+			names = append(names, name)
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool { return model[names[i]].position < model[names[j]].position })
+
+	argumentDecls := make(map[string]bool)
+	for _, name := range names {
+		if !isObjectDeclaration(model[name]) {
+			continue
+		}
+		for _, member := range model[name].members {
+			argsName := name + strings.ToUpper(member.name[:1]) + member.name[1:] + "Args"
+			if args, found := model[argsName]; found && isObjectDeclaration(args) {
+				r.arguments[name+"."+member.name] = args
+				argumentDecls[argsName] = true
+			}
+		}
+	}
+	referenced := make(map[string]bool)
+	for _, name := range names {
+		if helpers[name] {
+			continue
+		}
+		for _, parent := range model[name].extends {
+			referenced[parent] = true
+		}
+		for _, member := range model[name].members {
+			if model[name].kind == "union" {
+				referenced[member.name] = true
+			}
+			for _, target := range namedTypes(member.signature) {
+				referenced[target] = true
+			}
+		}
+	}
+	var converted []string
+	for _, name := range names {
+		if !argumentDecls[name] && (!helpers[name] || referenced[name]) {
+			converted = append(converted, name)
+		}
+	}
+	r.classify(converted)
+
+	var b strings.Builder
+	declarations := make([]string, 0, len(converted))
+	for _, name := range converted {
+		if declaration := r.declaration(name); declaration != "" {
+			declarations = append(declarations, declaration)
+		}
+	}
+	var scalars []string
+	for name := range r.scalars {
+		if _, declared := r.kinds[name]; !declared {
+			scalars = append(scalars, name)
+		}
+	}
+	sort.Strings(scalars)
+	for _, name := range scalars {
+		b.WriteString("scalar " + name + "\n\n")
+	}
+	b.WriteString(strings.Join(declarations, "\n"))
+	return b.String(), r.warnings
+}
+
+// Make the members of the implementer unions, such as `NodeTypes = Project | User`, extend their interface,
+// as the generated declarations of the types implementing an interface don't extend it. The members must
+// have every property of the interface.
+func implementInterfaces(model map[string]*tsDeclaration) {
+	for _, name := range sortedNames(model) {
+		iface, found := model[strings.TrimSuffix(name, "Types")]
+		if model[name].kind != "union" || !strings.HasSuffix(name, "Types") || !found || !isObjectDeclaration(iface) {
+			continue
+		}
+		parent := strings.TrimSuffix(name, "Types")
+		implementers := make([]*tsDeclaration, 0, len(model[name].members))
+		for _, member := range model[name].members {
+			if decl, found := model[member.name]; found && isObjectDeclaration(decl) && hasMembers(decl, iface.members) {
+				implementers = append(implementers, decl)
+			}
+		}
+		if len(implementers) != len(model[name].members) {
+			continue
+		}
+		for _, decl := range implementers {
+			if !slices.Contains(decl.extends, parent) {
+				decl.extends = append(decl.extends, parent)
+			}
+		}
+	}
+}
+
+// Check whether a declaration has a member of each name
+func hasMembers(decl *tsDeclaration, members []tsMember) bool {
+	for _, member := range members {
+		if !slices.ContainsFunc(decl.members, func(own tsMember) bool { return own.name == member.name }) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check whether a declaration is an interface, class or object type with properties
+func isObjectDeclaration(decl *tsDeclaration) bool {
+	return decl.kind == "interface" || decl.kind == "class" || decl.kind == "type"
+}
+
+// Set the GraphQL kind of each declaration
+func (r *reverseSDL) classify(names []string) {
+	var inputs []string
+	for _, name := range names {
+		decl := r.model[name]
+		switch {
+		case decl.kind == "enum":
+			r.kinds[name] = "enum"
+		case decl.kind == "union":
+			r.kinds[name] = "union"
+			for _, member := range decl.members {
+				if target, found := r.model[member.name]; !found || !isObjectDeclaration(target) {
+					// Alias of another type, such as `type UserId = string;`
+					r.kinds[name] = "scalar"
+				}
+			}
+		case strings.HasSuffix(name, "Input"):
+			r.kinds[name] = "input"
+			inputs = append(inputs, name)
+		default:
+			r.kinds[name] = "type"
+		}
+	}
+	for _, name := range names {
+		for _, parent := range r.model[name].extends {
+			if r.kinds[parent] == "type" && r.kinds[name] != "input" {
+				r.kinds[parent] = "interface"
+			}
+		}
+	}
+
+	// The types used by arguments, and by the fields of other input types, are input types
+	for _, args := range r.arguments {
+		for _, member := range args.members {
+			inputs = append(inputs, namedTypes(member.signature)...)
+		}
+	}
+	for len(inputs) > 0 {
+		name := inputs[0]
+		inputs = inputs[1:]
+		if kind := r.kinds[name]; kind != "type" && kind != "input" {
+			continue
+		}
+		if r.kinds[name] == "type" {
+			r.kinds[name] = "input"
+		}
+		for _, member := range r.fields(name) {
+			for _, target := range namedTypes(member.signature) {
+				if r.kinds[target] == "type" {
+					inputs = append(inputs, target)
+				}
+			}
+		}
+	}
+}
+
+// Return the identifiers of a TypeScript type, e.g. Nullable, Array and User for Nullable<Array<User>>
+func namedTypes(tsType string) []string {
+	return tsIdentifierPattern.FindAllString(tsType, -1)
+}
+
+// Return the properties of an object declaration, after the properties inherited from the declarations
+// it extends that it does not redeclare, as GraphQL types repeat the fields of their interfaces
+func (r *reverseSDL) fields(name string) []tsMember {
+	return r.inheritedFields(name, make(map[string]bool))
+}
+
+func (r *reverseSDL) inheritedFields(name string, visited map[string]bool) []tsMember {
+	decl, found := r.model[name]
+	if !found || visited[name] || !isObjectDeclaration(decl) {
+		return nil
+	}
+	visited[name] = true
+	own := make(map[string]tsMember, len(decl.members))
+	for _, member := range decl.members {
+		own[member.name] = member
+	}
+	var fields []tsMember
+	seen := make(map[string]bool)
+	for _, parent := range decl.extends {
+		for _, member := range r.inheritedFields(parent, visited) {
+			if seen[member.name] {
+				continue
+			}
+			seen[member.name] = true
+			if redeclared, found := own[member.name]; found {
+				member = redeclared
+			}
+			fields = append(fields, member)
+		}
+	}
+	for _, member := range decl.members {
+		if !seen[member.name] {
+			seen[member.name] = true
+			fields = append(fields, member)
+		}
+	}
+	return fields
+}
+
+// Return the SDL of a declaration, or nothing if it has nothing to convert
+func (r *reverseSDL) declaration(name string) string {
+	if !graphqlNamePattern.MatchString(name) {
+		r.warnf("%s: not a valid GraphQL name, skipped", name)
+		return ""
+	}
+	decl := r.model[name]
+	var b strings.Builder
+	b.WriteString(sdlDescription(decl.description, ""))
+	switch kind := r.kinds[name]; kind {
+	case "scalar":
+		b.WriteString("scalar " + name + "\n")
+	case "union":
+		members := make([]string, len(decl.members))
+		for i, member := range decl.members {
+			members[i] = member.name
+		}
+		b.WriteString("union " + name + " = " + strings.Join(members, " | ") + "\n")
+	case "enum":
+		b.WriteString("enum " + name + " {\n")
+		for _, member := range decl.members {
+			value := enumValueName(member)
+			if !graphqlNamePattern.MatchString(value) {
+				r.warnf("%s.%s: not a valid GraphQL enum value, skipped", name, member.name)
+				continue
+			}
+			b.WriteString(sdlDescription(member.description, "  ") + "  " + value + "\n")
+		}
+		b.WriteString("}\n")
+	default:
+		b.WriteString(kind + " " + name)
+		var interfaces []string
+		for _, parent := range decl.extends {
+			if r.kinds[parent] == "interface" {
+				interfaces = append(interfaces, parent)
+			}
+		}
+		if len(interfaces) > 0 && kind != "input" {
+			b.WriteString(" implements " + strings.Join(interfaces, " & "))
+		}
+		b.WriteString(" {\n")
+		for _, member := range r.fields(name) {
+			if field := r.field(name, member); field != "" {
+				b.WriteString(sdlDescription(member.description, "  ") + "  " + field + "\n")
+			}
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// Return the GraphQL name of an enum value: its string value, e.g. ADMIN for Admin = 'ADMIN',
+// or its name for numeric and implicit values
+func enumValueName(member tsMember) string {
+	if strings.HasPrefix(member.value, "'") || strings.HasPrefix(member.value, `"`) {
+		return strings.Trim(member.value, `'"`)
+	}
+	return member.name
+}
+
+// Return the name of a property or enum member from its key, unquoting string literal keys such as 'delete'
+func unquoteKey(key string) string {
+	if len(key) >= 2 && key[0] == '\'' && key[len(key)-1] == '\'' {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+// Return the SDL of a field with its arguments, e.g. `posts(first: Float): [Post!]!`
+func (r *reverseSDL) field(owner string, member tsMember) string {
+	if member.name == "__typename" {
+		return ""
+	}
+	if !graphqlNamePattern.MatchString(member.name) {
+		r.warnf("%s.%s: not a valid GraphQL field name, skipped", owner, member.name)
+		return ""
+	}
+	field := member.name
+	if args, found := r.arguments[owner+"."+member.name]; found && len(args.members) > 0 {
+		arguments := make([]string, 0, len(args.members))
+		for _, arg := range args.members {
+			arguments = append(arguments, arg.name+": "+r.memberType(owner+"."+member.name+"("+arg.name+":)", arg))
+		}
+		field += "(" + strings.Join(arguments, ", ") + ")"
+	}
+	return field + ": " + r.memberType(owner+"."+member.name, member)
+}
+
+// Return the GraphQL type of a property, nullable when the property is optional
+func (r *reverseSDL) memberType(path string, member tsMember) string {
+	tsType, optional := strings.CutPrefix(member.signature, "?")
+	return r.graphqlType(path, member.name, strings.TrimPrefix(tsType, ": "), optional)
+}
+
+// Return the GraphQL type of a TypeScript type, e.g. [User!] for Nullable<Array<User>>. Unions with null
+// and undefined, and the Nullable, Maybe and InputMaybe helpers, make the type nullable.
+func (r *reverseSDL) graphqlType(path string, field string, tsType string, nullable bool) string {
+	var types []string
+	for _, part := range splitTypeUnion(tsType) {
+		switch part {
+		case "null", "undefined":
+			nullable = true
+		default:
+			types = append(types, part)
+		}
+	}
+
+	var graphqlType string
+	switch {
+	case len(types) == 1:
+		graphqlType = r.namedType(path, field, types[0], &nullable)
+	case len(types) > 1 && allStringLiterals(types):
+		graphqlType = "String"
+	default:
+		r.warnf("%s: unsupported type %s, converted to JSON", path, tsType)
+		r.useScalar("JSON")
+		graphqlType = "JSON"
+	}
+	if !nullable {
+		graphqlType += "!"
+	}
+	return graphqlType
+}
+
+// Return the GraphQL type of a TypeScript type without null and undefined, setting nullable for the
+// nullable helpers
+func (r *reverseSDL) namedType(path string, field string, tsType string, nullable *bool) string {
+	for _, helper := range []string{"Nullable", "Maybe", "InputMaybe"} {
+		if inner, found := typeArgument(tsType, helper); found {
+			*nullable = true
+			return strings.TrimSuffix(r.graphqlType(path, field, inner, true), "!")
+		}
+	}
+	for _, list := range []string{"Array", "ReadonlyArray"} {
+		if inner, found := typeArgument(tsType, list); found {
+			return "[" + r.graphqlType(path, field, inner, false) + "]"
+		}
+	}
+	if inner, found := strings.CutSuffix(tsType, "[]"); found {
+		return "[" + r.graphqlType(path, field, strings.TrimSuffix(strings.TrimPrefix(inner, "("), ")"), false) + "]"
+	}
+	if match := tsScalarsPattern.FindStringSubmatch(tsType); match != nil {
+		r.useScalar(match[1])
+		return match[1]
+	}
+	if strings.HasPrefix(tsType, "Record<") {
+		r.useScalar("JSON")
+		return "JSON"
+	}
+	if allStringLiterals([]string{tsType}) {
+		return "String"
+	}
+	if scalar, found := reverseScalars[tsType]; found {
+		if scalar == "String" && field == "id" {
+			return "ID"
+		}
+		r.useScalar(scalar)
+		return scalar
+	}
+	if _, found := r.kinds[tsType]; found {
+		return tsType
+	}
+	if graphqlNamePattern.MatchString(tsType) {
+		// Type declared elsewhere, such as a branded type or an imported class
+		r.useScalar(tsType)
+		return tsType
+	}
+	r.warnf("%s: unsupported type %s, converted to JSON", path, tsType)
+	r.useScalar("JSON")
+	return "JSON"
+}
+
+// Record a scalar to declare, unless it is built in
+func (r *reverseSDL) useScalar(name string) {
+	if !builtinScalars[name] {
+		r.scalars[name] = true
+	}
+}
+
+func (r *reverseSDL) warnf(format string, args ...any) {
+	r.warnings = append(r.warnings, fmt.Sprintf(format, args...))
+}
+
+// Return the type argument of a generic type, e.g. User for Array<User>
+func typeArgument(tsType string, generic string) (string, bool) {
+	inner, found := strings.CutPrefix(tsType, generic+"<")
+	if !found || !strings.HasSuffix(inner, ">") {
+		return "", false
+	}
+	inner = strings.TrimSuffix(inner, ">")
+	if depth := strings.Count(inner, "<") - strings.Count(inner, ">"); depth != 0 {
+		return "", false
+	}
+	return strings.TrimSpace(inner), true
+}
+
+// Split a TypeScript type into the members of its top-level union, e.g. Array<A | B> | null into Array<A | B> and null
+func splitTypeUnion(tsType string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range tsType {
+		switch c {
+		case '<', '(', '{', '[':
+			depth++
+		case '>', ')', '}', ']':
+			depth--
+		case '|':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(tsType[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, strings.TrimSpace(tsType[start:]))
+	if len(parts) == 1 && strings.HasPrefix(parts[0], "(") && strings.HasSuffix(parts[0], ")") {
+		return splitTypeUnion(parts[0][1 : len(parts[0])-1])
+	}
+	return parts
+}
+
+func allStringLiterals(types []string) bool {
+	for _, tsType := range types {
+		if len(tsType) < 2 || tsType[0] != '\'' || tsType[len(tsType)-1] != '\'' {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the SDL description of a definition or field, as a block string if it spans several lines
+func sdlDescription(text string, indent string) string {
+	if text == "" {
+		return ""
+	}
+	if !strings.ContainsAny(text, "\n\"\\") {
+		return indent + `"` + text + `"` + "\n"
+	}
+	lines := strings.Split(strings.ReplaceAll(text, `"""`, `\"""`), "\n")
+	return indent + `"""` + "\n" + indent + strings.Join(lines, "\n"+indent) + "\n" + indent + `"""` + "\n"
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"graphql-ts-generator/generator"
+)
+
+// Largest SDL accepted by the serve mode
+const maxServeSchemaSize = 10 << 20
+
+// Diagnostics of a generation, as returned by GET /diagnostics
+type serveDiagnostics struct {
+	Time     time.Time             `json:"time"`
+	Target   string                `json:"target"`
+	Error    string                `json:"error,omitempty"`
+	Warnings []serveWarning        `json:"warnings"`
+	Lint     []serveLintDiagnostic `json:"lint"`
+}
+
+type serveWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type serveLintDiagnostic struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Position string `json:"position"`
+	Message  string `json:"message"`
+}
+
+// Generator server for editors and playgrounds. Requests are served one at a time.
+type generateServer struct {
+	schemaOpts *schemaFlags
+	mu         sync.Mutex
+	last       *serveDiagnostics
+}
+
+func registerServeFlags(flags *flag.FlagSet) (*schemaFlags, *string, *string) {
+	schemaOpts := registerSchemaFlags(flags)
+	return schemaOpts, flags.String("addr", "localhost:4070", "Address the server listens on"),
+		flags.String("pprof", "", "Address serving the pprof endpoints, e.g. localhost:6060")
+}
+
+// Serve the generator over HTTP until interrupted
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	schemaOpts, addr, pprofAddr := registerServeFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		log.Fatal(err)
+	}
+	// The options are checked once at startup, so that requests only fail on their schema
+	if _, err := schemaOpts.newGenerator(); err != nil {
+		log.Fatal(err)
+	}
+	servePprof(*pprofAddr, schemaOpts.console)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	server := &http.Server{Addr: *addr, Handler: newGenerateServer(schemaOpts)}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	fmt.Printf("Serving on http://%s (POST /generate, GET /diagnostics)\n", *addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Error serving: %v", err)
+	}
+}
+
+func newGenerateServer(schemaOpts *schemaFlags) http.Handler {
+	s := &generateServer{schemaOpts: schemaOpts}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/generate", s.generate)
+	mux.HandleFunc("/diagnostics", s.diagnostics)
+	return mux
+}
+
+// Generate the output of the SDL in the request body, for the target given by the target query
+// parameter (typescript by default). Invalid schemas are answered with 422 and the error.
+func (s *generateServer) generate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	sdl, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxServeSchemaSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not read schema: %v", err), http.StatusRequestEntityTooLarge)
+		return
+	}
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		target = generator.TargetTypescript
+	}
+
+	ctx := r.Context()
+	if s.schemaOpts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.schemaOpts.timeout)
+		defer cancel()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &serveDiagnostics{Time: time.Now().UTC(), Target: target, Warnings: []serveWarning{}, Lint: []serveLintDiagnostic{}}
+	s.last = result
+
+	var output bytes.Buffer
+	gen, err := s.schemaOpts.newGenerator(generator.WithTarget(target))
+	if err == nil {
+		err = gen.AddSource(ctx, "schema.graphql", string(sdl), "")
+	}
+	if err == nil {
+		for _, warning := range gen.Warnings() {
+			result.Warnings = append(result.Warnings, serveWarning{warning.Code, warning.Message})
+		}
+		for _, issue := range gen.Lint() {
+			result.Lint = append(result.Lint, serveLintDiagnostic{issue.Rule, string(issue.Severity), issue.Position, issue.Message})
+		}
+		err = gen.Emit(ctx, &output)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(output.Bytes())
+}
+
+// Return the diagnostics of the last generation, or 404 before the first one
+func (s *generateServer) diagnostics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.last == nil {
+		http.Error(w, "no generation yet", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.last)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Lines of unchanged context shown around each difference
+const diffContext = 3
+
+func registerSnapshotFlags(flags *flag.FlagSet) (*console, *string, *string, *bool) {
+	out := &console{}
+	flags.BoolVar(&out.noColor, "no-color", false, "Disable colored output, also disabled by the NO_COLOR environment variable or when not writing to a terminal")
+	return out, flags.String("fixtures", "./fixtures", "Directory with one subdirectory of schema files and golden file per case"),
+		flags.String("golden", "expected.ts", "Name of the golden file in each case directory"),
+		flags.Bool("update", false, "Write the generated output to the golden files instead of comparing")
+}
+
+// Generate the output of every fixture case and compare it with the committed golden file.
+// A case is a subdirectory of the fixtures directory holding schema files, the golden file,
+// and optionally a config.json with the generate options of the case.
+func runSnapshotTest(args []string) {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	out, fixturesDir, golden, update := registerSnapshotFlags(flags)
+	flags.Parse(args)
+	log.SetOutput(errorWriter{os.Stderr, out})
+
+	cases, err := fixtureCases(*fixturesDir)
+	if err != nil {
+		log.Fatalf("Error reading fixtures: %v", err)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Error running fixtures: %v", err)
+	}
+	tmpDir, err := os.MkdirTemp("", "graphql-ts-generator-test")
+	if err != nil {
+		log.Fatalf("Error running fixtures: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	failLabel := out.colorize(os.Stdout, colorRed, "FAIL")
+	failed := 0
+	for _, dir := range cases {
+		name := filepath.Base(dir)
+		outputPath := filepath.Join(tmpDir, name+filepath.Ext(*golden))
+		if err := generateFixture(executable, dir, outputPath); err != nil {
+			fmt.Printf("%s %s: %v\n", failLabel, name, err)
+			failed++
+			continue
+		}
+		actual, err := os.ReadFile(outputPath)
+		if err != nil {
+			log.Fatalf("Error reading output: %v", err)
+		}
+
+		goldenPath := filepath.Join(dir, *golden)
+		if *update {
+			if err := os.WriteFile(goldenPath, actual, 0644); err != nil {
+				log.Fatalf("Error writing golden file: %v", err)
+			}
+			fmt.Printf("UPDATED %s\n", name)
+			continue
+		}
+
+		expected, err := os.ReadFile(goldenPath)
+		if err != nil {
+			fmt.Printf("%s %s: %v (run with -update to create it)\n", failLabel, name, err)
+			failed++
+			continue
+		}
+		if diff := lineDiff(string(expected), string(actual)); diff != "" {
+			fmt.Printf("%s %s: output differs from %s\n%s", failLabel, name, goldenPath, diff)
+			failed++
+			continue
+		}
+		fmt.Printf("%s   %s\n", out.colorize(os.Stdout, colorGreen, "ok"), name)
+	}
+
+	if failed > 0 {
+		log.Fatalf("%d of %d fixture(s) failed", failed, len(cases))
+	}
+	if *update {
+		fmt.Printf("Updated %d golden file(s).\n", len(cases))
+		return
+	}
+	out.printSuccess("All %d fixture(s) passed.", len(cases))
+}
+
+// List the case directories of the fixtures directory, in name order
+func fixtureCases(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var cases []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			cases = append(cases, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(cases)
+	return cases, nil
+}
+
+// Generate the output of one case with a child process, so that each case runs with its own options
+func generateFixture(executable, dir, outputPath string) error {
+	args := []string{"generate", "-input", dir, "-output", outputPath, "-content-hash=false"}
+	if config := filepath.Join(dir, "config.json"); fileExists(config) {
+		args = append(args, "-config", config)
+	}
+	out, err := exec.Command(executable, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v\n%s", err, out)
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Return a unified-style diff of the lines of two texts, or "" if they are equal
+func lineDiff(expected, actual string) string {
+	if expected == actual {
+		return ""
+	}
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+
+	// Longest common subsequence table, lcs[i][j] is the length for a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte
+		text string
+		line int // line number in the expected text
+	}
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i], i + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', a[i], i + 1})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j], i + 1})
+			j++
+		}
+	}
+
+	// Keep the changed lines and their context, with a header at the start of each hunk
+	var out strings.Builder
+	last := -1
+	for k, l := range lines {
+		near := false
+		for d := max(0, k-diffContext); d <= min(len(lines)-1, k+diffContext); d++ {
+			if lines[d].op != ' ' {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+		if last != k-1 {
+			fmt.Fprintf(&out, "@@ line %d @@\n", l.line)
+		}
+		fmt.Fprintf(&out, "%c %s\n", l.op, l.text)
+		last = k
+	}
+	return out.String()
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"log"
+)
+
+// Check the schema files and run the lint rules without generating any output
+func runValidate(args []string) {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	schemaOpts := registerSchemaFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := schemaOpts.context()
+	defer cancel()
+
+	gen, err := schemaOpts.loadSchema(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failingCount := schemaOpts.reportWarnings(gen)
+	errorCount, failingLintCount := schemaOpts.reportLint(gen)
+	if errorCount > 0 {
+		log.Fatalf("Schema validation failed: %d lint error(s) found", errorCount)
+	}
+	if failingCount += failingLintCount; failingCount > 0 {
+		log.Fatalf("Schema validation failed: %d warning(s) found with -fail-on-warn", failingCount)
+	}
+
+	schemaOpts.console.printSuccess("Schema validation completed.")
+}
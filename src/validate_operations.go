@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"graphql-ts-generator/generator"
+)
+
+func registerValidateOperationsFlags(flags *flag.FlagSet) (*schemaFlags, *string) {
+	schemaOpts := registerSchemaFlags(flags)
+	operations := flags.String("operations", "./operations", "Directory of the .graphql operation documents to check, searched recursively")
+	return schemaOpts, operations
+}
+
+// Check the client operation documents against the merged schema and report each problem at its position
+func runValidateOperations(args []string) {
+	flags := flag.NewFlagSet("validate-operations", flag.ExitOnError)
+	schemaOpts, operations := registerValidateOperationsFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := schemaOpts.context()
+	defer cancel()
+
+	gen, err := schemaOpts.loadSchema(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sources, err := readOperations(*operations)
+	if err != nil {
+		log.Fatalf("Error reading operations: %v", err)
+	}
+	problems, err := gen.ValidateOperations(ctx, sources)
+	if err != nil {
+		log.Fatalf("Error validating operations: %v", err)
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			schemaOpts.console.printError(problem)
+		}
+		log.Fatalf("Operation validation failed: %d error(s) found in %d document(s)", len(problems), len(sources))
+	}
+
+	schemaOpts.console.printSuccess("Operation validation completed: %d document(s) checked.", len(sources))
+}
+
+// Read the .graphql files of a directory and its subdirectories, skipping node_modules
+func readOperations(dir string) ([]generator.OperationSource, error) {
+	var sources []generator.OperationSource
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == "node_modules" {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".graphql") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, generator.OperationSource{Name: path, Content: string(content)})
+		return nil
+	})
+	return sources, err
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Settings of the watch mode
+type watchOptions struct {
+	// How often the schema files are checked for changes
+	interval time.Duration
+	// How long the files must stay unchanged before generating, so that a burst of saves runs once
+	debounce time.Duration
+	// Shell commands run after each successful or failed generation
+	onSuccess string
+	onFailure string
+	// Address of the pprof endpoints, disabled if empty
+	pprofAddr string
+}
+
+// Regenerate whenever the schema files or the config file change, until interrupted.
+// The rebuilds run the generate command again in process, sharing the parsed schema files, and a failure
+// ends the rebuild instead of the watch.
+func runWatch(ctx context.Context, opts watchOptions, schemaOpts *schemaFlags, args []string, run *generateRun) {
+	servePprof(opts.pprofAddr, run.console)
+	rebuild := &generateRun{
+		args:     append(append([]string{}, run.args...), "-watch=false"),
+		defaults: run.defaults, parseCache: run.parseCache, console: run.console,
+	}
+	config := configPath(args)
+	snapshot := func() string {
+		// URL and registry schemas are fetched again, through the remote cache, to notice their changes
+		schemaOpts.sources = make(map[string][]byte)
+		files, err := schemaOpts.collectFiles(ctx)
+		if config != "" {
+			files = append(files, config)
+		}
+		state := fileSnapshot(files, schemaOpts.sources)
+		// The rebuild reports the error, e.g. of an input directory missing during a checkout
+		if err != nil {
+			state += "error: " + err.Error() + "\n"
+		}
+		return state
+	}
+	generate := func() {
+		hook := opts.onSuccess
+		if err := rebuild.generate(); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			run.console.printError(fmt.Sprintf("Generation failed: %v", err))
+			hook = opts.onFailure
+		}
+		if hook != "" {
+			if err := runHook(hook, nil); err != nil {
+				run.console.printError(err)
+			}
+		}
+		fmt.Printf("Watching %s for changes...\n", schemaOpts.inputDir)
+	}
+
+	watchLoop(ctx, opts, snapshot, generate)
+}
+
+// Run generate once, then again after every change of the snapshot once it stayed
+// the same for the debounce window. Returns when the context is cancelled.
+func watchLoop(ctx context.Context, opts watchOptions, snapshot func() string, generate func()) {
+	last := snapshot()
+	generate()
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	var pending string
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			current := snapshot()
+			if current != pending {
+				pending, changedAt = current, now
+			}
+			if pending != last && now.Sub(changedAt) >= opts.debounce {
+				last = pending
+				generate()
+			}
+		}
+	}
+}
+
+// Describe the files by path, size and modification time, to detect changes without reading them.
+// Downloaded schemas are described by the hash of their content.
+func fileSnapshot(files []string, sources map[string][]byte) string {
+	sort.Strings(files)
+	var b strings.Builder
+	for _, path := range files {
+		if data, found := sources[path]; found {
+			fmt.Fprintf(&b, "%s %s\n", path, hashContent(data))
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(&b, "%s missing\n", path)
+			continue
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}